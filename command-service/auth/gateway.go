@@ -0,0 +1,18 @@
+package auth
+
+import "crypto/subtle"
+
+// GatewaySecretHeader carries the secret rest-gateway shares with the
+// services, gRPC calls send it as x-gateway-secret metadata.
+const GatewaySecretHeader = "X-Gateway-Secret"
+
+// TrustsGateway reports whether sent matches the secret shared with the
+// gateway. Without a secret every caller is trusted and the service relies on
+// only being reachable through the gateway.
+func TrustsGateway(secret string, sent string) bool {
+	if secret == "" {
+		return true
+	}
+
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(sent)) == 1
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Principal is the caller verified by rest-gateway. The gateway forwards the
+// token subject and roles in the X-Auth-Subject and X-Auth-Roles headers, or
+// the x-auth-subject and x-auth-roles metadata of a gRPC call.
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+// PrincipalFromRequest reads the identity headers set by the gateway.
+func PrincipalFromRequest(r *http.Request) (Principal, bool) {
+	return ParsePrincipal(r.Header.Get("X-Auth-Subject"), r.Header.Get("X-Auth-Roles"))
+}
+
+// ParsePrincipal builds the principal from a subject and a comma separated
+// list of roles, it reports false without a subject.
+func ParsePrincipal(subject string, roles string) (Principal, bool) {
+	if subject == "" {
+		return Principal{}, false
+	}
+
+	principal := Principal{Subject: subject}
+	for _, role := range strings.Split(roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			principal.Roles = append(principal.Roles, role)
+		}
+	}
+
+	return principal, true
+}
+
+func (p Principal) HasRole(roles ...string) bool {
+	for _, have := range p.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
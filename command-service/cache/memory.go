@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// cacheMemory keeps the entries in a map, expired entries are dropped when
+// they are read.
+type cacheMemory struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+func NewCacheMemory() Cache {
+	return &cacheMemory{
+		entries: map[string]memoryEntry{},
+	}
+}
+
+func (c *cacheMemory) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, ErrMiss
+	}
+
+	return entry.value, nil
+}
+
+func (c *cacheMemory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := memoryEntry{value: append([]byte{}, value...)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	c.entries[key] = entry
+
+	return nil
+}
+
+func (c *cacheMemory) Del(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+
+	return nil
+}
@@ -2,241 +2,627 @@ package command
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Adhiana46/command-service/cache"
 	"github.com/Adhiana46/command-service/dto"
 	"github.com/Adhiana46/command-service/event"
 	"github.com/Adhiana46/command-service/model"
-	sq "github.com/Masterminds/squirrel"
 	"github.com/go-playground/validator/v10"
 	"github.com/go-redis/redis/v9"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 const (
-	articleCreatedEvent = "article.created"
-	articleUpdatedEvent = "article.updated"
-	articleDeletedEvent = "article.deleted"
+	articleCreatedEvent  = "article.created"
+	articleUpdatedEvent  = "article.updated"
+	articleDeletedEvent  = "article.deleted"
+	articleRestoredEvent = "article.restored"
+	articlePurgedEvent   = "article.purged"
+
+	articleSubmittedEvent = "article.submitted"
+	articlePublishedEvent = "article.published"
+	articleArchivedEvent  = "article.archived"
+
+	articlePublishScheduledEvent = "article.publish_scheduled"
+	articlePublishCancelledEvent = "article.publish_cancelled"
+
+	articleSlugChangedEvent = "article.slug_changed"
+
+	// PublishArticleJob is the scheduled job that publishes an article
+	PublishArticleJob = "article.publish"
+
+	purgeBatchSize = 100
 )
 
 type ArticleCommand interface {
-	PushToQueue(ctx context.Context, eventName string, article *model.Article) error
 	Store(ctx context.Context, reqDto dto.RequestStoreArticle) (*model.Article, error)
 	Update(ctx context.Context, reqDto dto.RequestUpdateArticle) (*model.Article, error)
 	Delete(ctx context.Context, reqDto dto.RequestDeleteArticle) (*model.Article, error)
+	Restore(ctx context.Context, reqDto dto.RequestRestoreArticle) (*model.Article, error)
+	// Submit, Publish and Archive move an article through the editorial
+	// workflow, only editors can publish.
+	Submit(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error)
+	Publish(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error)
+	Archive(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error)
+	// Schedule publishes a draft or an article in review later, only editors
+	// can schedule. CancelSchedule drops the scheduled publication.
+	Schedule(ctx context.Context, reqDto dto.RequestScheduleArticle) (*model.Article, error)
+	CancelSchedule(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error)
+	// PublishScheduled runs the publish job of an article, it reports false
+	// when there was nothing left to publish.
+	PublishScheduled(ctx context.Context, uuid string) (bool, error)
+	// Revert writes a new revision with the title and body of an older one.
+	Revert(ctx context.Context, reqDto dto.RequestRevertArticle) (*model.Article, error)
+	// PurgeTrash removes the articles deleted before deletedBefore for good
+	// and returns how many were purged.
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) (int, error)
+	// Bulk applies a batch of create, update and delete operations and
+	// reports the outcome of each.
+	Bulk(ctx context.Context, reqDto dto.RequestBulkArticle) ([]BulkResult, error)
 }
 
-type articleCommandPg struct {
-	db         *sqlx.DB
-	rabbitConn *amqp.Connection
-	rds        *redis.Client
+// articleCommand handles the article commands on top of an event repository,
+// the single article cache is refreshed after every successful save.
+type articleCommand struct {
+	repo  ArticleRepository
+	cache cache.Cache
 }
 
-func NewArticleCommandPg(db *sqlx.DB, rabbitConn *amqp.Connection, rds *redis.Client) ArticleCommand {
-	return &articleCommandPg{
-		db:         db,
-		rabbitConn: rabbitConn,
-		rds:        rds,
+func NewArticleCommand(repo ArticleRepository, c cache.Cache) ArticleCommand {
+	return &articleCommand{
+		repo:  repo,
+		cache: c,
 	}
 }
 
-func (c *articleCommandPg) PushToQueue(ctx context.Context, eventName string, article *model.Article) error {
-	emitter, err := event.NewEventEmitter(c.rabbitConn, "articles")
+func NewArticleCommandPg(db *sqlx.DB, rds *redis.Client) ArticleCommand {
+	return NewArticleCommand(NewArticleRepositoryPg(db), cache.NewCacheRedis(rds))
+}
+
+// refreshCache keeps the single article cache in sync right after a commit,
+// errors are ignored because the projection will overwrite it anyway.
+func (c *articleCommand) refreshCache(ctx context.Context, eventName string, article *model.Article) {
+	// the staged commands of a bulk batch have no cache, the batch refreshes
+	// it once saved
+	if c.cache == nil {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+	switch eventName {
+	case articleCreatedEvent, articleUpdatedEvent, articleRestoredEvent,
+		articleSubmittedEvent, articlePublishedEvent, articleArchivedEvent,
+		articlePublishScheduledEvent, articlePublishCancelledEvent, articleSlugChangedEvent:
+		articleJson, err := json.Marshal(article)
+		if err == nil {
+			c.cache.Set(ctx, cacheKey, articleJson, 10*time.Minute)
+		}
+	case articleDeletedEvent, articlePurgedEvent:
+		c.cache.Del(ctx, cacheKey)
+	}
+}
+
+// findArticle rebuilds the article aggregate, deleted or unknown articles are
+// reported as sql.ErrNoRows.
+func (c *articleCommand) findArticle(ctx context.Context, uuid string) (*Article, error) {
+	article, err := c.findArticleInTrash(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.Deleted {
+		return nil, sql.ErrNoRows
+	}
+
+	return article, nil
+}
+
+// findArticleInTrash rebuilds the article aggregate including a deleted one,
+// only unknown and purged articles are reported as sql.ErrNoRows.
+func (c *articleCommand) findArticleInTrash(ctx context.Context, uuid string) (*Article, error) {
+	events, err := c.repo.Load(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// the uuid of a comment is no article either
+	if len(events) == 0 || events[0].AggregateType != articleAggregateType {
+		return nil, sql.ErrNoRows
+	}
+
+	article, err := LoadArticle(events)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.Purged {
+		return nil, sql.ErrNoRows
+	}
+
+	return article, nil
+}
+
+// save appends the pending events of the aggregate together with the
+// envelopes that announce them.
+func (c *articleCommand) save(ctx context.Context, article *Article) error {
+	metadata, err := json.Marshal(event.MetadataFromContext(ctx))
 	if err != nil {
 		return err
 	}
+	article.setMetadata(metadata)
+
+	envelopes := []event.Envelope{}
+	snapshots := article.Snapshots()
+	for i, e := range article.Changes() {
+		envelope, err := newEnvelope(e, snapshots[i])
+		if err != nil {
+			return err
+		}
+		envelopes = append(envelopes, envelope)
+	}
 
-	jsonPayload, err := json.Marshal(article)
+	err = c.repo.Save(ctx, article.Uuid, article.LoadedVersion(), article.Changes(), envelopes)
 	if err != nil {
 		return err
 	}
 
-	// Redis
-	cacheKey := fmt.Sprintf("article-%s", article.Uuid)
-	switch eventName {
-	case articleCreatedEvent:
-		c.rds.Set(ctx, cacheKey, article, 10*time.Minute)
-	case articleUpdatedEvent:
-		c.rds.Set(ctx, cacheKey, article, 10*time.Minute)
-	case articleDeletedEvent:
-		c.rds.Del(ctx, cacheKey)
+	for _, e := range article.Changes() {
+		c.refreshCache(ctx, e.EventType, article.ToModel())
+	}
+
+	return nil
+}
+
+// newEnvelope wraps the aggregate snapshot of an event in the envelope that
+// is published for it.
+func newEnvelope(e model.Event, snapshot any) (event.Envelope, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return event.Envelope{}, err
 	}
 
-	return emitter.Push(eventName, jsonPayload)
+	var metadata event.Metadata
+	if err := json.Unmarshal(e.Metadata, &metadata); err != nil {
+		return event.Envelope{}, err
+	}
+
+	return event.Envelope{
+		EventID:          e.EventID,
+		EventType:        e.EventType,
+		AggregateID:      e.AggregateID,
+		AggregateType:    e.AggregateType,
+		AggregateVersion: e.Sequence,
+		OccurredAt:       e.CreatedAt,
+		CorrelationID:    metadata.CorrelationID,
+		CausationID:      metadata.CausationID,
+		Producer:         event.ProducerName,
+		SchemaVersion:    event.SchemaVersion,
+		Data:             data,
+	}, nil
 }
 
-func (c *articleCommandPg) findByUuid(ctx context.Context, uuid string) (*model.Article, error) {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	sql, args, err := psql.Select("*").
-		From("articles").
-		Where(sq.Eq{"uuid": uuid}).
-		ToSql()
+func (c *articleCommand) Store(ctx context.Context, reqDto dto.RequestStoreArticle) (*model.Article, error) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reqDto.Author = principal.Subject
+
+	validate := validator.New()
 
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	if reqDto.PublishAt != nil {
+		if err := checkPublishAt(ctx, *reqDto.PublishAt); err != nil {
+			return nil, err
+		}
+	}
+
+	articleID := uuid.NewString()
+
+	slug, err := c.slugFor(ctx, articleID, reqDto.Title)
 	if err != nil {
 		return nil, err
 	}
 
-	row := model.Article{}
-	err = c.db.GetContext(ctx, &row, sql, args...)
+	article, err := NewArticle(articleID, slug, reqDto.Author, reqDto.Title, reqDto.Body, reqDto.Category, reqDto.Tags)
 	if err != nil {
 		return nil, err
 	}
 
-	return &row, nil
+	if reqDto.PublishAt != nil {
+		if err := article.SchedulePublish(*reqDto.PublishAt); err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.save(ctx, article)
+	if err != nil {
+		return nil, err
+	}
+
+	return article.ToModel(), nil
 }
 
-func (c *articleCommandPg) Store(ctx context.Context, reqDto dto.RequestStoreArticle) (*model.Article, error) {
+func (c *articleCommand) Update(ctx context.Context, reqDto dto.RequestUpdateArticle) (*model.Article, error) {
 	validate := validator.New()
 
 	if err := validate.Struct(reqDto); err != nil {
 		return nil, err
 	}
 
-	values := map[string]interface{}{
-		"uuid":       uuid.NewString(),
-		"author":     reqDto.Author,
-		"title":      reqDto.Title,
-		"body":       reqDto.Body,
-		"created_at": time.Now(),
-		"updated_at": time.Now(),
+	article, err := c.findArticle(ctx, reqDto.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeChange(ctx, article); err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != article.Version {
+		return nil, ErrVersionMismatch
 	}
 
-	tx, err := c.db.Begin()
+	if reqDto.PublishAt != nil {
+		if err := checkPublishAt(ctx, *reqDto.PublishAt); err != nil {
+			return nil, err
+		}
+	}
+
+	principal, _ := currentPrincipal(ctx)
+
+	retitled := article.Title != reqDto.Title
+
+	// the author stays the owner of the article
+	err = article.Update(article.Author, reqDto.Title, reqDto.Body, principal.Subject, reqDto.Category, reqDto.Tags)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build Sql
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	sql, args, err := psql.Insert("articles").
-		SetMap(values).
-		ToSql()
+	if err := c.followTitle(ctx, article, retitled); err != nil {
+		return nil, err
+	}
+
+	if reqDto.PublishAt != nil {
+		if err := article.SchedulePublish(*reqDto.PublishAt); err != nil {
+			return nil, err
+		}
+	}
 
+	err = c.save(ctx, article)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = tx.ExecContext(ctx, sql, args...)
+	return article.ToModel(), nil
+}
+
+func (c *articleCommand) Delete(ctx context.Context, reqDto dto.RequestDeleteArticle) (*model.Article, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
 
+	article, err := c.findArticle(ctx, reqDto.Uuid)
 	if err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
-	tx.Commit()
+	if err := authorizeChange(ctx, article); err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != article.Version {
+		return nil, ErrVersionMismatch
+	}
 
-	article, err := c.findByUuid(ctx, values["uuid"].(string))
+	err = article.Delete()
 	if err != nil {
 		return nil, err
 	}
 
-	// Push
-	err = c.PushToQueue(ctx, articleCreatedEvent, article)
+	err = c.save(ctx, article)
 	if err != nil {
 		return nil, err
 	}
 
-	return article, nil
+	return article.ToModel(), nil
 }
 
-func (c *articleCommandPg) Update(ctx context.Context, reqDto dto.RequestUpdateArticle) (*model.Article, error) {
+func (c *articleCommand) Restore(ctx context.Context, reqDto dto.RequestRestoreArticle) (*model.Article, error) {
 	validate := validator.New()
 
 	if err := validate.Struct(reqDto); err != nil {
 		return nil, err
 	}
 
-	values := map[string]interface{}{
-		"author":     reqDto.Author,
-		"title":      reqDto.Title,
-		"body":       reqDto.Body,
-		"updated_at": time.Now(),
+	article, err := c.findArticleInTrash(ctx, reqDto.Uuid)
+	if err != nil {
+		return nil, err
 	}
 
-	tx, err := c.db.Begin()
-	if err != nil {
+	if err := authorizeChange(ctx, article); err != nil {
 		return nil, err
 	}
 
-	// Build Sql
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	sql, args, err := psql.Update("articles").
-		SetMap(values).
-		Where(sq.Eq{"uuid": reqDto.Uuid}).
-		ToSql()
+	if reqDto.Version != 0 && reqDto.Version != article.Version {
+		return nil, ErrVersionMismatch
+	}
 
+	err = article.Restore()
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = tx.ExecContext(ctx, sql, args...)
+	err = c.save(ctx, article)
+	if err != nil {
+		return nil, err
+	}
+
+	return article.ToModel(), nil
+}
 
+func (c *articleCommand) Submit(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error) {
+	return c.transition(ctx, reqDto, authorizeChange, (*Article).Submit)
+}
+
+func (c *articleCommand) Publish(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error) {
+	authorize := func(ctx context.Context, article *Article) error {
+		return authorizeEditor(ctx)
+	}
+
+	return c.transition(ctx, reqDto, authorize, (*Article).Publish)
+}
+
+func (c *articleCommand) Archive(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error) {
+	return c.transition(ctx, reqDto, authorizeChange, (*Article).Archive)
+}
+
+// transition loads the article, checks the caller and the expected version
+// and saves the event recorded by move.
+func (c *articleCommand) transition(
+	ctx context.Context,
+	reqDto dto.RequestTransitionArticle,
+	authorize func(context.Context, *Article) error,
+	move func(*Article) error,
+) (*model.Article, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	article, err := c.findArticle(ctx, reqDto.Uuid)
 	if err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
-	tx.Commit()
+	if err := authorize(ctx, article); err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != article.Version {
+		return nil, ErrVersionMismatch
+	}
 
-	article, err := c.findByUuid(ctx, reqDto.Uuid)
+	err = move(article)
 	if err != nil {
 		return nil, err
 	}
 
-	// Push
-	err = c.PushToQueue(ctx, articleUpdatedEvent, article)
+	err = c.save(ctx, article)
 	if err != nil {
 		return nil, err
 	}
 
-	return article, nil
+	return article.ToModel(), nil
 }
 
-func (c *articleCommandPg) Delete(ctx context.Context, reqDto dto.RequestDeleteArticle) (*model.Article, error) {
+func (c *articleCommand) Schedule(ctx context.Context, reqDto dto.RequestScheduleArticle) (*model.Article, error) {
 	validate := validator.New()
 
 	if err := validate.Struct(reqDto); err != nil {
 		return nil, err
 	}
 
-	article, err := c.findByUuid(ctx, reqDto.Uuid)
+	article, err := c.findArticle(ctx, reqDto.Uuid)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := c.db.Begin()
+	if err := checkPublishAt(ctx, reqDto.PublishAt); err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != article.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	err = article.SchedulePublish(reqDto.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.save(ctx, article)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build Sql
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	sql, args, err := psql.Delete("articles").
-		Where(sq.Eq{"uuid": reqDto.Uuid}).
-		ToSql()
+	return article.ToModel(), nil
+}
+
+func (c *articleCommand) CancelSchedule(ctx context.Context, reqDto dto.RequestTransitionArticle) (*model.Article, error) {
+	return c.transition(ctx, reqDto, authorizeChange, (*Article).CancelPublish)
+}
+
+// PublishScheduled is run by the scheduler, not on behalf of a caller. The
+// job is only asked for once the article was scheduled by an editor.
+func (c *articleCommand) PublishScheduled(ctx context.Context, uuid string) (bool, error) {
+	article, err := c.findArticle(ctx, uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		// deleted or purged meanwhile, there is nothing left to publish
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// a job that runs early fails with ErrPublishNotDue and is tried again
+	published, err := article.PublishDue(time.Now().UTC())
+	if err != nil || !published {
+		return false, err
+	}
+
+	// a conflict fails the job, it runs again against the new version
+	err = c.save(ctx, article)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// slugFor derives a slug from the title that no other article holds, a slug
+// the article held before can be taken again.
+func (c *articleCommand) slugFor(ctx context.Context, articleID string, title string) (string, error) {
+	base := slugify(title)
 
+	taken, err := c.repo.TakenSlugs(ctx, base, articleID)
+	if err != nil {
+		return "", err
+	}
+
+	return freeSlug(base, taken), nil
+}
+
+// followTitle moves a retitled article to the slug of its new title. Articles
+// created before slugs get one on their first update.
+func (c *articleCommand) followTitle(ctx context.Context, article *Article, retitled bool) error {
+	if !retitled && article.Slug != "" {
+		return nil
+	}
+
+	slug, err := c.slugFor(ctx, article.Uuid, article.Title)
+	if err != nil {
+		return err
+	}
+
+	return article.ChangeSlug(slug)
+}
+
+// checkPublishAt allows editors to schedule a publication in the future.
+func checkPublishAt(ctx context.Context, publishAt time.Time) error {
+	if err := authorizeEditor(ctx); err != nil {
+		return err
+	}
+
+	if !publishAt.After(time.Now()) {
+		return ErrPublishAtInPast
+	}
+
+	return nil
+}
+
+func (c *articleCommand) Revert(ctx context.Context, reqDto dto.RequestRevertArticle) (*model.Article, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	article, err := c.findArticle(ctx, reqDto.Uuid)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = tx.ExecContext(ctx, sql, args...)
+	if err := authorizeChange(ctx, article); err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != article.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	events, err := c.repo.Load(ctx, reqDto.Uuid)
+	if err != nil {
+		return nil, err
+	}
 
+	revision, err := ArticleAtRevision(events, reqDto.Revision)
 	if err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
-	tx.Commit()
+	principal, _ := currentPrincipal(ctx)
+
+	retitled := article.Title != revision.Title
 
-	// Push
-	err = c.PushToQueue(ctx, articleDeletedEvent, article)
+	// a revert is an ordinary update with the old content, the tags and the
+	// category stay as they are
+	err = article.Update(article.Author, revision.Title, revision.Body, principal.Subject, article.Category, article.Tags)
 	if err != nil {
 		return nil, err
 	}
 
-	return article, nil
+	if err := c.followTitle(ctx, article, retitled); err != nil {
+		return nil, err
+	}
+
+	err = c.save(ctx, article)
+	if err != nil {
+		return nil, err
+	}
+
+	return article.ToModel(), nil
+}
+
+func (c *articleCommand) PurgeTrash(ctx context.Context, deletedBefore time.Time) (int, error) {
+	uuids, err := c.repo.FindDeleted(ctx, deletedBefore, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, uuid := range uuids {
+		article, err := c.findArticleInTrash(ctx, uuid)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+
+		// restored or deleted again since it was found
+		if !article.Deleted || article.DeletedAt.After(deletedBefore) {
+			continue
+		}
+
+		err = article.Purge()
+		if err != nil {
+			return purged, err
+		}
+
+		err = c.save(ctx, article)
+		if errors.Is(err, ErrConcurrencyConflict) {
+			// changed meanwhile, the next run looks at it again
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+
+		purged++
+	}
+
+	return purged, nil
 }
@@ -0,0 +1,507 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+	"github.com/google/uuid"
+)
+
+const articleAggregateType = "article"
+
+var (
+	ErrArticleDeleted    = errors.New("article has been deleted")
+	ErrArticleNotDeleted = errors.New("article is not in the trash")
+	ErrRevisionNotFound  = errors.New("article revision not found")
+	ErrInvalidTransition = errors.New("invalid article status transition")
+	ErrNotScheduled      = errors.New("article has no scheduled publication")
+	ErrPublishAtInPast   = errors.New("publish_at must be in the future")
+	ErrPublishNotDue     = errors.New("scheduled publication is not due yet")
+)
+
+// Creates and updates are the revisions of an article, their payload keeps
+// who wrote the revision and its number. Events stored before revisions
+// existed have neither, they are numbered in sequence order. Both carry the
+// whole set of tags and the category, events without them had none. The
+// slug is derived from the title, articles created before slugs have none.
+type articleCreatedPayload struct {
+	Slug     string   `json:"slug,omitempty"`
+	Author   string   `json:"author"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Tags     []string `json:"tags,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Editor   string   `json:"editor,omitempty"`
+	Revision int      `json:"revision,omitempty"`
+	// articles created before the editorial workflow have no status, they
+	// were published right away
+	Status string `json:"status,omitempty"`
+}
+
+type articleUpdatedPayload struct {
+	Author   string   `json:"author"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Tags     []string `json:"tags,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Editor   string   `json:"editor,omitempty"`
+	Revision int      `json:"revision,omitempty"`
+}
+
+type articleDeletedPayload struct{}
+
+type articleRestoredPayload struct{}
+
+type articlePurgedPayload struct{}
+
+type articleSubmittedPayload struct{}
+
+type articlePublishedPayload struct{}
+
+type articleArchivedPayload struct{}
+
+type articlePublishScheduledPayload struct {
+	PublishAt time.Time `json:"publish_at"`
+}
+
+type articlePublishCancelledPayload struct{}
+
+// the previous slug stays with the article, it keeps resolving to it
+type articleSlugChangedPayload struct {
+	Slug string `json:"slug"`
+}
+
+// statusTransitions is the editorial workflow, the event that moves an
+// article on and the status it has to be in.
+var statusTransitions = map[string]struct {
+	from string
+	verb string
+}{
+	articleSubmittedEvent: {from: model.StatusDraft, verb: "submitted for review"},
+	articlePublishedEvent: {from: model.StatusInReview, verb: "published"},
+	articleArchivedEvent:  {from: model.StatusPublished, verb: "archived"},
+}
+
+// Article is the event-sourced article aggregate. Its state is never stored,
+// it is rebuilt by replaying the article events in sequence order. A new
+// article is a draft that goes through review before it is published, and is
+// archived when it is retired. A deleted article stays in the trash until it
+// is restored or purged, a purged article is gone for good. A draft or an
+// article in review can be scheduled to publish itself at PublishAt. Slug is
+// the unique public name of the article, it follows the title.
+type Article struct {
+	Uuid      string
+	Slug      string
+	Author    string
+	Title     string
+	Body      string
+	Tags      []string
+	Category  string
+	Status    string
+	Deleted   bool
+	Purged    bool
+	Version   int
+	Revision  int
+	UpdatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+	PublishAt *time.Time
+
+	changes   []model.Event
+	snapshots []*model.Article // the article right after each change
+}
+
+// NewArticle starts a new aggregate with a pending article.created event.
+func NewArticle(uuid, slug, author, title, body, category string, tags []string) (*Article, error) {
+	article := &Article{Uuid: uuid}
+
+	err := article.record(articleCreatedEvent, articleCreatedPayload{
+		Slug:     slug,
+		Author:   author,
+		Title:    title,
+		Body:     body,
+		Tags:     normalizeTags(tags),
+		Category: normalizeTag(category),
+		Editor:   author,
+		Revision: 1,
+		Status:   model.StatusDraft,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// LoadArticle rebuilds an aggregate from its stored events.
+func LoadArticle(events []model.Event) (*Article, error) {
+	article := &Article{}
+
+	for _, e := range events {
+		if err := article.apply(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return article, nil
+}
+
+// ArticleAtRevision rebuilds the aggregate as it was right after revision n
+// was written.
+func ArticleAtRevision(events []model.Event, n int) (*Article, error) {
+	article := &Article{}
+
+	for _, e := range events {
+		if err := article.apply(e); err != nil {
+			return nil, err
+		}
+
+		isRevision := e.EventType == articleCreatedEvent || e.EventType == articleUpdatedEvent
+		if isRevision && article.Revision == n {
+			return article, nil
+		}
+	}
+
+	return nil, ErrRevisionNotFound
+}
+
+// Update writes a new revision, editor is whoever made the change. The tags
+// and the category are replaced as well.
+func (a *Article) Update(author, title, body, editor, category string, tags []string) error {
+	if a.Deleted || a.Purged {
+		return ErrArticleDeleted
+	}
+
+	return a.record(articleUpdatedEvent, articleUpdatedPayload{
+		Author:   author,
+		Title:    title,
+		Body:     body,
+		Tags:     normalizeTags(tags),
+		Category: normalizeTag(category),
+		Editor:   editor,
+		Revision: a.Revision + 1,
+	})
+}
+
+// ChangeSlug moves the article to a new slug, the same slug is a no-op.
+func (a *Article) ChangeSlug(slug string) error {
+	if a.Deleted || a.Purged {
+		return ErrArticleDeleted
+	}
+
+	if slug == a.Slug {
+		return nil
+	}
+
+	return a.record(articleSlugChangedEvent, articleSlugChangedPayload{Slug: slug})
+}
+
+func (a *Article) Delete() error {
+	if a.Deleted || a.Purged {
+		return ErrArticleDeleted
+	}
+
+	return a.record(articleDeletedEvent, articleDeletedPayload{})
+}
+
+// Restore takes the article out of the trash.
+func (a *Article) Restore() error {
+	if a.Purged {
+		return ErrArticleDeleted
+	}
+	if !a.Deleted {
+		return ErrArticleNotDeleted
+	}
+
+	return a.record(articleRestoredEvent, articleRestoredPayload{})
+}
+
+// Purge removes an article from the trash for good.
+func (a *Article) Purge() error {
+	if a.Purged {
+		return ErrArticleDeleted
+	}
+	if !a.Deleted {
+		return ErrArticleNotDeleted
+	}
+
+	return a.record(articlePurgedEvent, articlePurgedPayload{})
+}
+
+// Submit sends a draft to review.
+func (a *Article) Submit() error {
+	return a.transition(articleSubmittedEvent, articleSubmittedPayload{})
+}
+
+// Publish makes a reviewed article public.
+func (a *Article) Publish() error {
+	return a.transition(articlePublishedEvent, articlePublishedPayload{})
+}
+
+// Archive takes a published article out of the public lists.
+func (a *Article) Archive() error {
+	return a.transition(articleArchivedEvent, articleArchivedPayload{})
+}
+
+// SchedulePublish publishes a draft or an article in review at publishAt,
+// replacing the publication scheduled before.
+func (a *Article) SchedulePublish(publishAt time.Time) error {
+	if a.Deleted || a.Purged {
+		return ErrArticleDeleted
+	}
+
+	if a.Status != model.StatusDraft && a.Status != model.StatusInReview {
+		return fmt.Errorf("%w: the article is %s, it can't be scheduled for publishing", ErrInvalidTransition, a.Status)
+	}
+
+	return a.record(articlePublishScheduledEvent, articlePublishScheduledPayload{
+		PublishAt: publishAt.UTC().Truncate(time.Second),
+	})
+}
+
+// CancelPublish drops the scheduled publication.
+func (a *Article) CancelPublish() error {
+	if a.Deleted || a.Purged {
+		return ErrArticleDeleted
+	}
+
+	if a.PublishAt == nil {
+		return ErrNotScheduled
+	}
+
+	return a.record(articlePublishCancelledEvent, articlePublishCancelledPayload{})
+}
+
+// PublishDue publishes the article when its scheduled publication is due at
+// now. It reports false when there is nothing to publish anymore, and
+// ErrPublishNotDue when the publication is still ahead.
+func (a *Article) PublishDue(now time.Time) (bool, error) {
+	if a.Deleted || a.Purged || a.PublishAt == nil {
+		return false, nil
+	}
+
+	if a.PublishAt.After(now) {
+		return false, ErrPublishNotDue
+	}
+
+	// an editor scheduled it, it may skip the review
+	if a.Status != model.StatusDraft && a.Status != model.StatusInReview {
+		return false, nil
+	}
+
+	if err := a.record(articlePublishedEvent, articlePublishedPayload{}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (a *Article) transition(eventType string, payload any) error {
+	if a.Deleted || a.Purged {
+		return ErrArticleDeleted
+	}
+
+	transition := statusTransitions[eventType]
+	if a.Status != transition.from {
+		return fmt.Errorf("%w: the article is %s, it can't be %s", ErrInvalidTransition, a.Status, transition.verb)
+	}
+
+	return a.record(eventType, payload)
+}
+
+// Changes returns the events recorded since the aggregate was loaded.
+func (a *Article) Changes() []model.Event {
+	return a.changes
+}
+
+// Snapshots returns the article as it was right after each of Changes, the
+// state its envelope carries.
+func (a *Article) Snapshots() []*model.Article {
+	return a.snapshots
+}
+
+// setMetadata attaches the request metadata to every pending event.
+func (a *Article) setMetadata(metadata []byte) {
+	for i := range a.changes {
+		a.changes[i].Metadata = metadata
+	}
+}
+
+// LoadedVersion is the version the aggregate had in the event store, it is
+// the expected sequence when appending Changes.
+func (a *Article) LoadedVersion() int {
+	return a.Version - len(a.changes)
+}
+
+func (a *Article) ToModel() *model.Article {
+	return &model.Article{
+		Uuid:      a.Uuid,
+		Slug:      a.Slug,
+		Author:    a.Author,
+		Title:     a.Title,
+		Body:      a.Body,
+		Tags:      a.tags(),
+		Category:  a.Category,
+		Status:    a.Status,
+		Version:   a.Version,
+		Revision:  a.Revision,
+		UpdatedBy: a.UpdatedBy,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+		DeletedAt: a.DeletedAt,
+		PublishAt: a.PublishAt,
+	}
+}
+
+// tags is never nil, so an article without tags has an empty list.
+func (a *Article) tags() []string {
+	if a.Tags == nil {
+		return []string{}
+	}
+
+	return a.Tags
+}
+
+func (a *Article) record(eventType string, payload any) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	e := model.Event{
+		EventID:       uuid.NewString(),
+		AggregateID:   a.Uuid,
+		AggregateType: articleAggregateType,
+		Sequence:      a.Version + 1,
+		EventType:     eventType,
+		Payload:       jsonPayload,
+		Metadata:      []byte("{}"),
+		// the events table keeps whole seconds
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := a.apply(e); err != nil {
+		return err
+	}
+
+	snapshot := a.ToModel()
+	snapshot.Tags = append([]string{}, snapshot.Tags...)
+
+	a.changes = append(a.changes, e)
+	a.snapshots = append(a.snapshots, snapshot)
+
+	return nil
+}
+
+func (a *Article) apply(e model.Event) error {
+	switch e.EventType {
+	case articleCreatedEvent:
+		var payload articleCreatedPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return err
+		}
+
+		a.Uuid = e.AggregateID
+		a.Slug = payload.Slug
+		a.Author = payload.Author
+		a.Title = payload.Title
+		a.Body = payload.Body
+		a.Tags = payload.Tags
+		a.Category = payload.Category
+		a.Status = payload.Status
+		if a.Status == "" {
+			a.Status = model.StatusPublished
+		}
+		a.Revision = revisionNumber(payload.Revision, 0)
+		a.UpdatedBy = editorOf(payload.Editor, payload.Author)
+		a.CreatedAt = e.CreatedAt
+		a.UpdatedAt = e.CreatedAt
+	case articleUpdatedEvent:
+		var payload articleUpdatedPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return err
+		}
+
+		a.Author = payload.Author
+		a.Title = payload.Title
+		a.Body = payload.Body
+		a.Tags = payload.Tags
+		a.Category = payload.Category
+		a.Revision = revisionNumber(payload.Revision, a.Revision)
+		a.UpdatedBy = editorOf(payload.Editor, payload.Author)
+		a.UpdatedAt = e.CreatedAt
+	case articleDeletedEvent:
+		deletedAt := e.CreatedAt
+		a.Deleted = true
+		a.DeletedAt = &deletedAt
+		a.PublishAt = nil
+		a.UpdatedAt = e.CreatedAt
+	case articleRestoredEvent:
+		a.Deleted = false
+		a.DeletedAt = nil
+		a.UpdatedAt = e.CreatedAt
+	case articlePurgedEvent:
+		a.Purged = true
+		a.UpdatedAt = e.CreatedAt
+	case articleSubmittedEvent:
+		a.Status = model.StatusInReview
+		a.UpdatedAt = e.CreatedAt
+	case articlePublishedEvent:
+		a.Status = model.StatusPublished
+		a.PublishAt = nil
+		a.UpdatedAt = e.CreatedAt
+	case articleArchivedEvent:
+		a.Status = model.StatusArchived
+		a.UpdatedAt = e.CreatedAt
+	case articlePublishScheduledEvent:
+		var payload articlePublishScheduledPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return err
+		}
+
+		publishAt := payload.PublishAt.UTC()
+		a.PublishAt = &publishAt
+		a.UpdatedAt = e.CreatedAt
+	case articlePublishCancelledEvent:
+		a.PublishAt = nil
+		a.UpdatedAt = e.CreatedAt
+	case articleSlugChangedEvent:
+		var payload articleSlugChangedPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return err
+		}
+
+		a.Slug = payload.Slug
+		a.UpdatedAt = e.CreatedAt
+	default:
+		return fmt.Errorf("unknown article event %q", e.EventType)
+	}
+
+	a.Version = e.Sequence
+
+	return nil
+}
+
+// revisionNumber is the stored number, or the one after previous for events
+// written before revisions were numbered.
+func revisionNumber(stored, previous int) int {
+	if stored != 0 {
+		return stored
+	}
+
+	return previous + 1
+}
+
+// editorOf falls back to the author for events written before the editor was
+// recorded.
+func editorOf(editor, author string) string {
+	if editor != "" {
+		return editor
+	}
+
+	return author
+}
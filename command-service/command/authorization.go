@@ -0,0 +1,99 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Adhiana46/command-service/auth"
+	"github.com/Adhiana46/command-service/model"
+)
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("only the author or an editor can change this article")
+	ErrEditorRequired  = errors.New("only an editor can publish or schedule articles")
+
+	ErrCommentForbidden  = errors.New("only the author or an editor can change this comment")
+	ErrModeratorRequired = errors.New("only an editor can moderate comments")
+)
+
+// editorRoles may change articles of any author.
+var editorRoles = []string{"editor", "admin"}
+
+func currentPrincipal(ctx context.Context) (auth.Principal, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return auth.Principal{}, ErrUnauthenticated
+	}
+
+	return principal, nil
+}
+
+// authorizeChange allows the author of the article and editors to change it.
+func authorizeChange(ctx context.Context, article *Article) error {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	if principal.Subject != article.Author && !principal.HasRole(editorRoles...) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeEditor allows editors only.
+func authorizeEditor(ctx context.Context) error {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !principal.HasRole(editorRoles...) {
+		return ErrEditorRequired
+	}
+
+	return nil
+}
+
+// authorizeCommentChange allows the author of the comment and editors to
+// change it.
+func authorizeCommentChange(ctx context.Context, comment *Comment) error {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	if principal.Subject != comment.Author && !principal.HasRole(editorRoles...) {
+		return ErrCommentForbidden
+	}
+
+	return nil
+}
+
+// authorizeModerator allows editors only.
+func authorizeModerator(ctx context.Context) error {
+	if err := authorizeEditor(ctx); errors.Is(err, ErrEditorRequired) {
+		return ErrModeratorRequired
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// canRead reports whether the caller may read the article, unpublished
+// articles are only shown to their author and to editors.
+func canRead(ctx context.Context, article *Article) bool {
+	if article.Status == model.StatusPublished {
+		return true
+	}
+
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		return false
+	}
+
+	return principal.Subject == article.Author || principal.HasRole(editorRoles...)
+}
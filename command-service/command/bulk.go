@@ -0,0 +1,253 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/model"
+	"github.com/go-playground/validator/v10"
+)
+
+var ErrBulkAborted = errors.New("not applied, another operation of the atomic batch failed")
+
+// BulkResult is the outcome of one bulk operation, the article it left
+// behind or the error it failed with.
+type BulkResult struct {
+	Article *model.Article
+	Err     error
+}
+
+// Bulk validates every operation before it applies any. An atomic batch is
+// saved in a single step once every operation went through, the first
+// failure aborts the others. Otherwise each operation is saved on its own.
+// Only errors about the batch as a whole are returned, the others are in the
+// results.
+func (c *articleCommand) Bulk(ctx context.Context, reqDto dto.RequestBulkArticle) ([]BulkResult, error) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(reqDto.Operations))
+	invalid := false
+	for i, op := range reqDto.Operations {
+		if err := validateBulk(validate, op, principal.Subject); err != nil {
+			results[i].Err = err
+			invalid = true
+		}
+	}
+
+	if !reqDto.Atomic {
+		for i, op := range reqDto.Operations {
+			if results[i].Err != nil {
+				continue
+			}
+			results[i].Article, results[i].Err = c.applyBulk(ctx, op)
+		}
+
+		return results, nil
+	}
+
+	if invalid {
+		abortBulk(results)
+		return results, nil
+	}
+
+	// the operations run against a buffer on top of the repository, so later
+	// operations see the changes of the earlier ones
+	batch := &bulkRepository{
+		ArticleRepository: c.repo,
+		claimed:           map[string]string{},
+	}
+	staged := &articleCommand{repo: batch}
+
+	for i, op := range reqDto.Operations {
+		results[i].Article, results[i].Err = staged.applyBulk(ctx, op)
+		if results[i].Err != nil {
+			abortBulk(results)
+			return results, nil
+		}
+	}
+
+	if err := c.repo.SaveAll(ctx, batch.pending); err != nil {
+		return nil, err
+	}
+
+	latest := map[string]*model.Article{}
+	for _, result := range results {
+		latest[result.Article.Uuid] = result.Article
+	}
+	for _, aggregate := range batch.pending {
+		for _, e := range aggregate.Events {
+			c.refreshCache(ctx, e.EventType, latest[aggregate.AggregateID])
+		}
+	}
+
+	return results, nil
+}
+
+// validateBulk checks an operation like the request it stands for would be
+// checked.
+func validateBulk(validate *validator.Validate, op dto.RequestBulkOperation, author string) error {
+	switch op.Op {
+	case dto.BulkCreate:
+		return validate.Struct(bulkStore(op, author))
+	case dto.BulkUpdate:
+		return validate.Struct(bulkUpdate(op))
+	default:
+		return validate.Struct(bulkDelete(op))
+	}
+}
+
+func (c *articleCommand) applyBulk(ctx context.Context, op dto.RequestBulkOperation) (*model.Article, error) {
+	switch op.Op {
+	case dto.BulkCreate:
+		// Store sets the author itself
+		return c.Store(ctx, bulkStore(op, ""))
+	case dto.BulkUpdate:
+		return c.Update(ctx, bulkUpdate(op))
+	default:
+		return c.Delete(ctx, bulkDelete(op))
+	}
+}
+
+func bulkStore(op dto.RequestBulkOperation, author string) dto.RequestStoreArticle {
+	return dto.RequestStoreArticle{
+		Author:    author,
+		Title:     op.Title,
+		Body:      op.Body,
+		Tags:      op.Tags,
+		Category:  op.Category,
+		PublishAt: op.PublishAt,
+	}
+}
+
+func bulkUpdate(op dto.RequestBulkOperation) dto.RequestUpdateArticle {
+	return dto.RequestUpdateArticle{
+		Uuid:      op.Uuid,
+		Title:     op.Title,
+		Body:      op.Body,
+		Tags:      op.Tags,
+		Category:  op.Category,
+		PublishAt: op.PublishAt,
+		Version:   op.Version,
+	}
+}
+
+func bulkDelete(op dto.RequestBulkOperation) dto.RequestDeleteArticle {
+	return dto.RequestDeleteArticle{
+		Uuid:    op.Uuid,
+		Version: op.Version,
+	}
+}
+
+// abortBulk marks every operation of a failed atomic batch that did not fail
+// itself as not applied.
+func abortBulk(results []BulkResult) {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Article = nil
+			results[i].Err = ErrBulkAborted
+		}
+	}
+}
+
+// bulkRepository collects the saves of an atomic batch instead of writing
+// them. Loads see the collected events, and the slugs they claim are taken
+// for the other articles of the batch; the repository checks everything
+// again when the batch is saved.
+type bulkRepository struct {
+	ArticleRepository
+
+	pending []AggregateChanges
+	claimed map[string]string // slug to the article of the batch claiming it
+}
+
+func (r *bulkRepository) Load(ctx context.Context, aggregateID string) ([]model.Event, error) {
+	events, err := r.ArticleRepository.Load(ctx, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, aggregate := range r.pending {
+		if aggregate.AggregateID == aggregateID {
+			events = append(events, aggregate.Events...)
+		}
+	}
+
+	return events, nil
+}
+
+func (r *bulkRepository) Save(ctx context.Context, aggregateID string, expectedSequence int, events []model.Event, envelopes []event.Envelope) error {
+	current, err := r.Load(ctx, aggregateID)
+	if err != nil {
+		return err
+	}
+
+	currentSequence := 0
+	if len(current) > 0 {
+		currentSequence = current[len(current)-1].Sequence
+	}
+	if currentSequence != expectedSequence {
+		return ErrConcurrencyConflict
+	}
+
+	slugs, err := slugChanges(events)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range slugs {
+		if holder, ok := r.claimed[change.slug]; ok && !change.remove && holder != aggregateID {
+			return ErrSlugTaken
+		}
+	}
+	for _, change := range slugs {
+		if !change.remove {
+			r.claimed[change.slug] = aggregateID
+		}
+	}
+
+	r.pending = append(r.pending, AggregateChanges{
+		AggregateID:      aggregateID,
+		ExpectedSequence: expectedSequence,
+		Events:           events,
+		Envelopes:        envelopes,
+	})
+
+	return nil
+}
+
+func (r *bulkRepository) SaveAll(ctx context.Context, changes []AggregateChanges) error {
+	for _, aggregate := range changes {
+		err := r.Save(ctx, aggregate.AggregateID, aggregate.ExpectedSequence, aggregate.Events, aggregate.Envelopes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *bulkRepository) TakenSlugs(ctx context.Context, base string, aggregateID string) (map[string]bool, error) {
+	taken, err := r.ArticleRepository.TakenSlugs(ctx, base, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+
+	for slug, holder := range r.claimed {
+		if holder != aggregateID {
+			taken[slug] = true
+		}
+	}
+
+	return taken, nil
+}
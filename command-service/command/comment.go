@@ -0,0 +1,249 @@
+package command
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	commentCreatedEvent   = "comment.created"
+	commentEditedEvent    = "comment.edited"
+	commentDeletedEvent   = "comment.deleted"
+	commentModeratedEvent = "comment.moderated"
+)
+
+type CommentCommand interface {
+	// Store comments on an article the caller can read, or replies to one
+	// of its comments.
+	Store(ctx context.Context, reqDto dto.RequestStoreComment) (*model.Comment, error)
+	Update(ctx context.Context, reqDto dto.RequestUpdateComment) (*model.Comment, error)
+	Delete(ctx context.Context, reqDto dto.RequestDeleteComment) (*model.Comment, error)
+	// Moderate hides a comment or shows it again, only editors can moderate.
+	Moderate(ctx context.Context, reqDto dto.RequestModerateComment) (*model.Comment, error)
+}
+
+// commentCommand handles the comment commands. Comments are kept in the
+// article event store, their events go through the same outbox.
+type commentCommand struct {
+	repo ArticleRepository
+}
+
+func NewCommentCommand(repo ArticleRepository) CommentCommand {
+	return &commentCommand{
+		repo: repo,
+	}
+}
+
+func NewCommentCommandPg(db *sqlx.DB) CommentCommand {
+	return NewCommentCommand(NewArticleRepositoryPg(db))
+}
+
+// findArticle rebuilds the commented article, articles the caller can't read
+// are reported as sql.ErrNoRows like deleted or unknown ones.
+func (c *commentCommand) findArticle(ctx context.Context, uuid string) (*Article, error) {
+	events, err := c.repo.Load(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) == 0 || events[0].AggregateType != articleAggregateType {
+		return nil, sql.ErrNoRows
+	}
+
+	article, err := LoadArticle(events)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.Deleted || article.Purged || !canRead(ctx, article) {
+		return nil, sql.ErrNoRows
+	}
+
+	return article, nil
+}
+
+// findComment rebuilds the comment aggregate, unknown comments are reported
+// as sql.ErrNoRows.
+func (c *commentCommand) findComment(ctx context.Context, uuid string) (*Comment, error) {
+	events, err := c.repo.Load(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) == 0 || events[0].AggregateType != commentAggregateType {
+		return nil, sql.ErrNoRows
+	}
+
+	return LoadComment(events)
+}
+
+// save appends the pending events of the aggregate together with the
+// envelopes that announce them.
+func (c *commentCommand) save(ctx context.Context, comment *Comment) error {
+	metadata, err := json.Marshal(event.MetadataFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	comment.setMetadata(metadata)
+
+	envelopes := []event.Envelope{}
+	snapshots := comment.Snapshots()
+	for i, e := range comment.Changes() {
+		envelope, err := newEnvelope(e, snapshots[i])
+		if err != nil {
+			return err
+		}
+		envelopes = append(envelopes, envelope)
+	}
+
+	return c.repo.Save(ctx, comment.Uuid, comment.LoadedVersion(), comment.Changes(), envelopes)
+}
+
+func (c *commentCommand) Store(ctx context.Context, reqDto dto.RequestStoreComment) (*model.Comment, error) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reqDto.Author = principal.Subject
+
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.findArticle(ctx, reqDto.ArticleID); err != nil {
+		return nil, err
+	}
+
+	var parent *Comment
+	if reqDto.ParentID != "" {
+		parent, err = c.findComment(ctx, reqDto.ParentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	comment, err := NewComment(uuid.NewString(), reqDto.ArticleID, parent, reqDto.Author, reqDto.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.save(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment.ToModel(), nil
+}
+
+func (c *commentCommand) Update(ctx context.Context, reqDto dto.RequestUpdateComment) (*model.Comment, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	comment, err := c.findComment(ctx, reqDto.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeCommentChange(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != comment.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	principal, _ := currentPrincipal(ctx)
+
+	err = comment.Edit(reqDto.Body, principal.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.save(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment.ToModel(), nil
+}
+
+func (c *commentCommand) Delete(ctx context.Context, reqDto dto.RequestDeleteComment) (*model.Comment, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	comment, err := c.findComment(ctx, reqDto.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeCommentChange(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != comment.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	err = comment.Delete()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.save(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment.ToModel(), nil
+}
+
+func (c *commentCommand) Moderate(ctx context.Context, reqDto dto.RequestModerateComment) (*model.Comment, error) {
+	if err := authorizeModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	comment, err := c.findComment(ctx, reqDto.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if reqDto.Version != 0 && reqDto.Version != comment.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	principal, _ := currentPrincipal(ctx)
+
+	err = comment.Moderate(reqDto.Status, principal.Subject, reqDto.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.save(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment.ToModel(), nil
+}
@@ -0,0 +1,273 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+	"github.com/google/uuid"
+)
+
+const commentAggregateType = "comment"
+
+var (
+	ErrCommentDeleted   = errors.New("comment has been deleted")
+	ErrInvalidParent    = errors.New("a reply must belong to the article of the comment it answers")
+	ErrCommentModerated = errors.New("comment already has this status")
+)
+
+type commentCreatedPayload struct {
+	ArticleID string `json:"article_id"`
+	ParentID  string `json:"parent_id,omitempty"`
+	ThreadID  string `json:"thread_id"`
+	Depth     int    `json:"depth"`
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+}
+
+type commentEditedPayload struct {
+	Body   string `json:"body"`
+	Editor string `json:"editor"`
+}
+
+type commentDeletedPayload struct{}
+
+type commentModeratedPayload struct {
+	Status    string `json:"status"`
+	Moderator string `json:"moderator"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Comment is the event-sourced comment aggregate, it lives in the same event
+// store as the articles. A comment answers an article or, as a reply,
+// another comment of the same article. A deleted comment can't be changed
+// anymore, its replies stay.
+type Comment struct {
+	Uuid             string
+	ArticleID        string
+	ParentID         string
+	ThreadID         string
+	Depth            int
+	Author           string
+	Body             string
+	Status           string
+	Deleted          bool
+	Version          int
+	UpdatedBy        string
+	ModeratedBy      string
+	ModerationReason string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time
+
+	changes   []model.Event
+	snapshots []*model.Comment // the comment right after each change
+}
+
+// NewComment starts a new aggregate with a pending comment.created event, a
+// nil parent makes it a top-level comment.
+func NewComment(uuid, articleID string, parent *Comment, author, body string) (*Comment, error) {
+	comment := &Comment{Uuid: uuid}
+
+	payload := commentCreatedPayload{
+		ArticleID: articleID,
+		ThreadID:  uuid,
+		Author:    author,
+		Body:      body,
+	}
+
+	if parent != nil {
+		if parent.ArticleID != articleID {
+			return nil, ErrInvalidParent
+		}
+		if parent.Deleted {
+			return nil, ErrCommentDeleted
+		}
+
+		payload.ParentID = parent.Uuid
+		payload.ThreadID = parent.ThreadID
+		payload.Depth = parent.Depth + 1
+	}
+
+	if err := comment.record(commentCreatedEvent, payload); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// LoadComment rebuilds an aggregate from its stored events.
+func LoadComment(events []model.Event) (*Comment, error) {
+	comment := &Comment{}
+
+	for _, e := range events {
+		if err := comment.apply(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return comment, nil
+}
+
+// Edit replaces the body, editor is whoever made the change.
+func (c *Comment) Edit(body, editor string) error {
+	if c.Deleted {
+		return ErrCommentDeleted
+	}
+
+	return c.record(commentEditedEvent, commentEditedPayload{
+		Body:   body,
+		Editor: editor,
+	})
+}
+
+func (c *Comment) Delete() error {
+	if c.Deleted {
+		return ErrCommentDeleted
+	}
+
+	return c.record(commentDeletedEvent, commentDeletedPayload{})
+}
+
+// Moderate hides a comment or makes a hidden one visible again.
+func (c *Comment) Moderate(status, moderator, reason string) error {
+	if c.Deleted {
+		return ErrCommentDeleted
+	}
+
+	if c.Status == status {
+		return ErrCommentModerated
+	}
+
+	return c.record(commentModeratedEvent, commentModeratedPayload{
+		Status:    status,
+		Moderator: moderator,
+		Reason:    reason,
+	})
+}
+
+// Changes returns the events recorded since the aggregate was loaded.
+func (c *Comment) Changes() []model.Event {
+	return c.changes
+}
+
+// Snapshots returns the comment as it was right after each of Changes, the
+// state its envelope carries.
+func (c *Comment) Snapshots() []*model.Comment {
+	return c.snapshots
+}
+
+// setMetadata attaches the request metadata to every pending event.
+func (c *Comment) setMetadata(metadata []byte) {
+	for i := range c.changes {
+		c.changes[i].Metadata = metadata
+	}
+}
+
+// LoadedVersion is the version the aggregate had in the event store, it is
+// the expected sequence when appending Changes.
+func (c *Comment) LoadedVersion() int {
+	return c.Version - len(c.changes)
+}
+
+func (c *Comment) ToModel() *model.Comment {
+	return &model.Comment{
+		Uuid:             c.Uuid,
+		ArticleID:        c.ArticleID,
+		ParentID:         c.ParentID,
+		ThreadID:         c.ThreadID,
+		Depth:            c.Depth,
+		Author:           c.Author,
+		Body:             c.Body,
+		Status:           c.Status,
+		Version:          c.Version,
+		UpdatedBy:        c.UpdatedBy,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+		DeletedAt:        c.DeletedAt,
+		ModeratedBy:      c.ModeratedBy,
+		ModerationReason: c.ModerationReason,
+	}
+}
+
+func (c *Comment) record(eventType string, payload any) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	e := model.Event{
+		EventID:       uuid.NewString(),
+		AggregateID:   c.Uuid,
+		AggregateType: commentAggregateType,
+		Sequence:      c.Version + 1,
+		EventType:     eventType,
+		Payload:       jsonPayload,
+		Metadata:      []byte("{}"),
+		// the events table keeps whole seconds
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := c.apply(e); err != nil {
+		return err
+	}
+
+	c.changes = append(c.changes, e)
+	c.snapshots = append(c.snapshots, c.ToModel())
+
+	return nil
+}
+
+func (c *Comment) apply(e model.Event) error {
+	switch e.EventType {
+	case commentCreatedEvent:
+		var payload commentCreatedPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return err
+		}
+
+		c.Uuid = e.AggregateID
+		c.ArticleID = payload.ArticleID
+		c.ParentID = payload.ParentID
+		c.ThreadID = payload.ThreadID
+		c.Depth = payload.Depth
+		c.Author = payload.Author
+		c.Body = payload.Body
+		c.Status = model.CommentVisible
+		c.UpdatedBy = payload.Author
+		c.CreatedAt = e.CreatedAt
+		c.UpdatedAt = e.CreatedAt
+	case commentEditedEvent:
+		var payload commentEditedPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return err
+		}
+
+		c.Body = payload.Body
+		c.UpdatedBy = payload.Editor
+		c.UpdatedAt = e.CreatedAt
+	case commentDeletedEvent:
+		deletedAt := e.CreatedAt
+		c.Deleted = true
+		c.DeletedAt = &deletedAt
+		c.UpdatedAt = e.CreatedAt
+	case commentModeratedEvent:
+		var payload commentModeratedPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return err
+		}
+
+		c.Status = payload.Status
+		c.ModeratedBy = payload.Moderator
+		c.ModerationReason = payload.Reason
+		c.UpdatedAt = e.CreatedAt
+	default:
+		return fmt.Errorf("unknown comment event %q", e.EventType)
+	}
+
+	c.Version = e.Sequence
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/Adhiana46/command-service/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrConcurrencyConflict = errors.New("article was modified concurrently, reload it and try again")
+	ErrVersionMismatch     = errors.New("article version does not match the expected version")
+)
+
+const pgUniqueViolation = "23505"
+
+// loadEvents returns every event of an aggregate ordered by sequence.
+func loadEvents(ctx context.Context, q sqlx.QueryerContext, aggregateID string) ([]model.Event, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From("events").
+		Where(sq.Eq{"aggregate_id": aggregateID}).
+		OrderBy("sequence").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := []model.Event{}
+	err = sqlx.SelectContext(ctx, q, &events, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// appendEvents stores new events of an aggregate, failing with
+// ErrConcurrencyConflict when someone else appended after expectedSequence.
+func appendEvents(ctx context.Context, tx *sqlx.Tx, aggregateID string, expectedSequence int, events []model.Event) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("COALESCE(MAX(sequence), 0)").
+		From("events").
+		Where(sq.Eq{"aggregate_id": aggregateID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	var currentSequence int
+	err = tx.GetContext(ctx, &currentSequence, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if currentSequence != expectedSequence {
+		return ErrConcurrencyConflict
+	}
+
+	for _, e := range events {
+		sql, args, err := psql.Insert("events").
+			SetMap(map[string]interface{}{
+				"event_id":       e.EventID,
+				"aggregate_id":   e.AggregateID,
+				"aggregate_type": e.AggregateType,
+				"sequence":       e.Sequence,
+				"event_type":     e.EventType,
+				"payload":        string(e.Payload),
+				"metadata":       string(e.Metadata),
+				"created_at":     e.CreatedAt,
+			}).
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, sql, args...)
+		if err != nil {
+			// a concurrent append won the (aggregate_id, sequence) race
+			var pgErr pgx.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+				return ErrConcurrencyConflict
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EventFeed exposes the stored events in global order so read models can be
+// rebuilt from scratch.
+type EventFeed interface {
+	ReadAll(ctx context.Context, reqDto dto.RequestListEvent) ([]model.Event, error)
+}
+
+type eventFeedPg struct {
+	db *sqlx.DB
+}
+
+func NewEventFeedPg(db *sqlx.DB) EventFeed {
+	return &eventFeedPg{
+		db: db,
+	}
+}
+
+func (f *eventFeedPg) ReadAll(ctx context.Context, reqDto dto.RequestListEvent) ([]model.Event, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From("events").
+		Where(sq.Gt{"id": reqDto.After}).
+		OrderBy("id").
+		Limit(reqDto.Limit).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := []model.Event{}
+	err = f.db.SelectContext(ctx, &events, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,228 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/model"
+	"github.com/Adhiana46/command-service/scheduler"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// ArticleRepository stores the events of the article aggregates, and of the
+// comment aggregates that share the event store. Save appends
+// events after expectedSequence, failing with ErrConcurrencyConflict when
+// someone else appended first, and schedules their envelopes for publishing
+// and the jobs they ask for in the same step. The slugs claimed by the events
+// are unique, Save fails with ErrSlugTaken when another article holds one.
+type ArticleRepository interface {
+	Load(ctx context.Context, aggregateID string) ([]model.Event, error)
+	Save(ctx context.Context, aggregateID string, expectedSequence int, events []model.Event, envelopes []event.Envelope) error
+	// SaveAll saves the changes of several aggregates like Save does, all of
+	// them or none.
+	SaveAll(ctx context.Context, changes []AggregateChanges) error
+	// FindDeleted returns up to limit articles whose latest event is an
+	// article.deleted recorded before deletedBefore.
+	FindDeleted(ctx context.Context, deletedBefore time.Time, limit uint64) ([]string, error)
+	// TakenSlugs returns the slugs held by articles other than aggregateID
+	// that start like base.
+	TakenSlugs(ctx context.Context, base string, aggregateID string) (map[string]bool, error)
+}
+
+// AggregateChanges are the events appended to one aggregate after
+// ExpectedSequence and the envelopes that announce them.
+type AggregateChanges struct {
+	AggregateID      string
+	ExpectedSequence int
+	Events           []model.Event
+	Envelopes        []event.Envelope
+}
+
+type articleRepositoryPg struct {
+	db *sqlx.DB
+}
+
+func NewArticleRepositoryPg(db *sqlx.DB) ArticleRepository {
+	return &articleRepositoryPg{
+		db: db,
+	}
+}
+
+func (r *articleRepositoryPg) Load(ctx context.Context, aggregateID string) ([]model.Event, error) {
+	return loadEvents(ctx, r.db, aggregateID)
+}
+
+// Save appends the events and writes the envelopes to the outbox, the slugs,
+// the tags and category of the article and the scheduled jobs in a single
+// transaction, the outbox relay publishes the envelopes once it is committed.
+func (r *articleRepositoryPg) Save(ctx context.Context, aggregateID string, expectedSequence int, events []model.Event, envelopes []event.Envelope) error {
+	return r.SaveAll(ctx, []AggregateChanges{{
+		AggregateID:      aggregateID,
+		ExpectedSequence: expectedSequence,
+		Events:           events,
+		Envelopes:        envelopes,
+	}})
+}
+
+func (r *articleRepositoryPg) SaveAll(ctx context.Context, changes []AggregateChanges) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		err = r.save(ctx, tx, change)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// save writes the changes of one aggregate in tx.
+func (r *articleRepositoryPg) save(ctx context.Context, tx *sqlx.Tx, aggregate AggregateChanges) error {
+	aggregateID := aggregate.AggregateID
+
+	err := appendEvents(ctx, tx, aggregateID, aggregate.ExpectedSequence, aggregate.Events)
+	if err != nil {
+		return err
+	}
+
+	for _, envelope := range aggregate.Envelopes {
+		err = r.enqueue(ctx, tx, envelope)
+		if err != nil {
+			return err
+		}
+	}
+
+	slugs, err := slugChanges(aggregate.Events)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range slugs {
+		err = saveSlug(ctx, tx, aggregateID, change)
+		if err != nil {
+			return err
+		}
+	}
+
+	taxonomy, err := taxonomyChanges(aggregate.Events)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range taxonomy {
+		err = saveTaxonomy(ctx, tx, aggregateID, change)
+		if err != nil {
+			return err
+		}
+	}
+
+	changes, err := scheduleChanges(aggregate.Events)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if change.cancel {
+			err = scheduler.Cancel(ctx, tx, aggregateID, change.jobType)
+		} else {
+			err = scheduler.Schedule(ctx, tx, aggregateID, change.jobType, change.runAt)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *articleRepositoryPg) FindDeleted(ctx context.Context, deletedBefore time.Time, limit uint64) ([]string, error) {
+	// the latest event of every article, the inner query keeps ? placeholders
+	// so the outer one can number them
+	latest := sq.Select("DISTINCT ON (aggregate_id) aggregate_id, event_type, created_at").
+		From("events").
+		Where(sq.Eq{"aggregate_type": articleAggregateType}).
+		OrderBy("aggregate_id", "sequence DESC")
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("aggregate_id").
+		FromSelect(latest, "latest").
+		Where(sq.Eq{"event_type": articleDeletedEvent}).
+		Where(sq.Lt{"created_at": deletedBefore}).
+		OrderBy("created_at").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	uuids := []string{}
+	err = r.db.SelectContext(ctx, &uuids, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return uuids, nil
+}
+
+func (r *articleRepositoryPg) TakenSlugs(ctx context.Context, base string, aggregateID string) (map[string]bool, error) {
+	return takenSlugs(ctx, r.db, base, aggregateID)
+}
+
+func (r *articleRepositoryPg) enqueue(ctx context.Context, tx *sqlx.Tx, envelope event.Envelope) error {
+	jsonPayload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert("outbox").
+		SetMap(map[string]interface{}{
+			"aggregate_id": envelope.AggregateID,
+			"event_name":   envelope.EventType,
+			"payload":      string(jsonPayload),
+			"created_at":   time.Now(),
+		}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	return err
+}
+
+// scheduleChange sets or cancels the pending job of an aggregate.
+type scheduleChange struct {
+	jobType string
+	runAt   time.Time
+	cancel  bool
+}
+
+// scheduleChanges are the jobs asked for by the events, in event order.
+func scheduleChanges(events []model.Event) ([]scheduleChange, error) {
+	changes := []scheduleChange{}
+	for _, e := range events {
+		switch e.EventType {
+		case articlePublishScheduledEvent:
+			var payload articlePublishScheduledPayload
+			if err := json.Unmarshal(e.Payload, &payload); err != nil {
+				return nil, err
+			}
+
+			changes = append(changes, scheduleChange{jobType: PublishArticleJob, runAt: payload.PublishAt})
+		case articlePublishCancelledEvent, articlePublishedEvent, articleDeletedEvent:
+			changes = append(changes, scheduleChange{jobType: PublishArticleJob, cancel: true})
+		}
+	}
+
+	return changes, nil
+}
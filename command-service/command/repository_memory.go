@@ -0,0 +1,228 @@
+package command
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/model"
+	"github.com/Adhiana46/command-service/scheduler"
+	"github.com/go-playground/validator/v10"
+)
+
+// ArticleRepositoryMemory keeps the event store in memory and has no outbox,
+// envelopes are pushed to the emitter as soon as their events are appended.
+// The tags and categories are only kept in the events, slugs are kept with
+// the article holding them.
+// It is also the EventFeed of the events it holds, and schedules jobs in JobStore.
+type ArticleRepositoryMemory struct {
+	mu      sync.Mutex
+	emitter event.Emitter
+	events  []model.Event
+	slugs   map[string]string
+	jobs    *scheduler.StoreMemory
+}
+
+func NewArticleRepositoryMemory(emitter event.Emitter) *ArticleRepositoryMemory {
+	return &ArticleRepositoryMemory{
+		emitter: emitter,
+		slugs:   map[string]string{},
+		jobs:    scheduler.NewStoreMemory(),
+	}
+}
+
+// JobStore holds the scheduled jobs asked for by the saved events.
+func (r *ArticleRepositoryMemory) JobStore() *scheduler.StoreMemory {
+	return r.jobs
+}
+
+func (r *ArticleRepositoryMemory) Load(ctx context.Context, aggregateID string) ([]model.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []model.Event{}
+	for _, e := range r.events {
+		if e.AggregateID == aggregateID {
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+func (r *ArticleRepositoryMemory) Save(ctx context.Context, aggregateID string, expectedSequence int, events []model.Event, envelopes []event.Envelope) error {
+	return r.SaveAll(ctx, []AggregateChanges{{
+		AggregateID:      aggregateID,
+		ExpectedSequence: expectedSequence,
+		Events:           events,
+		Envelopes:        envelopes,
+	}})
+}
+
+// SaveAll checks every aggregate before it appends the events of any.
+func (r *ArticleRepositoryMemory) SaveAll(ctx context.Context, aggregates []AggregateChanges) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	currentSequence := map[string]int{}
+	for _, e := range r.events {
+		if e.Sequence > currentSequence[e.AggregateID] {
+			currentSequence[e.AggregateID] = e.Sequence
+		}
+	}
+
+	// the holders the slugs will have, so two aggregates of the batch can't
+	// claim the same one
+	holders := map[string]string{}
+	for slug, holder := range r.slugs {
+		holders[slug] = holder
+	}
+
+	slugs := make([][]slugChange, len(aggregates))
+	changes := make([][]scheduleChange, len(aggregates))
+	for i, aggregate := range aggregates {
+		if currentSequence[aggregate.AggregateID] != aggregate.ExpectedSequence {
+			return ErrConcurrencyConflict
+		}
+		if n := len(aggregate.Events); n > 0 {
+			currentSequence[aggregate.AggregateID] = aggregate.Events[n-1].Sequence
+		}
+
+		var err error
+		slugs[i], err = slugChanges(aggregate.Events)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range slugs[i] {
+			if change.remove {
+				for slug, holder := range holders {
+					if holder == aggregate.AggregateID {
+						delete(holders, slug)
+					}
+				}
+				continue
+			}
+			if holder, ok := holders[change.slug]; ok && holder != aggregate.AggregateID {
+				return ErrSlugTaken
+			}
+			holders[change.slug] = aggregate.AggregateID
+		}
+
+		changes[i], err = scheduleChanges(aggregate.Events)
+		if err != nil {
+			return err
+		}
+	}
+
+	envelopes := []event.Envelope{}
+	for i, aggregate := range aggregates {
+		aggregateID := aggregate.AggregateID
+
+		for _, e := range aggregate.Events {
+			e.ID = int64(len(r.events) + 1)
+			r.events = append(r.events, e)
+		}
+
+		for _, change := range slugs[i] {
+			if change.remove {
+				r.releaseSlugs(aggregateID)
+			} else {
+				r.slugs[change.slug] = aggregateID
+			}
+		}
+
+		for _, change := range changes[i] {
+			if change.cancel {
+				r.jobs.Cancel(aggregateID, change.jobType)
+			} else {
+				r.jobs.Schedule(aggregateID, change.jobType, change.runAt)
+			}
+		}
+
+		envelopes = append(envelopes, aggregate.Envelopes...)
+	}
+
+	// pushed under the lock so the events of an article keep their order
+	_, err := event.PushEnvelopes(r.emitter, envelopes)
+	return err
+}
+
+func (r *ArticleRepositoryMemory) FindDeleted(ctx context.Context, deletedBefore time.Time, limit uint64) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	latest := map[string]model.Event{}
+	order := []string{}
+	for _, e := range r.events {
+		if e.AggregateType != articleAggregateType {
+			continue
+		}
+		if _, ok := latest[e.AggregateID]; !ok {
+			order = append(order, e.AggregateID)
+		}
+		latest[e.AggregateID] = e
+	}
+
+	uuids := []string{}
+	for _, aggregateID := range order {
+		e := latest[aggregateID]
+		if e.EventType != articleDeletedEvent || !e.CreatedAt.Before(deletedBefore) {
+			continue
+		}
+		if uint64(len(uuids)) >= limit {
+			break
+		}
+		uuids = append(uuids, aggregateID)
+	}
+
+	return uuids, nil
+}
+
+func (r *ArticleRepositoryMemory) TakenSlugs(ctx context.Context, base string, aggregateID string) (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taken := map[string]bool{}
+	for slug, holder := range r.slugs {
+		if holder != aggregateID {
+			taken[slug] = true
+		}
+	}
+
+	return taken, nil
+}
+
+func (r *ArticleRepositoryMemory) releaseSlugs(aggregateID string) {
+	for slug, holder := range r.slugs {
+		if holder == aggregateID {
+			delete(r.slugs, slug)
+		}
+	}
+}
+
+func (r *ArticleRepositoryMemory) ReadAll(ctx context.Context, reqDto dto.RequestListEvent) ([]model.Event, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []model.Event{}
+	for _, e := range r.events {
+		if e.ID <= reqDto.After {
+			continue
+		}
+		if uint64(len(events)) >= reqDto.Limit {
+			break
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,207 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Adhiana46/command-service/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	maxSlugLength = 80
+
+	// fallbackSlug names an article whose title has nothing to transliterate
+	fallbackSlug = "article"
+)
+
+var ErrSlugTaken = errors.New("slug is already taken by another article, try again")
+
+// transliterations spell out the letters that don't decompose into an ASCII
+// letter and a mark.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'ø': "o", 'œ': "oe", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y",
+	'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+}
+
+// slugify turns a title into lowercase ASCII words joined by dashes. Accents
+// are dropped, other scripts are transliterated where a table exists and
+// anything else separates words.
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(strings.ToLower(title)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		word, ok := transliterations[r]
+		if !ok && (('a' <= r && r <= 'z') || ('0' <= r && r <= '9')) {
+			word, ok = string(r), true
+		}
+		if !ok {
+			dash = b.Len() > 0
+			continue
+		}
+
+		if dash && word != "" {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(word)
+	}
+
+	slug := trimSlug(b.String(), maxSlugLength)
+	if slug == "" {
+		return fallbackSlug
+	}
+
+	return slug
+}
+
+// trimSlug cuts the slug to length without leaving a dash at its end.
+func trimSlug(slug string, length int) string {
+	if len(slug) > length {
+		slug = slug[:length]
+	}
+
+	return strings.Trim(slug, "-")
+}
+
+// freeSlug is base, or base with the first numeric suffix that is not in
+// taken.
+func freeSlug(base string, taken map[string]bool) string {
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		suffix := fmt.Sprintf("-%d", n)
+		slug = trimSlug(base, maxSlugLength-len(suffix)) + suffix
+	}
+
+	return slug
+}
+
+// slugChange claims a slug for an article, or releases every slug of the
+// article when it is purged.
+type slugChange struct {
+	slug   string
+	remove bool
+}
+
+// slugChanges are the slugs claimed by the events, in event order.
+func slugChanges(events []model.Event) ([]slugChange, error) {
+	changes := []slugChange{}
+	for _, e := range events {
+		switch e.EventType {
+		case articleCreatedEvent:
+			var payload articleCreatedPayload
+			if err := json.Unmarshal(e.Payload, &payload); err != nil {
+				return nil, err
+			}
+
+			// articles created before slugs existed have none
+			if payload.Slug != "" {
+				changes = append(changes, slugChange{slug: payload.Slug})
+			}
+		case articleSlugChangedEvent:
+			var payload articleSlugChangedPayload
+			if err := json.Unmarshal(e.Payload, &payload); err != nil {
+				return nil, err
+			}
+
+			changes = append(changes, slugChange{slug: payload.Slug})
+		case articlePurgedEvent:
+			changes = append(changes, slugChange{remove: true})
+		}
+	}
+
+	return changes, nil
+}
+
+// saveSlug claims the slug in article_slugs. The retired slugs of the article
+// stay in the table so they keep resolving, claiming one of them again is
+// allowed. A slug of another article fails with ErrSlugTaken.
+func saveSlug(ctx context.Context, tx *sqlx.Tx, aggregateID string, change slugChange) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	if change.remove {
+		sql, args, err := psql.Delete("article_slugs").
+			Where(sq.Eq{"article_id": aggregateID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, sql, args...)
+		return err
+	}
+
+	sql, args, err := psql.Insert("article_slugs").
+		Columns("slug", "article_id", "created_at").
+		Values(change.slug, aggregateID, time.Now().UTC().Truncate(time.Second)).
+		// the no-op update only matches a slug of the same article
+		Suffix("ON CONFLICT (slug) DO UPDATE SET article_id = EXCLUDED.article_id WHERE article_slugs.article_id = EXCLUDED.article_id").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return ErrSlugTaken
+	}
+
+	return nil
+}
+
+// takenSlugs returns the slugs of other articles that base or one of its
+// suffixed forms could collide with.
+func takenSlugs(ctx context.Context, db *sqlx.DB, base string, aggregateID string) (map[string]bool, error) {
+	// a long base is cut to make room for the suffix, so only its start is
+	// matched
+	prefix := base
+	if len(prefix) > maxSlugLength-10 {
+		prefix = prefix[:maxSlugLength-10]
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("slug").
+		From("article_slugs").
+		// slugs have no LIKE wildcards to escape
+		Where(sq.Like{"slug": prefix + "%"}).
+		Where(sq.NotEq{"article_id": aggregateID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	slugs := []string{}
+	if err := db.SelectContext(ctx, &slugs, sql, args...); err != nil {
+		return nil, err
+	}
+
+	taken := map[string]bool{}
+	for _, slug := range slugs {
+		taken[slug] = true
+	}
+
+	return taken, nil
+}
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/Adhiana46/command-service/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// normalizeTag lowercases a tag or category and trims the spaces around it.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags returns the distinct normalized tags in alphabetical order.
+func normalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// taxonomyChange sets the tags and the category of an article, or removes
+// them when the article is purged.
+type taxonomyChange struct {
+	tags     []string
+	category string
+	remove   bool
+}
+
+// taxonomyChanges are the tag and category writes asked for by the events, in
+// event order.
+func taxonomyChanges(events []model.Event) ([]taxonomyChange, error) {
+	changes := []taxonomyChange{}
+	for _, e := range events {
+		switch e.EventType {
+		case articleCreatedEvent, articleUpdatedEvent:
+			// both payloads carry the same tag and category fields
+			var payload articleUpdatedPayload
+			if err := json.Unmarshal(e.Payload, &payload); err != nil {
+				return nil, err
+			}
+
+			changes = append(changes, taxonomyChange{tags: payload.Tags, category: payload.Category})
+		case articlePurgedEvent:
+			changes = append(changes, taxonomyChange{remove: true})
+		}
+	}
+
+	return changes, nil
+}
+
+// saveTaxonomy replaces the rows linking the article to its tags and its
+// category, the tags and categories are created on first use.
+func saveTaxonomy(ctx context.Context, tx *sqlx.Tx, aggregateID string, change taxonomyChange) error {
+	if err := removeTaxonomy(ctx, tx, aggregateID); err != nil {
+		return err
+	}
+
+	if change.remove {
+		return nil
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	for _, tag := range change.tags {
+		tagID, err := upsertName(ctx, tx, "tags", tag)
+		if err != nil {
+			return err
+		}
+
+		sql, args, err := psql.Insert("article_tags").
+			Columns("article_id", "tag_id").
+			Values(aggregateID, tagID).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+
+	if change.category == "" {
+		return nil
+	}
+
+	categoryID, err := upsertName(ctx, tx, "categories", change.category)
+	if err != nil {
+		return err
+	}
+
+	sql, args, err := psql.Insert("article_categories").
+		Columns("article_id", "category_id").
+		Values(aggregateID, categoryID).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	return err
+}
+
+func removeTaxonomy(ctx context.Context, tx *sqlx.Tx, aggregateID string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	for _, table := range []string{"article_tags", "article_categories"} {
+		sql, args, err := psql.Delete(table).
+			Where(sq.Eq{"article_id": aggregateID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsertName returns the id of the named row of a tags or categories table,
+// inserting it when it is new.
+func upsertName(ctx context.Context, tx *sqlx.Tx, table string, name string) (int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(table).
+		Columns("name").
+		Values(name).
+		// a no-op update, so the id of an existing row is returned too
+		Suffix("ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id").
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = tx.GetContext(ctx, &id, sql, args...)
+	return id, err
+}
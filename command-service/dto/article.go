@@ -8,37 +8,89 @@ import (
 
 type ResponseArticle struct {
 	Uuid      string    `json:"uuid"`
+	Slug      string    `json:"slug"`
 	Author    string    `json:"author"`
 	Title     string    `json:"title"`
 	Body      string    `json:"body"`
+	Tags      []string  `json:"tags"`
+	Category  string    `json:"category"`
+	Status    string    `json:"status"`
+	Version   int       `json:"version"`
+	Revision  int       `json:"revision"`
+	UpdatedBy string    `json:"updated_by"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
 }
 
 type RequestStoreArticle struct {
-	Author string `json:"author" validate:"required"`
-	Title  string `json:"title" validate:"required"`
-	Body   string `json:"body" validate:"required"`
+	Author    string     `json:"-" validate:"required"` // the authenticated subject
+	Title     string     `json:"title" validate:"required"`
+	Body      string     `json:"body" validate:"required"`
+	Tags      []string   `json:"tags" validate:"max=20,dive,required,max=50"`
+	Category  string     `json:"category" validate:"max=50"`
+	PublishAt *time.Time `json:"publish_at"` // publishes the draft later, editors only
 }
 
 type RequestUpdateArticle struct {
-	Uuid   string `validate:"required"`
-	Author string `json:"author" validate:"required"`
-	Title  string `json:"title" validate:"required"`
-	Body   string `json:"body" validate:"required"`
+	Uuid      string     `validate:"required"`
+	Title     string     `json:"title" validate:"required"`
+	Body      string     `json:"body" validate:"required"`
+	Tags      []string   `json:"tags" validate:"max=20,dive,required,max=50"` // replaces the tags
+	Category  string     `json:"category" validate:"max=50"`                  // replaces the category
+	PublishAt *time.Time `json:"publish_at"`                                  // publishes the article later, editors only
+	Version   int        `json:"version" validate:"min=0"`                    // expected version, 0 skips the check
 }
 
 type RequestDeleteArticle struct {
-	Uuid string `validate:"required"`
+	Uuid    string `validate:"required"`
+	Version int    `json:"version" validate:"min=0"` // expected version, 0 skips the check
+}
+
+type RequestRestoreArticle struct {
+	Uuid    string `validate:"required"`
+	Version int    `json:"version" validate:"min=0"` // expected version, 0 skips the check
+}
+
+// RequestTransitionArticle moves an article to the next status of the
+// editorial workflow.
+type RequestTransitionArticle struct {
+	Uuid    string `validate:"required"`
+	Version int    `json:"version" validate:"min=0"` // expected version, 0 skips the check
+}
+
+// RequestScheduleArticle publishes an article at PublishAt, or moves the
+// publication scheduled before.
+type RequestScheduleArticle struct {
+	Uuid      string    `validate:"required"`
+	PublishAt time.Time `json:"publish_at" validate:"required"`
+	Version   int       `json:"version" validate:"min=0"` // expected version, 0 skips the check
+}
+
+type RequestRevertArticle struct {
+	Uuid     string `validate:"required"`
+	Revision int    `validate:"min=1"`
+	Version  int    `json:"version" validate:"min=0"` // expected version, 0 skips the check
 }
 
 func ArticleToResponseDTO(article *model.Article) *ResponseArticle {
 	return &ResponseArticle{
 		Uuid:      article.Uuid,
+		Slug:      article.Slug,
 		Author:    article.Author,
 		Title:     article.Title,
 		Body:      article.Body,
+		Tags:      article.Tags,
+		Category:  article.Category,
+		Status:    article.Status,
+		Version:   article.Version,
+		Revision:  article.Revision,
+		UpdatedBy: article.UpdatedBy,
 		CreatedAt: article.CreatedAt,
 		UpdatedAt: article.UpdatedAt,
+		DeletedAt: article.DeletedAt,
+		PublishAt: article.PublishAt,
 	}
 }
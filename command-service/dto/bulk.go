@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// RequestBulkArticle applies several article operations at once. Atomic
+// batches are applied all together or not at all, otherwise every operation
+// is applied on its own and may fail alone.
+type RequestBulkArticle struct {
+	Atomic     bool                   `json:"atomic"`
+	Operations []RequestBulkOperation `json:"operations" validate:"required,min=1,max=500,dive"`
+}
+
+// RequestBulkOperation carries the fields of the create, update or delete
+// request it stands for, the fields an operation doesn't use are ignored.
+type RequestBulkOperation struct {
+	Op        string     `json:"op" validate:"required,oneof=create update delete"`
+	Uuid      string     `json:"uuid"` // the article updated or deleted
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Tags      []string   `json:"tags"`
+	Category  string     `json:"category"`
+	PublishAt *time.Time `json:"publish_at"`
+	Version   int        `json:"version"` // expected version, 0 skips the check
+}
+
+// ResponseBulkItem is the outcome of one operation, in the order of the
+// request. Status is the HTTP status the operation would have had alone.
+type ResponseBulkItem struct {
+	Index   int              `json:"index"`
+	Op      string           `json:"op"`
+	Status  int              `json:"status"`
+	Error   string           `json:"error,omitempty"`
+	Article *ResponseArticle `json:"article,omitempty"`
+}
+
+type ResponseBulkArticle struct {
+	Atomic  bool                `json:"atomic"`
+	Applied int                 `json:"applied"`
+	Failed  int                 `json:"failed"`
+	Items   []*ResponseBulkItem `json:"items"`
+}
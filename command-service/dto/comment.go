@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+)
+
+type ResponseComment struct {
+	Uuid      string    `json:"uuid"`
+	ArticleID string    `json:"article_id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	ThreadID  string    `json:"thread_id"`
+	Depth     int       `json:"depth"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Status    string    `json:"status"`
+	Version   int       `json:"version"`
+	UpdatedBy string    `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	ModeratedBy      string     `json:"moderated_by,omitempty"`
+	ModerationReason string     `json:"moderation_reason,omitempty"`
+}
+
+type RequestStoreComment struct {
+	ArticleID string `validate:"required"`
+	Author    string `json:"-" validate:"required"` // the authenticated subject
+	ParentID  string `json:"parent_id"`             // the comment answered, empty for a top-level comment
+	Body      string `json:"body" validate:"required,max=10000"`
+}
+
+type RequestUpdateComment struct {
+	Uuid    string `validate:"required"`
+	Body    string `json:"body" validate:"required,max=10000"`
+	Version int    `json:"version" validate:"min=0"` // expected version, 0 skips the check
+}
+
+type RequestDeleteComment struct {
+	Uuid    string `validate:"required"`
+	Version int    `json:"version" validate:"min=0"` // expected version, 0 skips the check
+}
+
+// RequestModerateComment hides a comment or makes it visible again.
+type RequestModerateComment struct {
+	Uuid    string `validate:"required"`
+	Status  string `json:"status" validate:"required,oneof=visible hidden"`
+	Reason  string `json:"reason" validate:"max=500"`
+	Version int    `json:"version" validate:"min=0"` // expected version, 0 skips the check
+}
+
+func CommentToResponseDTO(comment *model.Comment) *ResponseComment {
+	return &ResponseComment{
+		Uuid:             comment.Uuid,
+		ArticleID:        comment.ArticleID,
+		ParentID:         comment.ParentID,
+		ThreadID:         comment.ThreadID,
+		Depth:            comment.Depth,
+		Author:           comment.Author,
+		Body:             comment.Body,
+		Status:           comment.Status,
+		Version:          comment.Version,
+		UpdatedBy:        comment.UpdatedBy,
+		CreatedAt:        comment.CreatedAt,
+		UpdatedAt:        comment.UpdatedAt,
+		DeletedAt:        comment.DeletedAt,
+		ModeratedBy:      comment.ModeratedBy,
+		ModerationReason: comment.ModerationReason,
+	}
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+)
+
+type ResponseEvent struct {
+	Position      int64           `json:"position"`
+	EventID       string          `json:"event_id"`
+	AggregateID   string          `json:"aggregate_id"`
+	AggregateType string          `json:"aggregate_type"`
+	Sequence      int             `json:"sequence"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Metadata      json.RawMessage `json:"metadata"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type RequestListEvent struct {
+	After int64  `validate:"min=0"`
+	Limit uint64 `validate:"min=1,max=1000"`
+}
+
+func EventToResponseDTO(e *model.Event) *ResponseEvent {
+	return &ResponseEvent{
+		Position:      e.ID,
+		EventID:       e.EventID,
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		Sequence:      e.Sequence,
+		EventType:     e.EventType,
+		Payload:       json.RawMessage(e.Payload),
+		Metadata:      json.RawMessage(e.Metadata),
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+func EventsToResponseDtos(events []model.Event) []*ResponseEvent {
+	result := []*ResponseEvent{}
+	for i := range events {
+		result = append(result, EventToResponseDTO(&events[i]))
+	}
+	return result
+}
@@ -1,17 +1,57 @@
 package event
 
 import (
+	"encoding/json"
 	"log"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-type Emitter struct {
+const (
+	TransportRabbitmq = "rabbitmq"
+	TransportKafka    = "kafka"
+	TransportMemory   = "memory"
+)
+
+// Emitter publishes events on a transport. Push sends a bare payload and
+// PushEnvelope an event envelope, the events of one aggregate are delivered
+// in the order they are pushed.
+type Emitter interface {
+	Push(eventName string, data []byte) error
+	PushEnvelope(envelope Envelope) error
+}
+
+// BatchEmitter is an Emitter that publishes several envelopes in one go, in
+// order. PushEnvelopes returns how many envelopes were pushed before it
+// failed.
+type BatchEmitter interface {
+	Emitter
+	PushEnvelopes(envelopes []Envelope) (int, error)
+}
+
+// PushEnvelopes publishes the envelopes in order through the batch support of
+// the emitter, or one by one when it has none. It returns how many envelopes
+// were pushed before it failed.
+func PushEnvelopes(emitter Emitter, envelopes []Envelope) (int, error) {
+	if batch, ok := emitter.(BatchEmitter); ok {
+		return batch.PushEnvelopes(envelopes)
+	}
+
+	for i, envelope := range envelopes {
+		if err := emitter.PushEnvelope(envelope); err != nil {
+			return i, err
+		}
+	}
+
+	return len(envelopes), nil
+}
+
+type rabbitmqEmitter struct {
 	exchangeName string
-	connection   *amqp.Connection
+	connection   *Connection
 }
 
-func (e *Emitter) setup() error {
+func (e *rabbitmqEmitter) setup() error {
 	ch, err := e.connection.Channel()
 	if err != nil {
 		return err
@@ -21,18 +61,103 @@ func (e *Emitter) setup() error {
 	return declareExchange(ch, e.exchangeName)
 }
 
-func (e *Emitter) Push(eventName string, data []byte) error {
-	ch, err := e.connection.Channel()
+func (e *rabbitmqEmitter) Push(eventName string, data []byte) error {
+	return e.publish(eventName, amqp.Publishing{
+		ContentType: "text/plain",
+		Body:        data,
+	})
+}
+
+// PushEnvelope publishes an event envelope as JSON with its metadata mirrored
+// in the AMQP message properties.
+func (e *rabbitmqEmitter) PushEnvelope(envelope Envelope) error {
+	msg, err := envelopePublishing(envelope)
 	if err != nil {
 		return err
 	}
+
+	return e.publish(envelope.EventType, msg)
+}
+
+// PushEnvelopes publishes the envelopes over a single channel.
+func (e *rabbitmqEmitter) PushEnvelopes(envelopes []Envelope) (int, error) {
+	ch, queue, err := e.channel()
+	if err != nil {
+		return 0, err
+	}
 	defer ch.Close()
 
+	log.Printf("Push %d events to channel E:%s -> Q:%s", len(envelopes), e.exchangeName, queue.Name)
+
+	bound := map[string]bool{}
+	for i, envelope := range envelopes {
+		msg, err := envelopePublishing(envelope)
+		if err != nil {
+			return i, err
+		}
+
+		if !bound[envelope.EventType] {
+			ch.QueueBind(queue.Name, envelope.EventType, e.exchangeName, false, nil)
+			bound[envelope.EventType] = true
+		}
+
+		err = ch.Publish(e.exchangeName, envelope.EventType, false, false, msg)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(envelopes), nil
+}
+
+func envelopePublishing(envelope Envelope) (amqp.Publishing, error) {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return amqp.Publishing{}, err
+	}
+
+	return amqp.Publishing{
+		ContentType:   "application/json",
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     envelope.EventID,
+		CorrelationId: envelope.CorrelationID,
+		Timestamp:     envelope.OccurredAt,
+		Type:          envelope.EventType,
+		AppId:         envelope.Producer,
+		Body:          data,
+	}, nil
+}
+
+// channel opens a channel with the exchange and its queue declared, the
+// broker may have restarted since the last push.
+func (e *rabbitmqEmitter) channel() (*amqp.Channel, amqp.Queue, error) {
+	ch, err := e.connection.Channel()
+	if err != nil {
+		return nil, amqp.Queue{}, err
+	}
+
+	err = declareExchange(ch, e.exchangeName)
+	if err != nil {
+		ch.Close()
+		return nil, amqp.Queue{}, err
+	}
+
 	// declare queue
 	queue, err := declareQueue(ch, e.exchangeName)
+	if err != nil {
+		ch.Close()
+		return nil, amqp.Queue{}, err
+	}
+
+	return ch, queue, nil
+}
+
+func (e *rabbitmqEmitter) publish(eventName string, msg amqp.Publishing) error {
+	ch, queue, err := e.channel()
 	if err != nil {
 		return err
 	}
+	defer ch.Close()
 
 	log.Printf("Push to channel E:%s -> R:%s -> Q:%s", e.exchangeName, eventName, queue.Name)
 
@@ -43,10 +168,7 @@ func (e *Emitter) Push(eventName string, data []byte) error {
 		eventName,
 		false,
 		false,
-		amqp.Publishing{
-			ContentType: "text/plain",
-			Body:        data,
-		},
+		msg,
 	)
 	if err != nil {
 		return err
@@ -55,15 +177,15 @@ func (e *Emitter) Push(eventName string, data []byte) error {
 	return nil
 }
 
-func NewEventEmitter(conn *amqp.Connection, exchangeName string) (Emitter, error) {
-	emitter := Emitter{
+func NewEventEmitterRabbitmq(conn *Connection, exchangeName string) (Emitter, error) {
+	emitter := &rabbitmqEmitter{
 		connection:   conn,
 		exchangeName: exchangeName,
 	}
 
 	err := emitter.setup()
 	if err != nil {
-		return Emitter{}, err
+		return nil, err
 	}
 
 	return emitter, nil
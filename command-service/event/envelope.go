@@ -0,0 +1,46 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	SchemaVersion = 1
+	ProducerName  = "command-service"
+)
+
+// Envelope is the wire format of every published event. Data carries the
+// event specific payload, everything else is metadata consumers use for
+// deduplication, ordering and tracing.
+type Envelope struct {
+	EventID          string          `json:"event_id"`
+	EventType        string          `json:"event_type"`
+	AggregateID      string          `json:"aggregate_id"`
+	AggregateType    string          `json:"aggregate_type"`
+	AggregateVersion int             `json:"aggregate_version"`
+	OccurredAt       time.Time       `json:"occurred_at"`
+	CorrelationID    string          `json:"correlation_id,omitempty"`
+	CausationID      string          `json:"causation_id,omitempty"`
+	Producer         string          `json:"producer"`
+	SchemaVersion    int             `json:"schema_version"`
+	Data             json.RawMessage `json:"data"`
+}
+
+// Metadata is stored next to every event and copied into its envelope.
+type Metadata struct {
+	CorrelationID string `json:"correlation_id,omitempty"`
+	CausationID   string `json:"causation_id,omitempty"`
+}
+
+type metadataKey struct{}
+
+func ContextWithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+func MetadataFromContext(ctx context.Context) Metadata {
+	metadata, _ := ctx.Value(metadataKey{}).(Metadata)
+	return metadata
+}
@@ -0,0 +1,147 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaConfig locates the cluster and names the topics. Every exchange maps
+// to one topic, TopicPrefix + exchange, so all events of an aggregate share a
+// topic and their partition.
+type KafkaConfig struct {
+	Brokers     []string
+	TopicPrefix string
+}
+
+func (c KafkaConfig) TopicName(exchangeName string) string {
+	return c.TopicPrefix + exchangeName
+}
+
+// PingKafka checks that one of the brokers accepts connections.
+func PingKafka(ctx context.Context, brokers []string) error {
+	var err error
+	for _, broker := range brokers {
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			return conn.Close()
+		}
+	}
+
+	if err == nil {
+		err = errors.New("no Kafka broker configured")
+	}
+	return err
+}
+
+// kafkaEmitter keys every envelope by its aggregate id. The hash balancer
+// sends one aggregate to one partition, which keeps its events in order.
+type kafkaEmitter struct {
+	writer *kafka.Writer
+}
+
+func NewEventEmitterKafka(config KafkaConfig, exchangeName string) (Emitter, error) {
+	if len(config.Brokers) == 0 {
+		return nil, errors.New("no Kafka broker configured")
+	}
+
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(config.Brokers...),
+		Topic:                  config.TopicName(exchangeName),
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+		// the outbox relay hands whole batches to PushEnvelopes, a single
+		// push should not wait for more messages
+		BatchTimeout: 10 * time.Millisecond,
+	}
+
+	return &kafkaEmitter{writer: writer}, nil
+}
+
+func (e *kafkaEmitter) Push(eventName string, data []byte) error {
+	return e.publish(kafka.Message{
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte("text/plain")},
+			{Key: "event-type", Value: []byte(eventName)},
+		},
+		Value: data,
+	})
+}
+
+func (e *kafkaEmitter) PushEnvelope(envelope Envelope) error {
+	msg, err := envelopeMessage(envelope)
+	if err != nil {
+		return err
+	}
+
+	return e.publish(msg)
+}
+
+// PushEnvelopes writes the envelopes in a single request. The events of one
+// aggregate share a partition and keep their order, the envelopes before the
+// first one the brokers refused count as pushed.
+func (e *kafkaEmitter) PushEnvelopes(envelopes []Envelope) (int, error) {
+	msgs := make([]kafka.Message, 0, len(envelopes))
+	for i, envelope := range envelopes {
+		msg, err := envelopeMessage(envelope)
+		if err != nil {
+			return i, err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	log.Printf("Push %d events to topic T:%s", len(msgs), e.writer.Topic)
+
+	err := e.writer.WriteMessages(ctx, msgs...)
+	if err == nil {
+		return len(msgs), nil
+	}
+
+	var writeErrors kafka.WriteErrors
+	if errors.As(err, &writeErrors) {
+		for i, writeErr := range writeErrors {
+			if writeErr != nil {
+				return i, err
+			}
+		}
+	}
+
+	return 0, err
+}
+
+func envelopeMessage(envelope Envelope) (kafka.Message, error) {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	return kafka.Message{
+		Key: []byte(envelope.AggregateID),
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte("application/json")},
+			{Key: "event-type", Value: []byte(envelope.EventType)},
+			{Key: "message-id", Value: []byte(envelope.EventID)},
+			{Key: "correlation-id", Value: []byte(envelope.CorrelationID)},
+		},
+		Time:  envelope.OccurredAt,
+		Value: data,
+	}, nil
+}
+
+func (e *kafkaEmitter) publish(msg kafka.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	log.Printf("Push to topic T:%s -> K:%s", e.writer.Topic, msg.Key)
+
+	return e.writer.WriteMessages(ctx, msg)
+}
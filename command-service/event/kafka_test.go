@@ -0,0 +1,72 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEnvelopeMessageKeysByAggregate(t *testing.T) {
+	envelope := Envelope{
+		EventID:          "event-1",
+		EventType:        "article.created",
+		AggregateID:      "article-1",
+		AggregateType:    "article",
+		AggregateVersion: 1,
+		OccurredAt:       time.Date(2022, 11, 5, 10, 0, 0, 0, time.UTC),
+		CorrelationID:    "request-1",
+		Producer:         ProducerName,
+		SchemaVersion:    SchemaVersion,
+		Data:             json.RawMessage(`{"uuid":"article-1"}`),
+	}
+
+	msg, err := envelopeMessage(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(msg.Key) != "article-1" {
+		t.Errorf("key = %q, want the aggregate id", msg.Key)
+	}
+	if !msg.Time.Equal(envelope.OccurredAt) {
+		t.Errorf("time = %s, want %s", msg.Time, envelope.OccurredAt)
+	}
+
+	headers := map[string]string{}
+	for _, header := range msg.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	want := map[string]string{
+		"content-type":   "application/json",
+		"event-type":     "article.created",
+		"message-id":     "event-1",
+		"correlation-id": "request-1",
+	}
+	for key, value := range want {
+		if headers[key] != value {
+			t.Errorf("header %s = %q, want %q", key, headers[key], value)
+		}
+	}
+
+	var decoded Envelope
+	if err := json.Unmarshal(msg.Value, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.EventID != envelope.EventID || decoded.AggregateVersion != envelope.AggregateVersion {
+		t.Errorf("value = %+v, want the envelope", decoded)
+	}
+}
+
+func TestKafkaTopicName(t *testing.T) {
+	config := KafkaConfig{TopicPrefix: "staging."}
+
+	if topic := config.TopicName("articles"); topic != "staging.articles" {
+		t.Errorf("topic = %q, want %q", topic, "staging.articles")
+	}
+}
+
+func TestNewEventEmitterKafkaNeedsBrokers(t *testing.T) {
+	if _, err := NewEventEmitterKafka(KafkaConfig{}, "articles"); err == nil {
+		t.Error("expected an error without brokers")
+	}
+}
@@ -0,0 +1,83 @@
+package event
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+type MemoryMessage struct {
+	ID          string
+	EventType   string
+	Key         string
+	ContentType string
+	Body        []byte
+}
+
+// MemoryEmitter keeps the pushed events in memory instead of publishing
+// them, so the service runs without a broker. Subscribers are called with
+// every event as it is pushed, which connects the emitter to an in-process
+// consumer.
+type MemoryEmitter struct {
+	mu          sync.Mutex
+	messages    []MemoryMessage
+	subscribers []func(msg MemoryMessage) error
+}
+
+func NewEventEmitterMemory() *MemoryEmitter {
+	return &MemoryEmitter{}
+}
+
+func (e *MemoryEmitter) Push(eventName string, data []byte) error {
+	return e.append(MemoryMessage{
+		EventType:   eventName,
+		ContentType: "text/plain",
+		Body:        data,
+	})
+}
+
+func (e *MemoryEmitter) PushEnvelope(envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return e.append(MemoryMessage{
+		ID:          envelope.EventID,
+		EventType:   envelope.EventType,
+		Key:         envelope.AggregateID,
+		ContentType: "application/json",
+		Body:        data,
+	})
+}
+
+// Subscribe calls fn with every event pushed from now on, an error of fn is
+// returned by the push.
+func (e *MemoryEmitter) Subscribe(fn func(msg MemoryMessage) error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.subscribers = append(e.subscribers, fn)
+}
+
+// Messages returns the pushed events in order.
+func (e *MemoryEmitter) Messages() []MemoryMessage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]MemoryMessage{}, e.messages...)
+}
+
+func (e *MemoryEmitter) append(msg MemoryMessage) error {
+	e.mu.Lock()
+	e.messages = append(e.messages, msg)
+	subscribers := append([]func(msg MemoryMessage) error{}, e.subscribers...)
+	e.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		if err := subscriber(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
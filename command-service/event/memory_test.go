@@ -0,0 +1,66 @@
+package event
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryEmitterDeliversInOrder(t *testing.T) {
+	emitter := NewEventEmitterMemory()
+
+	received := []MemoryMessage{}
+	emitter.Subscribe(func(msg MemoryMessage) error {
+		received = append(received, msg)
+		return nil
+	})
+
+	envelopes := []Envelope{
+		{EventID: "event-1", EventType: "article.created", AggregateID: "article-1", AggregateVersion: 1},
+		{EventID: "event-2", EventType: "article.updated", AggregateID: "article-1", AggregateVersion: 2},
+	}
+
+	pushed, err := PushEnvelopes(emitter, envelopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pushed != len(envelopes) {
+		t.Errorf("pushed = %d, want %d", pushed, len(envelopes))
+	}
+
+	if len(received) != len(envelopes) {
+		t.Fatalf("received %d messages, want %d", len(received), len(envelopes))
+	}
+	for i, msg := range received {
+		if msg.ID != envelopes[i].EventID || msg.EventType != envelopes[i].EventType || msg.Key != "article-1" {
+			t.Errorf("message %d = %+v, want envelope %s keyed by its aggregate", i, msg, envelopes[i].EventID)
+		}
+	}
+
+	if messages := emitter.Messages(); len(messages) != len(envelopes) {
+		t.Errorf("kept %d messages, want %d", len(messages), len(envelopes))
+	}
+}
+
+func TestPushEnvelopesStopsAtFirstFailure(t *testing.T) {
+	emitter := NewEventEmitterMemory()
+
+	errRefused := errors.New("refused")
+	emitter.Subscribe(func(msg MemoryMessage) error {
+		if msg.ID == "event-2" {
+			return errRefused
+		}
+		return nil
+	})
+
+	pushed, err := PushEnvelopes(emitter, []Envelope{
+		{EventID: "event-1", EventType: "article.created", AggregateID: "article-1"},
+		{EventID: "event-2", EventType: "article.updated", AggregateID: "article-1"},
+		{EventID: "event-3", EventType: "article.deleted", AggregateID: "article-1"},
+	})
+	if !errors.Is(err, errRefused) {
+		t.Fatalf("err = %v, want %v", err, errRefused)
+	}
+	if pushed != 1 {
+		t.Errorf("pushed = %d, want the envelopes before the failing one", pushed)
+	}
+}
@@ -0,0 +1,91 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+)
+
+type storeMemory struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	lease time.Duration
+	keys  map[string]*model.IdempotencyKey
+}
+
+func NewStoreMemory(ttl time.Duration) Store {
+	return &storeMemory{
+		ttl:   ttl,
+		lease: defaultLease,
+		keys:  map[string]*model.IdempotencyKey{},
+	}
+}
+
+func (s *storeMemory) Reserve(ctx context.Context, key string, requestHash string) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	record, ok := s.keys[key]
+	// an expired key can be used again, so can a key whose request never
+	// completed it
+	if !ok || record.CreatedAt.Before(now.Add(-s.ttl)) || (!record.StatusCode.Valid && record.LockedUntil.Before(now)) {
+		s.keys[key] = &model.IdempotencyKey{
+			Key:         key,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			LockedUntil: now.Add(s.lease),
+		}
+		return nil, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, ErrKeyReused
+	}
+
+	if !record.StatusCode.Valid {
+		return nil, ErrInProgress
+	}
+
+	result := *record
+	return &result, nil
+}
+
+func (s *storeMemory) Complete(ctx context.Context, key string, statusCode int, response []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.keys[key]; ok {
+		record.StatusCode = sql.NullInt32{Int32: int32(statusCode), Valid: true}
+		record.Response = append([]byte{}, response...)
+	}
+
+	return nil
+}
+
+func (s *storeMemory) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+
+	return nil
+}
+
+func (s *storeMemory) Purge(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, record := range s.keys {
+		if record.CreatedAt.Before(time.Now().Add(-s.ttl)) {
+			delete(s.keys, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
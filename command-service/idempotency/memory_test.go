@@ -0,0 +1,42 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestReserveTakesOverAnExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	store := NewStoreMemory(24 * time.Hour).(*storeMemory)
+	store.lease = 50 * time.Millisecond
+
+	if record, err := store.Reserve(ctx, "create-1", "hash"); record != nil || err != nil {
+		t.Fatalf("Reserve = %+v, %v, want the key owned", record, err)
+	}
+
+	// the request that owns the key is still running
+	if _, err := store.Reserve(ctx, "create-1", "hash"); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("Reserve = %v within the lease, want %v", err, ErrInProgress)
+	}
+
+	// it crashed without completing the key
+	time.Sleep(2 * store.lease)
+
+	if record, err := store.Reserve(ctx, "create-1", "hash"); record != nil || err != nil {
+		t.Fatalf("Reserve = %+v, %v after the lease, want the key taken over", record, err)
+	}
+	if err := store.Complete(ctx, "create-1", http.StatusOK, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// a completed key is replayed, however old its lease
+	time.Sleep(2 * store.lease)
+
+	record, err := store.Reserve(ctx, "create-1", "hash")
+	if err != nil || record == nil || record.StatusCode.Int32 != http.StatusOK {
+		t.Fatalf("Reserve = %+v, %v, want the completed key replayed", record, err)
+	}
+}
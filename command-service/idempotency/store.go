@@ -0,0 +1,178 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultLease is how long a key stays in progress without being completed,
+// the request that reserved it is expected to be answered well within it.
+const defaultLease = 1 * time.Minute
+
+var (
+	ErrKeyReused  = errors.New("Idempotency-Key was already used with a different request")
+	ErrInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+)
+
+// Store records Idempotency-Key headers together with the hash of the request
+// and the response that was sent back for it.
+type Store interface {
+	// Reserve claims the key for requestHash. It returns the recorded key when
+	// the key was already completed within the window, nil when the caller
+	// now owns the key. A key left in progress past its lease, by a request
+	// that crashed, is taken over.
+	Reserve(ctx context.Context, key string, requestHash string) (*model.IdempotencyKey, error)
+	Complete(ctx context.Context, key string, statusCode int, response []byte) error
+	Release(ctx context.Context, key string) error
+	Purge(ctx context.Context) (int64, error)
+}
+
+type storePg struct {
+	db    *sqlx.DB
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewStorePg(db *sqlx.DB, ttl time.Duration) Store {
+	return &storePg{
+		db:    db,
+		ttl:   ttl,
+		lease: defaultLease,
+	}
+}
+
+func (s *storePg) Reserve(ctx context.Context, key string, requestHash string) (*model.IdempotencyKey, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// an expired key can be used again, so can a key whose request never
+	// completed it
+	now := time.Now()
+	sql, args, err := psql.Delete("idempotency_keys").
+		Where(sq.Eq{"key": key}).
+		Where(sq.Or{
+			sq.Lt{"created_at": now.Add(-s.ttl)},
+			sq.And{
+				sq.Eq{"status_code": nil},
+				sq.Lt{"locked_until": now},
+			},
+		}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args, err = psql.Insert("idempotency_keys").
+		SetMap(map[string]interface{}{
+			"key":          key,
+			"request_hash": requestHash,
+			"created_at":   now,
+			"locked_until": now.Add(s.lease),
+		}).
+		Suffix("ON CONFLICT (key) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if inserted == 1 {
+		return nil, tx.Commit()
+	}
+
+	sql, args, err = psql.Select("*").
+		From("idempotency_keys").
+		Where(sq.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	record := model.IdempotencyKey{}
+	err = tx.GetContext(ctx, &record, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, ErrKeyReused
+	}
+
+	if !record.StatusCode.Valid {
+		return nil, ErrInProgress
+	}
+
+	return &record, tx.Commit()
+}
+
+func (s *storePg) Complete(ctx context.Context, key string, statusCode int, response []byte) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update("idempotency_keys").
+		SetMap(map[string]interface{}{
+			"status_code": statusCode,
+			"response":    string(response),
+		}).
+		Where(sq.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+func (s *storePg) Release(ctx context.Context, key string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Delete("idempotency_keys").
+		Where(sq.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+// Purge removes every key older than the replay window.
+func (s *storePg) Purge(ctx context.Context) (int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Delete("idempotency_keys").
+		Where(sq.Lt{"created_at": time.Now().Add(-s.ttl)}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
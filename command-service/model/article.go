@@ -2,12 +2,31 @@ package model
 
 import "time"
 
+// Article statuses, only published articles are public.
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
 type Article struct {
-	ID        int       `db:"id" json:"id"`
 	Uuid      string    `db:"uuid" json:"uuid"`
+	Slug      string    `db:"slug" json:"slug"`
 	Author    string    `db:"author" json:"author"`
 	Title     string    `db:"title" json:"title"`
 	Body      string    `db:"body" json:"body"`
+	Tags      []string  `db:"tags" json:"tags"`
+	Category  string    `db:"category" json:"category"`
+	Status    string    `db:"status" json:"status"`
+	Version   int       `db:"version" json:"version"`
+	Revision  int       `db:"revision" json:"revision"`
+	UpdatedBy string    `db:"updated_by" json:"updated_by"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+
+	// set while the article is in the trash
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+	// set while the article is scheduled to be published
+	PublishAt *time.Time `db:"publish_at" json:"publish_at,omitempty"`
 }
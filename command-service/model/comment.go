@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Comment statuses, hidden comments were taken down by a moderator.
+const (
+	CommentVisible = "visible"
+	CommentHidden  = "hidden"
+)
+
+// Comment is a comment on an article or a reply to another comment. ThreadID
+// is the top-level comment of the thread, Depth is 0 for a top-level comment.
+type Comment struct {
+	Uuid      string    `json:"uuid"`
+	ArticleID string    `json:"article_id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	ThreadID  string    `json:"thread_id"`
+	Depth     int       `json:"depth"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Status    string    `json:"status"`
+	Version   int       `json:"version"`
+	UpdatedBy string    `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// set once the comment is deleted
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// set by the last moderation
+	ModeratedBy      string `json:"moderated_by,omitempty"`
+	ModerationReason string `json:"moderation_reason,omitempty"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Event struct {
+	ID            int64     `db:"id" json:"id"`
+	EventID       string    `db:"event_id" json:"event_id"`
+	AggregateID   string    `db:"aggregate_id" json:"aggregate_id"`
+	AggregateType string    `db:"aggregate_type" json:"aggregate_type"`
+	Sequence      int       `db:"sequence" json:"sequence"`
+	EventType     string    `db:"event_type" json:"event_type"`
+	Payload       []byte    `db:"payload" json:"payload"`
+	Metadata      []byte    `db:"metadata" json:"metadata"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
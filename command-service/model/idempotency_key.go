@@ -0,0 +1,16 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type IdempotencyKey struct {
+	Key         string        `db:"key" json:"key"`
+	RequestHash string        `db:"request_hash" json:"request_hash"`
+	StatusCode  sql.NullInt32 `db:"status_code" json:"status_code"`
+	Response    []byte        `db:"response" json:"response"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	// a key in progress can be taken over once its lease ran out
+	LockedUntil time.Time `db:"locked_until" json:"locked_until"`
+}
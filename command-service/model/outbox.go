@@ -0,0 +1,20 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OutboxMessage struct {
+	ID            int64          `db:"id" json:"id"`
+	AggregateID   string         `db:"aggregate_id" json:"aggregate_id"`
+	EventName     string         `db:"event_name" json:"event_name"`
+	Payload       []byte         `db:"payload" json:"payload"`
+	Attempts      int            `db:"attempts" json:"attempts"`
+	LastError     sql.NullString `db:"last_error" json:"last_error"`
+	NextAttemptAt time.Time      `db:"next_attempt_at" json:"next_attempt_at"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+	SentAt        sql.NullTime   `db:"sent_at" json:"sent_at"`
+	// set once the row failed too often, it is no longer published
+	ParkedAt sql.NullTime `db:"parked_at" json:"parked_at"`
+}
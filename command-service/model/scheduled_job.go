@@ -0,0 +1,20 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ScheduledJob is an action on an aggregate that is due at RunAt. There is at
+// most one pending job per aggregate and job type.
+type ScheduledJob struct {
+	ID          int64          `db:"id" json:"id"`
+	AggregateID string         `db:"aggregate_id" json:"aggregate_id"`
+	JobType     string         `db:"job_type" json:"job_type"`
+	RunAt       time.Time      `db:"run_at" json:"run_at"`
+	Attempts    int            `db:"attempts" json:"attempts"`
+	LastError   sql.NullString `db:"last_error" json:"last_error"`
+	Cancelled   bool           `db:"cancelled" json:"cancelled"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	DoneAt      sql.NullTime   `db:"done_at" json:"done_at"`
+}
@@ -0,0 +1,356 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultPollInterval = 1 * time.Second
+	defaultBatchSize    = 100
+	defaultMaxBackoff   = 5 * time.Minute
+	defaultLease        = 1 * time.Minute
+	defaultMaxAttempts  = 20
+)
+
+// Relay publishes pending outbox rows through the event emitter and marks
+// them as sent. A batch is claimed in a short transaction that leases its
+// rows by moving next_attempt_at past the publishing, so no lock or
+// connection is held while the broker is slow. A claim only takes the rows of
+// an aggregate once every older row of that aggregate is sent, several
+// command-service replicas can run a relay against the same table and the
+// events of an aggregate still go out in order, while a failing row only
+// holds back its own aggregate. A row that failed maxAttempts times is parked
+// with its last error and the events behind it go out.
+// A lease that runs out before its batch is marked sent, after a crash for
+// example, makes the rows due again, consumers drop the duplicates.
+type Relay struct {
+	db      *sqlx.DB
+	emitter event.Emitter
+
+	pollInterval time.Duration
+	batchSize    uint64
+	maxBackoff   time.Duration
+	lease        time.Duration
+	maxAttempts  int
+}
+
+func NewRelay(db *sqlx.DB, emitter event.Emitter) *Relay {
+	return &Relay{
+		db:           db,
+		emitter:      emitter,
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		maxBackoff:   defaultMaxBackoff,
+		lease:        defaultLease,
+		maxAttempts:  defaultMaxAttempts,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	log.Printf("Outbox relay started, polling every %s\n", r.pollInterval)
+
+	for {
+		sent, err := r.relayPending(ctx)
+		if err != nil {
+			log.Println("Outbox relay error:", err)
+		}
+
+		// a full batch means more rows are waiting, a bulk write is
+		// drained without waiting for the next tick
+		if err == nil && uint64(sent) == r.batchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeSent deletes the rows sent before sentBefore and returns how many
+// were deleted.
+func (r *Relay) PurgeSent(ctx context.Context, sentBefore time.Time) (int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Delete("outbox").
+		Where(sq.Lt{"sent_at": sentBefore}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// relayPending publishes the pending rows that are due as one batch and
+// returns how many were sent.
+func (r *Relay) relayPending(ctx context.Context) (int, error) {
+	due, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent, pushErr := r.publish(due)
+
+	if err := r.markSent(ctx, due[:sent]); err != nil {
+		return 0, err
+	}
+
+	if pushErr != nil {
+		msg := due[sent]
+		log.Printf("Outbox message %d (%s) failed on attempt %d: %s", msg.ID, msg.EventName, msg.Attempts+1, pushErr)
+		if err := r.markFailed(ctx, msg, pushErr); err != nil {
+			return 0, err
+		}
+
+		// the rows behind the failed one are claimed again without waiting
+		// for their lease, those of its aggregate wait for it
+		if err := r.release(ctx, due[sent+1:]); err != nil {
+			return 0, err
+		}
+	}
+
+	return sent, nil
+}
+
+// claim leases the due rows of the aggregates whose oldest pending row is
+// due. That row stands for its aggregate and is locked with SKIP LOCKED: a
+// concurrent claim skips it, or finds it leased once this claim committed,
+// and takes other aggregates meanwhile. The rows of an aggregate are taken
+// up to the first one that is not due.
+func (r *Relay) claim(ctx context.Context) ([]model.OutboxMessage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	pending := sq.Eq{"sent_at": nil, "parked_at": nil}
+	oldest := sq.Select("MIN(id)").
+		From("outbox").
+		Where(pending).
+		GroupBy("aggregate_id")
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("aggregate_id").
+		From("outbox").
+		Where(sq.Expr("id IN (?)", oldest)).
+		// checked again on the latest version of a row locked meanwhile
+		Where(pending).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("id").
+		Limit(r.batchSize).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates := []string{}
+	err = tx.SelectContext(ctx, &aggregates, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(aggregates) == 0 {
+		return []model.OutboxMessage{}, nil
+	}
+
+	sql, args, err = psql.Select("*").
+		From("outbox").
+		Where(pending).
+		Where(sq.Eq{"aggregate_id": aggregates}).
+		OrderBy("id").
+		Limit(r.batchSize).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []model.OutboxMessage{}
+	err = tx.SelectContext(ctx, &messages, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	due := duePrefixes(messages, now)
+
+	sql, args, err = psql.Update("outbox").
+		Set("next_attempt_at", now.Add(r.lease)).
+		Where(sq.Eq{"id": outboxIDs(due)}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, sql, args...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return due, nil
+}
+
+// duePrefixes keeps the rows of every aggregate up to its first row that is
+// not due, a row backing off or leased holds back the newer rows of its
+// aggregate. The messages are in outbox order.
+func duePrefixes(messages []model.OutboxMessage, now time.Time) []model.OutboxMessage {
+	waiting := map[string]bool{}
+	due := []model.OutboxMessage{}
+	for _, msg := range messages {
+		if waiting[msg.AggregateID] {
+			continue
+		}
+		if msg.NextAttemptAt.After(now) {
+			waiting[msg.AggregateID] = true
+			continue
+		}
+		due = append(due, msg)
+	}
+
+	return due
+}
+
+// publish sends the rows in order and returns how many were sent before one
+// failed. Consecutive envelopes go out as one batch, rows written before
+// envelopes existed only hold the article and are pushed as they are.
+func (r *Relay) publish(messages []model.OutboxMessage) (int, error) {
+	sent := 0
+	for sent < len(messages) {
+		envelopes := []event.Envelope{}
+		for _, msg := range messages[sent:] {
+			var envelope event.Envelope
+			if err := json.Unmarshal(msg.Payload, &envelope); err != nil || envelope.EventID == "" {
+				break
+			}
+			envelopes = append(envelopes, envelope)
+		}
+
+		if len(envelopes) == 0 {
+			msg := messages[sent]
+			if err := r.emitter.Push(msg.EventName, msg.Payload); err != nil {
+				return sent, err
+			}
+			sent++
+			continue
+		}
+
+		pushed, err := event.PushEnvelopes(r.emitter, envelopes)
+		sent += pushed
+		if err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+func (r *Relay) markSent(ctx context.Context, messages []model.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update("outbox").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("sent_at", time.Now()).
+		Where(sq.Eq{"id": outboxIDs(messages)}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+// markFailed backs the row off, or parks it once it failed maxAttempts
+// times so the newer rows of its aggregate are published.
+func (r *Relay) markFailed(ctx context.Context, msg model.OutboxMessage, pushErr error) error {
+	attempts := msg.Attempts + 1
+
+	update := map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      pushErr.Error(),
+		"next_attempt_at": time.Now().Add(r.backoff(attempts)),
+	}
+	if attempts >= r.maxAttempts {
+		log.Printf("Outbox message %d (%s) parked after %d attempts", msg.ID, msg.EventName, attempts)
+		update["parked_at"] = time.Now()
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update("outbox").
+		SetMap(update).
+		Where(sq.Eq{"id": msg.ID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+// release ends the lease of rows that were claimed but not published.
+func (r *Relay) release(ctx context.Context, messages []model.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update("outbox").
+		Set("next_attempt_at", time.Now()).
+		Where(sq.Eq{"id": outboxIDs(messages)}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+func outboxIDs(messages []model.OutboxMessage) []int64 {
+	ids := make([]int64, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+
+	return ids
+}
+
+// backoff grows exponentially with the number of attempts: 2s, 4s, 8s, ...
+// capped at maxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := time.Duration(math.Pow(2, float64(attempts))) * time.Second
+	if delay <= 0 || delay > r.maxBackoff {
+		return r.maxBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+)
+
+func TestDuePrefixesKeepAggregateOrder(t *testing.T) {
+	now := time.Date(2022, 11, 5, 10, 0, 0, 0, time.UTC)
+	due, later := now.Add(-time.Second), now.Add(time.Minute)
+
+	messages := []model.OutboxMessage{
+		{ID: 1, AggregateID: "article-1", NextAttemptAt: due},
+		// backing off, holds back the newer rows of article-2 only
+		{ID: 2, AggregateID: "article-2", NextAttemptAt: later},
+		{ID: 3, AggregateID: "article-1", NextAttemptAt: due},
+		{ID: 4, AggregateID: "article-2", NextAttemptAt: due},
+		{ID: 5, AggregateID: "article-3", NextAttemptAt: due},
+		// leased by an earlier claim, the rows behind it stay
+		{ID: 6, AggregateID: "article-1", NextAttemptAt: later},
+		{ID: 7, AggregateID: "article-1", NextAttemptAt: due},
+	}
+
+	ids := outboxIDs(duePrefixes(messages, now))
+
+	want := []int64{1, 3, 5}
+	if len(ids) != len(want) {
+		t.Fatalf("due = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("due = %v, want %v", ids, want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: article_command.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ArticleCommand_Store_FullMethodName  = "/article.ArticleCommand/Store"
+	ArticleCommand_Update_FullMethodName = "/article.ArticleCommand/Update"
+	ArticleCommand_Delete_FullMethodName = "/article.ArticleCommand/Delete"
+)
+
+// ArticleCommandClient is the client API for ArticleCommand service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ArticleCommandClient interface {
+	Store(ctx context.Context, in *StoreArticleRequest, opts ...grpc.CallOption) (*ArticleResponse, error)
+	Update(ctx context.Context, in *UpdateArticleRequest, opts ...grpc.CallOption) (*ArticleResponse, error)
+	Delete(ctx context.Context, in *DeleteArticleRequest, opts ...grpc.CallOption) (*ArticleResponse, error)
+}
+
+type articleCommandClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewArticleCommandClient(cc grpc.ClientConnInterface) ArticleCommandClient {
+	return &articleCommandClient{cc}
+}
+
+func (c *articleCommandClient) Store(ctx context.Context, in *StoreArticleRequest, opts ...grpc.CallOption) (*ArticleResponse, error) {
+	out := new(ArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleCommand_Store_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleCommandClient) Update(ctx context.Context, in *UpdateArticleRequest, opts ...grpc.CallOption) (*ArticleResponse, error) {
+	out := new(ArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleCommand_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleCommandClient) Delete(ctx context.Context, in *DeleteArticleRequest, opts ...grpc.CallOption) (*ArticleResponse, error) {
+	out := new(ArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleCommand_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ArticleCommandServer is the server API for ArticleCommand service.
+// All implementations must embed UnimplementedArticleCommandServer
+// for forward compatibility
+type ArticleCommandServer interface {
+	Store(context.Context, *StoreArticleRequest) (*ArticleResponse, error)
+	Update(context.Context, *UpdateArticleRequest) (*ArticleResponse, error)
+	Delete(context.Context, *DeleteArticleRequest) (*ArticleResponse, error)
+	mustEmbedUnimplementedArticleCommandServer()
+}
+
+// UnimplementedArticleCommandServer must be embedded to have forward compatible implementations.
+type UnimplementedArticleCommandServer struct {
+}
+
+func (UnimplementedArticleCommandServer) Store(context.Context, *StoreArticleRequest) (*ArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Store not implemented")
+}
+func (UnimplementedArticleCommandServer) Update(context.Context, *UpdateArticleRequest) (*ArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedArticleCommandServer) Delete(context.Context, *DeleteArticleRequest) (*ArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedArticleCommandServer) mustEmbedUnimplementedArticleCommandServer() {}
+
+// UnsafeArticleCommandServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ArticleCommandServer will
+// result in compilation errors.
+type UnsafeArticleCommandServer interface {
+	mustEmbedUnimplementedArticleCommandServer()
+}
+
+func RegisterArticleCommandServer(s grpc.ServiceRegistrar, srv ArticleCommandServer) {
+	s.RegisterService(&ArticleCommand_ServiceDesc, srv)
+}
+
+func _ArticleCommand_Store_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleCommandServer).Store(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleCommand_Store_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleCommandServer).Store(ctx, req.(*StoreArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleCommand_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleCommandServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleCommand_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleCommandServer).Update(ctx, req.(*UpdateArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleCommand_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleCommandServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleCommand_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleCommandServer).Delete(ctx, req.(*DeleteArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ArticleCommand_ServiceDesc is the grpc.ServiceDesc for ArticleCommand service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ArticleCommand_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "article.ArticleCommand",
+	HandlerType: (*ArticleCommandServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Store",
+			Handler:    _ArticleCommand_Store_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ArticleCommand_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ArticleCommand_Delete_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "article_command.proto",
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+)
+
+// StoreMemory keeps the jobs in memory, Schedule and Cancel stand in for the
+// functions of the same name that write the jobs table.
+type StoreMemory struct {
+	mu     sync.Mutex
+	nextID int64
+	jobs   []*model.ScheduledJob
+}
+
+func NewStoreMemory() *StoreMemory {
+	return &StoreMemory{}
+}
+
+func (s *StoreMemory) Schedule(aggregateID string, jobType string, runAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job := s.pending(aggregateID, jobType); job != nil {
+		job.RunAt = dbTime(runAt)
+		job.Attempts = 0
+		job.LastError = sql.NullString{}
+		return
+	}
+
+	s.nextID++
+	s.jobs = append(s.jobs, &model.ScheduledJob{
+		ID:          s.nextID,
+		AggregateID: aggregateID,
+		JobType:     jobType,
+		RunAt:       dbTime(runAt),
+		CreatedAt:   dbTime(time.Now()),
+	})
+}
+
+func (s *StoreMemory) Cancel(aggregateID string, jobType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job := s.pending(aggregateID, jobType); job != nil {
+		job.Cancelled = true
+		job.DoneAt = sql.NullTime{Time: dbTime(time.Now()), Valid: true}
+	}
+}
+
+// Jobs returns a copy of every job, done ones included.
+func (s *StoreMemory) Jobs() []model.ScheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]model.ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+
+	return jobs
+}
+
+func (s *StoreMemory) Claim(ctx context.Context, now time.Time, limit uint64, lease time.Duration) ([]model.ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := []*model.ScheduledJob{}
+	for _, job := range s.jobs {
+		if !job.DoneAt.Valid && !job.RunAt.After(dbTime(now)) {
+			due = append(due, job)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].RunAt.Before(due[j].RunAt)
+	})
+	if uint64(len(due)) > limit {
+		due = due[:limit]
+	}
+
+	jobs := make([]model.ScheduledJob, 0, len(due))
+	for _, job := range due {
+		job.RunAt = dbTime(now.Add(lease))
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, nil
+}
+
+func (s *StoreMemory) Complete(ctx context.Context, job model.ScheduledJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored := s.claimed(job); stored != nil {
+		stored.Attempts = job.Attempts + 1
+		stored.DoneAt = sql.NullTime{Time: dbTime(time.Now()), Valid: true}
+	}
+
+	return nil
+}
+
+func (s *StoreMemory) Retry(ctx context.Context, job model.ScheduledJob, jobErr error, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored := s.claimed(job); stored != nil {
+		stored.Attempts = job.Attempts + 1
+		stored.LastError = sql.NullString{String: jobErr.Error(), Valid: true}
+		stored.RunAt = dbTime(retryAt)
+	}
+
+	return nil
+}
+
+func (s *StoreMemory) pending(aggregateID string, jobType string) *model.ScheduledJob {
+	for _, job := range s.jobs {
+		if job.AggregateID == aggregateID && job.JobType == jobType && !job.DoneAt.Valid {
+			return job
+		}
+	}
+
+	return nil
+}
+
+// claimed returns the stored job when it was not rescheduled since it was
+// claimed.
+func (s *StoreMemory) claimed(job model.ScheduledJob) *model.ScheduledJob {
+	for _, stored := range s.jobs {
+		if stored.ID == job.ID && stored.RunAt.Equal(job.RunAt) && !stored.DoneAt.Valid {
+			return stored
+		}
+	}
+
+	return nil
+}
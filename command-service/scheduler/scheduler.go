@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 50
+	defaultLease        = 1 * time.Minute
+	defaultMaxBackoff   = 30 * time.Minute
+)
+
+// Handler runs a due job. A job that returns an error is retried with an
+// exponential backoff, so handlers must be safe to run again.
+type Handler func(ctx context.Context, job model.ScheduledJob) error
+
+// Scheduler polls the store for due jobs and runs the handler of their type.
+// Several command-service replicas can run a scheduler against the same
+// store, a job is only ever claimed by one of them at a time.
+type Scheduler struct {
+	store    Store
+	handlers map[string]Handler
+
+	pollInterval time.Duration
+	batchSize    uint64
+	lease        time.Duration
+	maxBackoff   time.Duration
+}
+
+func NewScheduler(store Store) *Scheduler {
+	return &Scheduler{
+		store:        store,
+		handlers:     map[string]Handler{},
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		lease:        defaultLease,
+		maxBackoff:   defaultMaxBackoff,
+	}
+}
+
+// Handle registers the handler of a job type, before Run is called.
+func (s *Scheduler) Handle(jobType string, handler Handler) {
+	s.handlers[jobType] = handler
+}
+
+// Run polls the store until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	log.Printf("Scheduler started, polling every %s\n", s.pollInterval)
+
+	for {
+		if _, err := s.RunDue(ctx, time.Now()); err != nil {
+			log.Println("Scheduler error:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the jobs that are due at now and returns how many succeeded.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := s.store.Claim(ctx, now, s.batchSize, s.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, job := range jobs {
+		jobErr := s.run(ctx, job)
+		if jobErr == nil {
+			if err := s.store.Complete(ctx, job); err != nil {
+				return done, err
+			}
+			done++
+			continue
+		}
+
+		log.Printf("Scheduled %s of %s failed on attempt %d: %s", job.JobType, job.AggregateID, job.Attempts+1, jobErr)
+		if err := s.store.Retry(ctx, job, jobErr, time.Now().Add(s.backoff(job.Attempts+1))); err != nil {
+			return done, err
+		}
+	}
+
+	return done, nil
+}
+
+func (s *Scheduler) run(ctx context.Context, job model.ScheduledJob) error {
+	handler, ok := s.handlers[job.JobType]
+	if !ok {
+		return fmt.Errorf("no handler for job type %q", job.JobType)
+	}
+
+	return handler(ctx, job)
+}
+
+// backoff grows exponentially with the number of attempts: 2s, 4s, 8s, ...
+// capped at maxBackoff.
+func (s *Scheduler) backoff(attempts int) time.Duration {
+	delay := time.Duration(math.Pow(2, float64(attempts))) * time.Second
+	if delay <= 0 || delay > s.maxBackoff {
+		return s.maxBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/command-service/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store keeps the scheduled jobs. A claimed job is leased: its run_at moves
+// to the end of the lease, so it comes due again when the replica running it
+// dies. Complete and Retry only touch a job that still has the run_at it was
+// claimed with, a job rescheduled while it ran stays pending.
+type Store interface {
+	// Claim leases up to limit jobs that are due at now.
+	Claim(ctx context.Context, now time.Time, limit uint64, lease time.Duration) ([]model.ScheduledJob, error)
+	Complete(ctx context.Context, job model.ScheduledJob) error
+	Retry(ctx context.Context, job model.ScheduledJob, jobErr error, retryAt time.Time) error
+}
+
+// Schedule sets the pending job of the aggregate to run at runAt, inside the
+// transaction that records the reason for it.
+func Schedule(ctx context.Context, tx *sqlx.Tx, aggregateID string, jobType string, runAt time.Time) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert("scheduled_jobs").
+		SetMap(map[string]interface{}{
+			"aggregate_id": aggregateID,
+			"job_type":     jobType,
+			"run_at":       dbTime(runAt),
+			"created_at":   dbTime(time.Now()),
+		}).
+		Suffix("ON CONFLICT (aggregate_id, job_type) WHERE done_at IS NULL DO UPDATE SET run_at = EXCLUDED.run_at, attempts = 0, last_error = NULL").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	return err
+}
+
+// Cancel closes the pending job of the aggregate, if there is one.
+func Cancel(ctx context.Context, tx *sqlx.Tx, aggregateID string, jobType string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update("scheduled_jobs").
+		SetMap(map[string]interface{}{
+			"cancelled": true,
+			"done_at":   dbTime(time.Now()),
+		}).
+		Where(sq.Eq{"aggregate_id": aggregateID, "job_type": jobType, "done_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	return err
+}
+
+type storePg struct {
+	db *sqlx.DB
+}
+
+func NewStorePg(db *sqlx.DB) Store {
+	return &storePg{
+		db: db,
+	}
+}
+
+// Claim locks the due jobs with SKIP LOCKED, so replicas polling at the same
+// time never claim the same job.
+func (s *storePg) Claim(ctx context.Context, now time.Time, limit uint64, lease time.Duration) ([]model.ScheduledJob, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From("scheduled_jobs").
+		Where(sq.Eq{"done_at": nil}).
+		Where(sq.LtOrEq{"run_at": dbTime(now)}).
+		OrderBy("run_at").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []model.ScheduledJob{}
+	err = tx.SelectContext(ctx, &jobs, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	leasedUntil := dbTime(now.Add(lease))
+	for i := range jobs {
+		sql, args, err := psql.Update("scheduled_jobs").
+			Set("run_at", leasedUntil).
+			Where(sq.Eq{"id": jobs[i].ID}).
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, sql, args...); err != nil {
+			return nil, err
+		}
+
+		jobs[i].RunAt = leasedUntil
+	}
+
+	return jobs, tx.Commit()
+}
+
+func (s *storePg) Complete(ctx context.Context, job model.ScheduledJob) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update("scheduled_jobs").
+		SetMap(map[string]interface{}{
+			"attempts": job.Attempts + 1,
+			"done_at":  dbTime(time.Now()),
+		}).
+		Where(sq.Eq{"id": job.ID, "run_at": job.RunAt, "done_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+func (s *storePg) Retry(ctx context.Context, job model.ScheduledJob, jobErr error, retryAt time.Time) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update("scheduled_jobs").
+		SetMap(map[string]interface{}{
+			"attempts":   job.Attempts + 1,
+			"last_error": jobErr.Error(),
+			"run_at":     dbTime(retryAt),
+		}).
+		Where(sq.Eq{"id": job.ID, "run_at": job.RunAt, "done_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+// dbTime is t as stored in a TIMESTAMP(0) column, run_at is compared for
+// equality so it must not be rounded by the database.
+func dbTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+func (app *Config) StoreCommentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestStoreComment
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.ArticleID = uuid
+
+	comment, err := app.cmdComment.Store(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Comment Successfully Created",
+		Data:    dto.CommentToResponseDTO(comment),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(comment.Version)}})
+}
+
+func (app *Config) UpdateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestUpdateComment
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	comment, err := app.cmdComment.Update(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Comment Successfully Updated",
+		Data:    dto.CommentToResponseDTO(comment),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(comment.Version)}})
+}
+
+func (app *Config) DeleteCommentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestDeleteComment
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	comment, err := app.cmdComment.Delete(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Comment Successfully Deleted",
+		Data:    dto.CommentToResponseDTO(comment),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(comment.Version)}})
+}
+
+func (app *Config) ModerateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestModerateComment
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	comment, err := app.cmdComment.Moderate(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Comment Successfully Moderated",
+		Data:    dto.CommentToResponseDTO(comment),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(comment.Version)}})
+}
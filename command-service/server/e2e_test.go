@@ -0,0 +1,311 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Adhiana46/command-service/cache"
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/Adhiana46/command-service/event"
+)
+
+// testApp is the service wired to the in-memory event store, transport and
+// cache.
+type testApp struct {
+	server  *httptest.Server
+	emitter *event.MemoryEmitter
+	cache   cache.Cache
+}
+
+func newTestApp(t *testing.T, options ...func(*Config)) *testApp {
+	t.Helper()
+
+	emitter := event.NewEventEmitterMemory()
+	c := cache.NewCacheMemory()
+
+	app := NewMemory(emitter, c)
+	for _, option := range options {
+		option(app)
+	}
+
+	server := httptest.NewServer(app.Routes())
+	t.Cleanup(server.Close)
+
+	return &testApp{
+		server:  server,
+		emitter: emitter,
+		cache:   c,
+	}
+}
+
+// do sends a request as alice and decodes the article of the response.
+func (a *testApp) do(t *testing.T, method string, path string, body any, headers http.Header) (*http.Response, *dto.ResponseArticle) {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, a.server.URL+path, &reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Subject", "alice")
+	req.Header.Set("X-Auth-Roles", "author")
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	payload := struct {
+		Error   bool                 `json:"error"`
+		Message string               `json:"message"`
+		Data    *dto.ResponseArticle `json:"data"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+
+	return res, payload.Data
+}
+
+// envelopes decodes the published envelopes.
+func (a *testApp) envelopes(t *testing.T) []event.Envelope {
+	t.Helper()
+
+	envelopes := []event.Envelope{}
+	for _, msg := range a.emitter.Messages() {
+		envelope := event.Envelope{}
+		if err := json.Unmarshal(msg.Body, &envelope); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Key != envelope.AggregateID {
+			t.Errorf("message key = %q, want the aggregate %q", msg.Key, envelope.AggregateID)
+		}
+
+		envelopes = append(envelopes, envelope)
+	}
+
+	return envelopes
+}
+
+// cachedArticle reads the single article cache, nil when the article isn't
+// cached.
+func (a *testApp) cachedArticle(t *testing.T, uuid string) *dto.ResponseArticle {
+	t.Helper()
+
+	value, err := a.cache.Get(context.Background(), "article-"+uuid)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	article := dto.ResponseArticle{}
+	if err := json.Unmarshal(value, &article); err != nil {
+		t.Fatal(err)
+	}
+	return &article
+}
+
+func TestArticleLifecycle(t *testing.T) {
+	app := newTestApp(t)
+
+	res, article := app.do(t, http.MethodPost, "/articles", dto.RequestStoreArticle{
+		Title: "First title",
+		Body:  "First body",
+		Tags:  []string{"go"},
+	}, nil)
+	if res.StatusCode != http.StatusOK || article == nil {
+		t.Fatalf("create answered %d", res.StatusCode)
+	}
+	if res.Header.Get("ETag") != `"1"` || article.Author != "alice" {
+		t.Errorf("created article %+v with ETag %s, want version 1 by alice", article, res.Header.Get("ETag"))
+	}
+	if cached := app.cachedArticle(t, article.Uuid); cached == nil || cached.Title != "First title" {
+		t.Errorf("cached %+v after create, want the created article", cached)
+	}
+
+	uuid := article.Uuid
+
+	// update with the version just read, the new title moves the slug too
+	res, article = app.do(t, http.MethodPut, "/articles/"+uuid, dto.RequestUpdateArticle{
+		Title: "Second title",
+		Body:  "Second body",
+	}, http.Header{"If-Match": {`"1"`}})
+	if res.StatusCode != http.StatusOK || res.Header.Get("ETag") != `"3"` {
+		t.Fatalf("update answered %d with ETag %s, want 200 with version 3", res.StatusCode, res.Header.Get("ETag"))
+	}
+	if article.Slug != "second-title" {
+		t.Errorf("slug = %q after update, want second-title", article.Slug)
+	}
+	if cached := app.cachedArticle(t, uuid); cached == nil || cached.Title != "Second title" {
+		t.Errorf("cached %+v after update, want the updated article", cached)
+	}
+
+	// an update based on the stale version is refused
+	res, _ = app.do(t, http.MethodPut, "/articles/"+uuid, dto.RequestUpdateArticle{
+		Title: "Lost title",
+		Body:  "Lost body",
+	}, http.Header{"If-Match": {`"1"`}})
+	if res.StatusCode != http.StatusConflict {
+		t.Fatalf("stale update answered %d, want 409", res.StatusCode)
+	}
+
+	res, article = app.do(t, http.MethodDelete, "/articles/"+uuid, nil, http.Header{"If-Match": {`"3"`}})
+	if res.StatusCode != http.StatusOK || article.DeletedAt == nil {
+		t.Fatalf("delete answered %d with %+v, want the article in the trash", res.StatusCode, article)
+	}
+	if cached := app.cachedArticle(t, uuid); cached != nil {
+		t.Errorf("cached %+v after delete, want nothing", cached)
+	}
+
+	// a deleted article can't be updated, only restored
+	res, _ = app.do(t, http.MethodPut, "/articles/"+uuid, dto.RequestUpdateArticle{
+		Title: "Lost title",
+		Body:  "Lost body",
+	}, nil)
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("update of a deleted article answered %d, want 404", res.StatusCode)
+	}
+
+	res, article = app.do(t, http.MethodPost, "/articles/"+uuid+"/restore", nil, nil)
+	if res.StatusCode != http.StatusOK || article.DeletedAt != nil || article.Title != "Second title" {
+		t.Fatalf("restore answered %d with %+v, want the updated article back", res.StatusCode, article)
+	}
+	if cached := app.cachedArticle(t, uuid); cached == nil || cached.Version != article.Version {
+		t.Errorf("cached %+v after restore, want version %d", cached, article.Version)
+	}
+
+	envelopes := app.envelopes(t)
+	wantTypes := []string{"article.created", "article.updated", "article.slug_changed", "article.deleted", "article.restored"}
+	if len(envelopes) != len(wantTypes) {
+		t.Fatalf("published %d events, want %d", len(envelopes), len(wantTypes))
+	}
+	for i, envelope := range envelopes {
+		if envelope.EventType != wantTypes[i] || envelope.AggregateID != uuid || envelope.AggregateVersion != i+1 {
+			t.Errorf("event %d = %s %s version %d, want %s version %d", i, envelope.EventType, envelope.AggregateID, envelope.AggregateVersion, wantTypes[i], i+1)
+		}
+	}
+
+	// every event carries the article as it was right after it
+	created := dto.ResponseArticle{}
+	if err := json.Unmarshal(envelopes[0].Data, &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Title != "First title" {
+		t.Errorf("created event carries %q, want the first title", created.Title)
+	}
+}
+
+func TestStoreArticleReplaysIdempotencyKey(t *testing.T) {
+	app := newTestApp(t)
+
+	request := dto.RequestStoreArticle{
+		Title: "Title",
+		Body:  "Body",
+	}
+	headers := http.Header{"Idempotency-Key": {"create-1"}}
+
+	res, first := app.do(t, http.MethodPost, "/articles", request, headers)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("create answered %d", res.StatusCode)
+	}
+
+	res, replayed := app.do(t, http.MethodPost, "/articles", request, headers)
+	if res.StatusCode != http.StatusOK || res.Header.Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("retry answered %d, want the replayed response", res.StatusCode)
+	}
+	if replayed.Uuid != first.Uuid {
+		t.Errorf("retry answered article %s, want %s", replayed.Uuid, first.Uuid)
+	}
+
+	// the same key for another request is refused
+	request.Title = "Other title"
+	res, _ = app.do(t, http.MethodPost, "/articles", request, headers)
+	if res.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("reused key answered %d, want 422", res.StatusCode)
+	}
+
+	if messages := app.emitter.Messages(); len(messages) != 1 {
+		t.Errorf("published %d events, want the single create", len(messages))
+	}
+}
+
+func TestGetEventsFeed(t *testing.T) {
+	app := newTestApp(t)
+
+	for _, title := range []string{"First", "Second"} {
+		res, _ := app.do(t, http.MethodPost, "/articles", dto.RequestStoreArticle{Title: title, Body: "Body"}, nil)
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("create answered %d", res.StatusCode)
+		}
+	}
+
+	readFeed := func(path string) []dto.ResponseEvent {
+		res, err := http.Get(app.server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		payload := struct {
+			Data []dto.ResponseEvent `json:"data"`
+		}{}
+		if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		return payload.Data
+	}
+
+	events := readFeed("/events")
+	if len(events) != 2 || events[0].EventType != "article.created" || events[0].Position >= events[1].Position {
+		t.Fatalf("feed = %+v, want both creates in order", events)
+	}
+
+	// the feed is resumed after the last position read
+	rest := readFeed(fmt.Sprintf("/events?after=%d", events[0].Position))
+	if len(rest) != 1 || rest[0].EventID != events[1].EventID {
+		t.Errorf("feed after %d = %+v, want the second create", events[0].Position, rest)
+	}
+}
+
+func TestIdentityNeedsGatewaySecret(t *testing.T) {
+	app := newTestApp(t, func(app *Config) {
+		app.gatewaySecret = "gateway-secret"
+	})
+
+	request := dto.RequestStoreArticle{Title: "Title", Body: "Body"}
+
+	// the identity headers of a caller bypassing the gateway are ignored
+	res, _ := app.do(t, http.MethodPost, "/articles", request, nil)
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("create without the gateway secret answered %d, want 401", res.StatusCode)
+	}
+
+	res, _ = app.do(t, http.MethodPost, "/articles", request, http.Header{"X-Gateway-Secret": {"wrong-secret"}})
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("create with a wrong gateway secret answered %d, want 401", res.StatusCode)
+	}
+
+	res, article := app.do(t, http.MethodPost, "/articles", request, http.Header{"X-Gateway-Secret": {"gateway-secret"}})
+	if res.StatusCode != http.StatusOK || article.Author != "alice" {
+		t.Errorf("create with the gateway secret answered %d, want the article by alice", res.StatusCode)
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/Adhiana46/command-service/auth"
+	"github.com/Adhiana46/command-service/command"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/idempotency"
+	"github.com/Adhiana46/command-service/pb"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func (app *Config) serveGRPC() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Starting %s gRPC server on port %s\n", appName, grpcPort)
+
+	return app.GRPCServer().Serve(listener)
+}
+
+// GRPCServer is the gRPC API of the service, the counterpart of Routes.
+func (app *Config) GRPCServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		app.grpcMetadata,
+		app.grpcIdempotent,
+	))
+	pb.RegisterArticleCommandServer(server, &articleCommandServer{app: app})
+
+	return server
+}
+
+// grpcMetadata is the gRPC counterpart of the eventMetadata and principal
+// middlewares: request ids and the caller come from the call metadata.
+func (app *Config) grpcMetadata(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := firstMetadata(md, "x-request-id")
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+
+	correlationID := firstMetadata(md, "x-correlation-id")
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	ctx = event.ContextWithMetadata(ctx, event.Metadata{
+		CorrelationID: correlationID,
+		CausationID:   requestID,
+	})
+
+	if auth.TrustsGateway(app.gatewaySecret, firstMetadata(md, "x-gateway-secret")) {
+		if principal, ok := auth.ParsePrincipal(firstMetadata(md, "x-auth-subject"), firstMetadata(md, "x-auth-roles")); ok {
+			ctx = auth.ContextWithPrincipal(ctx, principal)
+		}
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs("x-correlation-id", correlationID))
+
+	return handler(ctx, req)
+}
+
+// grpcIdempotent replays the recorded response of a Store call retried with
+// the same idempotency-key metadata. Failed calls are not recorded so the
+// client can retry them.
+func (app *Config) grpcIdempotent(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	key := firstMetadata(md, "idempotency-key")
+	if key == "" || info.FullMethod != pb.ArticleCommand_Store_FullMethodName {
+		return handler(ctx, req)
+	}
+
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req.(proto.Message))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	principal, _ := auth.PrincipalFromContext(ctx)
+
+	hash := sha256.New()
+	hash.Write([]byte(principal.Subject + "\n"))
+	hash.Write([]byte("grpc " + info.FullMethod + "\n"))
+	hash.Write(body)
+	requestHash := hex.EncodeToString(hash.Sum(nil))
+
+	record, err := app.idempotencyKeys.Reserve(ctx, key, requestHash)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	if record != nil {
+		var resp pb.ArticleResponse
+		if err := protojson.Unmarshal(record.Response, &resp); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+		return &resp, nil
+	}
+
+	resp, handleErr := handler(ctx, req)
+
+	// the call context may already be cancelled by a client that gave up
+	saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if handleErr != nil {
+		err = app.idempotencyKeys.Release(saveCtx, key)
+	} else {
+		var recorded []byte
+		recorded, err = protojson.Marshal(resp.(proto.Message))
+		if err == nil {
+			err = app.idempotencyKeys.Complete(saveCtx, key, int(codes.OK), recorded)
+		}
+	}
+	if err != nil {
+		log.Printf("Can't record idempotency-key %s: %s", key, err)
+	}
+
+	return resp, handleErr
+}
+
+// grpcError is the gRPC counterpart of errorJSON. Validation errors carry
+// the failed fields as BadRequest details.
+func grpcError(err error) error {
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.As(err, &validationErrors):
+		st := status.New(codes.InvalidArgument, err.Error())
+
+		badRequest := &errdetails.BadRequest{}
+		for _, fieldErr := range validationErrors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldErr.Field(),
+				Description: validationDescription(fieldErr),
+			})
+		}
+
+		if detailed, detailErr := st.WithDetails(badRequest); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, command.ErrArticleDeleted):
+		return status.Error(codes.NotFound, err.Error())
+	// the gateway answers Aborted with 409 like the HTTP API does
+	case errors.Is(err, command.ErrConcurrencyConflict), errors.Is(err, command.ErrVersionMismatch), errors.Is(err, command.ErrSlugTaken), errors.Is(err, idempotency.ErrInProgress),
+		errors.Is(err, command.ErrInvalidTransition), errors.Is(err, command.ErrNotScheduled):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, idempotency.ErrKeyReused):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, command.ErrPublishAtInPast):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, command.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, command.ErrForbidden), errors.Is(err, command.ErrEditorRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+func validationDescription(fieldErr validator.FieldError) string {
+	if fieldErr.Param() != "" {
+		return fmt.Sprintf("failed on the '%s=%s' rule", fieldErr.Tag(), fieldErr.Param())
+	}
+
+	return fmt.Sprintf("failed on the '%s' rule", fieldErr.Tag())
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/Adhiana46/command-service/model"
+	"github.com/Adhiana46/command-service/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type articleCommandServer struct {
+	pb.UnimplementedArticleCommandServer
+
+	app *Config
+}
+
+func (s *articleCommandServer) Store(ctx context.Context, req *pb.StoreArticleRequest) (*pb.ArticleResponse, error) {
+	requestDto := dto.RequestStoreArticle{
+		Title:     req.GetTitle(),
+		Body:      req.GetBody(),
+		Tags:      req.GetTags(),
+		Category:  req.GetCategory(),
+		PublishAt: timeFromProto(req.GetPublishAt()),
+	}
+
+	article, err := s.app.cmdArticle.Store(ctx, requestDto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.ArticleResponse{
+		Message: "Article Successfully Created",
+		Article: articleToProto(article),
+	}, nil
+}
+
+func (s *articleCommandServer) Update(ctx context.Context, req *pb.UpdateArticleRequest) (*pb.ArticleResponse, error) {
+	requestDto := dto.RequestUpdateArticle{
+		Uuid:      req.GetUuid(),
+		Title:     req.GetTitle(),
+		Body:      req.GetBody(),
+		Tags:      req.GetTags(),
+		Category:  req.GetCategory(),
+		PublishAt: timeFromProto(req.GetPublishAt()),
+		Version:   int(req.GetVersion()),
+	}
+
+	article, err := s.app.cmdArticle.Update(ctx, requestDto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.ArticleResponse{
+		Message: "Article Successfully Updated",
+		Article: articleToProto(article),
+	}, nil
+}
+
+func (s *articleCommandServer) Delete(ctx context.Context, req *pb.DeleteArticleRequest) (*pb.ArticleResponse, error) {
+	requestDto := dto.RequestDeleteArticle{
+		Uuid:    req.GetUuid(),
+		Version: int(req.GetVersion()),
+	}
+
+	article, err := s.app.cmdArticle.Delete(ctx, requestDto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.ArticleResponse{
+		Message: "Article Successfully Deleted",
+		Article: articleToProto(article),
+	}, nil
+}
+
+func articleToProto(article *model.Article) *pb.Article {
+	result := &pb.Article{
+		Uuid:      article.Uuid,
+		Slug:      article.Slug,
+		Author:    article.Author,
+		Title:     article.Title,
+		Body:      article.Body,
+		Tags:      article.Tags,
+		Category:  article.Category,
+		Status:    article.Status,
+		Version:   int32(article.Version),
+		CreatedAt: timestamppb.New(article.CreatedAt),
+		UpdatedAt: timestamppb.New(article.UpdatedAt),
+	}
+
+	if article.PublishAt != nil {
+		result.PublishAt = timestamppb.New(*article.PublishAt)
+	}
+
+	return result
+}
+
+// timeFromProto is nil for an unset timestamp.
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+	return &t
+}
@@ -0,0 +1,392 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Adhiana46/command-service/command"
+	"github.com/Adhiana46/command-service/dto"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func (app *Config) StoreArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestDto dto.RequestStoreArticle
+	_ = app.readJSON(w, r, &requestDto)
+
+	article, err := app.cmdArticle.Store(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Article Successfully Created",
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(article.Version)}})
+}
+
+func (app *Config) UpdateArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestUpdateArticle
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	article, err := app.cmdArticle.Update(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Article Successfully Updated",
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(article.Version)}})
+}
+
+func (app *Config) DeleteArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestDeleteArticle
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	article, err := app.cmdArticle.Delete(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Article Successfully Deleted",
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) RestoreArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestRestoreArticle
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	article, err := app.cmdArticle.Restore(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Article Successfully Restored",
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(article.Version)}})
+}
+
+func (app *Config) SubmitArticleHandler(w http.ResponseWriter, r *http.Request) {
+	app.transitionArticle(w, r, app.cmdArticle.Submit, "Article Successfully Submitted for Review")
+}
+
+func (app *Config) PublishArticleHandler(w http.ResponseWriter, r *http.Request) {
+	app.transitionArticle(w, r, app.cmdArticle.Publish, "Article Successfully Published")
+}
+
+func (app *Config) ArchiveArticleHandler(w http.ResponseWriter, r *http.Request) {
+	app.transitionArticle(w, r, app.cmdArticle.Archive, "Article Successfully Archived")
+}
+
+// transitionArticle serves the editorial workflow endpoints, they only differ
+// in the command they run.
+func (app *Config) transitionArticle(
+	w http.ResponseWriter,
+	r *http.Request,
+	transition func(context.Context, dto.RequestTransitionArticle) (*model.Article, error),
+	message string,
+) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestTransitionArticle
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	article, err := transition(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: message,
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(article.Version)}})
+}
+
+func (app *Config) ScheduleArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	var requestDto dto.RequestScheduleArticle
+	_ = app.readJSON(w, r, &requestDto)
+	requestDto.Uuid = uuid
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	article, err := app.cmdArticle.Schedule(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Article Successfully Scheduled",
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(article.Version)}})
+}
+
+func (app *Config) CancelScheduleArticleHandler(w http.ResponseWriter, r *http.Request) {
+	app.transitionArticle(w, r, app.cmdArticle.CancelSchedule, "Article Schedule Successfully Cancelled")
+}
+
+func (app *Config) RevertArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil {
+		app.errorJSON(w, errors.New("revision must be a number"), http.StatusBadRequest)
+		return
+	}
+
+	requestDto := dto.RequestRevertArticle{
+		Uuid:     chi.URLParam(r, "uuid"),
+		Revision: revision,
+	}
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	requestDto.Version = version
+
+	article, err := app.cmdArticle.Revert(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: fmt.Sprintf("Article Successfully Reverted to Revision %d", revision),
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {etag(article.Version)}})
+}
+
+func (app *Config) GetEventsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	after, err := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+	if err != nil {
+		after = 0
+	}
+
+	limit, err := strconv.ParseUint(r.URL.Query().Get("limit"), 10, 64)
+	if err != nil || limit == 0 {
+		limit = 500
+	}
+
+	requestDto := dto.RequestListEvent{
+		After: after,
+		Limit: limit,
+	}
+
+	events, err := app.eventFeed.ReadAll(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Successfully Get List of Events",
+		Data:    dto.EventsToResponseDtos(events),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	checks := map[string]string{
+		"postgres": "up",
+	}
+
+	switch app.eventTransport {
+	case event.TransportKafka:
+		checks["kafka"] = "up"
+		if err := event.PingKafka(r.Context(), app.kafka.Brokers); err != nil {
+			checks["kafka"] = "down"
+			status = http.StatusServiceUnavailable
+		}
+	case event.TransportMemory:
+		checks["events"] = "memory"
+	default:
+		checks["rabbitmq"] = string(app.rabbitConn.State())
+		if !app.rabbitConn.IsConnected() {
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	if err := app.DB.PingContext(r.Context()); err != nil {
+		checks["postgres"] = "down"
+		status = http.StatusServiceUnavailable
+	}
+
+	resp := jsonResponse{
+		Error:   status != http.StatusOK,
+		Message: http.StatusText(status),
+		Data:    checks,
+	}
+
+	app.writeJSON(w, status, resp)
+}
+
+// BulkArticlesHandler answers 200 when every operation was applied, 207 when
+// only some were, and with the status of the failing operation when an
+// atomic batch was aborted. The outcome of each operation is in the items.
+func (app *Config) BulkArticlesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestDto dto.RequestBulkArticle
+	_ = app.readJSON(w, r, &requestDto)
+
+	results, err := app.cmdArticle.Bulk(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	data := dto.ResponseBulkArticle{
+		Atomic: requestDto.Atomic,
+		Items:  []*dto.ResponseBulkItem{},
+	}
+
+	failedStatus := 0
+	for i, result := range results {
+		item := &dto.ResponseBulkItem{
+			Index:  i,
+			Op:     requestDto.Operations[i].Op,
+			Status: http.StatusOK,
+		}
+
+		if result.Err != nil {
+			item.Status = errorStatus(result.Err)
+			item.Error = result.Err.Error()
+			data.Failed++
+			if failedStatus == 0 && !errors.Is(result.Err, command.ErrBulkAborted) {
+				failedStatus = item.Status
+			}
+		} else {
+			item.Article = dto.ArticleToResponseDTO(result.Article)
+			data.Applied++
+		}
+
+		data.Items = append(data.Items, item)
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Articles Successfully Processed",
+		Data:    data,
+	}
+
+	status := http.StatusOK
+	switch {
+	case data.Failed == 0:
+	case requestDto.Atomic:
+		resp.Error = true
+		resp.Message = "Bulk operation aborted, no article was changed"
+		status = failedStatus
+	case data.Applied == 0:
+		resp.Error = true
+		resp.Message = "No article was changed"
+		status = failedStatus
+	default:
+		resp.Message = "Articles Partially Processed"
+		status = http.StatusMultiStatus
+	}
+
+	app.writeJSON(w, status, resp)
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Adhiana46/command-service/command"
+	"github.com/go-playground/validator/v10"
+)
+
+type jsonResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (app *Config) readJSON(w http.ResponseWriter, r *http.Request, data any) error {
+	maxBytes := 1048576 // one megabyte
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
+
+	dec := json.NewDecoder(r.Body)
+	err := dec.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	err = dec.Decode(&struct{}{})
+	if err != io.EOF {
+		return errors.New("Body must have only a single JSON value")
+	}
+
+	return nil
+}
+
+func (app *Config) writeJSON(w http.ResponseWriter, status int, data any, headers ...http.Header) error {
+	out, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if len(headers) > 0 {
+		for key, value := range headers[0] {
+			w.Header()[key] = value
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(out)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (app *Config) errorJSON(w http.ResponseWriter, err error, status ...int) error {
+	statusCode := errorStatus(err)
+
+	if len(status) > 0 {
+		statusCode = status[0]
+	}
+
+	var payload jsonResponse
+	payload.Error = true
+	payload.Message = err.Error()
+
+	return app.writeJSON(w, statusCode, payload)
+}
+
+// errorStatus is the HTTP status a command error is answered with.
+func errorStatus(err error) int {
+	if err == sql.ErrNoRows || errors.Is(err, command.ErrRevisionNotFound) || errors.Is(err, command.ErrCommentDeleted) {
+		return 404
+	} else if errors.Is(err, command.ErrConcurrencyConflict) || errors.Is(err, command.ErrVersionMismatch) || errors.Is(err, command.ErrArticleNotDeleted) || errors.Is(err, command.ErrInvalidTransition) || errors.Is(err, command.ErrNotScheduled) || errors.Is(err, command.ErrSlugTaken) || errors.Is(err, command.ErrCommentModerated) {
+		return 409
+	} else if errors.Is(err, command.ErrPublishAtInPast) || errors.Is(err, command.ErrInvalidParent) {
+		return 400
+	} else if errors.Is(err, command.ErrUnauthenticated) {
+		return 401
+	} else if errors.Is(err, command.ErrForbidden) || errors.Is(err, command.ErrEditorRequired) || errors.Is(err, command.ErrCommentForbidden) || errors.Is(err, command.ErrModeratorRequired) {
+		return 403
+	} else if errors.Is(err, command.ErrBulkAborted) {
+		return 424
+	}
+
+	switch err.(type) {
+	case validator.ValidationErrors:
+		return 400
+	default:
+		return 500
+	}
+}
+
+// etag formats an article version as a strong ETag.
+func etag(version int) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// readIfMatch parses the expected version from an If-Match header, both
+// "3" and W/"3" are accepted. It returns 0 when the header is missing.
+func readIfMatch(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return 0, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	version, err := strconv.Atoi(strings.Trim(value, "\""))
+	if err != nil || version < 1 {
+		return 0, errors.New("If-Match must be an article version ETag")
+	}
+
+	return version, nil
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"github.com/Adhiana46/command-service/cache"
+	"github.com/Adhiana46/command-service/command"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/idempotency"
+)
+
+// NewMemory wires the service to the in-memory event store, idempotency keys
+// and the given emitter and cache, the way Run wires the Postgres, broker and
+// Redis ones. It runs the service in-process without any infrastructure.
+func NewMemory(emitter *event.MemoryEmitter, c cache.Cache) *Config {
+	repo := command.NewArticleRepositoryMemory(emitter)
+
+	return &Config{
+		AppName:         appName,
+		AppVersion:      appVersion,
+		eventTransport:  event.TransportMemory,
+		cmdArticle:      command.NewArticleCommand(repo, c),
+		cmdComment:      command.NewCommentCommand(repo),
+		eventFeed:       repo,
+		idempotencyKeys: idempotency.NewStoreMemory(defaultIdempotencyTTL),
+	}
+}
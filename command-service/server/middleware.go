@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Adhiana46/command-service/auth"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/idempotency"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// responseRecorder keeps a copy of the response so it can be replayed.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// idempotent replays the recorded response when a request is retried with
+// the same Idempotency-Key, and refuses a key reused for a different request.
+func (app *Config) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		maxBytes := 1048576 // one megabyte
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(maxBytes)))
+		if err != nil {
+			app.errorJSON(w, err, http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// keys are scoped to the caller, another user can't replay them
+		principal, _ := auth.PrincipalFromContext(r.Context())
+
+		hash := sha256.New()
+		hash.Write([]byte(principal.Subject + "\n"))
+		hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		ctx := r.Context()
+
+		record, err := app.idempotencyKeys.Reserve(ctx, key, requestHash)
+		if err != nil {
+			switch {
+			case errors.Is(err, idempotency.ErrKeyReused):
+				app.errorJSON(w, err, http.StatusUnprocessableEntity)
+			case errors.Is(err, idempotency.ErrInProgress):
+				app.errorJSON(w, err, http.StatusConflict)
+			default:
+				app.errorJSON(w, err)
+			}
+			return
+		}
+
+		if record != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(int(record.StatusCode.Int32))
+			w.Write(record.Response)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// the request context may already be cancelled by a client that gave up
+		saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// server errors are not recorded so the client can retry them
+		if rec.status >= http.StatusInternalServerError {
+			err = app.idempotencyKeys.Release(saveCtx, key)
+		} else {
+			err = app.idempotencyKeys.Complete(saveCtx, key, rec.status, rec.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("Can't record Idempotency-Key %s: %s", key, err)
+		}
+	})
+}
+
+// eventMetadata carries the correlation and causation ids of the request into
+// the events it produces. The causation id is the id of this request, the
+// correlation id is taken from the caller when it sends one.
+func (app *Config) eventMetadata(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		correlationID := r.Header.Get("X-Correlation-ID")
+		if correlationID == "" {
+			correlationID = requestID
+		}
+
+		ctx := event.ContextWithMetadata(r.Context(), event.Metadata{
+			CorrelationID: correlationID,
+			CausationID:   requestID,
+		})
+
+		w.Header().Set("X-Correlation-ID", correlationID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// principal puts the caller forwarded by rest-gateway into the request context.
+// The identity headers of a request without the gateway secret are ignored.
+func (app *Config) principal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.TrustsGateway(app.gatewaySecret, r.Header.Get(auth.GatewaySecretHeader)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, ok := auth.PrincipalFromRequest(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := auth.ContextWithPrincipal(r.Context(), principal)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
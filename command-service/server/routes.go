@@ -0,0 +1,66 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+)
+
+func (app *Config) Routes() http.Handler {
+	mux := chi.NewRouter()
+
+	mux.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   []string{"https://*", "http://*"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "Idempotency-Key", "X-Request-Id", "X-Correlation-ID"},
+		ExposedHeaders:   []string{"Link", "ETag", "Idempotent-Replayed", "X-Correlation-ID"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}))
+
+	mux.Use(middleware.Heartbeat("/ping"))
+	mux.Use(middleware.RequestID)
+	mux.Use(app.eventMetadata)
+	mux.Use(app.principal)
+
+	mux.Get("/health", app.HealthHandler)
+
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		payload := jsonResponse{
+			Error:   false,
+			Message: fmt.Sprintf("Welcome to %s version %s", app.AppName, app.AppVersion),
+		}
+		_ = app.writeJSON(w, http.StatusOK, payload)
+	})
+
+	// Articles
+	mux.Route("/articles", func(r chi.Router) {
+		r.With(app.idempotent).Post("/", app.StoreArticleHandler)
+		r.With(app.idempotent).Post("/bulk", app.BulkArticlesHandler)
+		r.Put("/{uuid}", app.UpdateArticleHandler)
+		r.Delete("/{uuid}", app.DeleteArticleHandler)
+		r.Post("/{uuid}/restore", app.RestoreArticleHandler)
+		r.Post("/{uuid}/revert/{revision}", app.RevertArticleHandler)
+		r.Post("/{uuid}/submit", app.SubmitArticleHandler)
+		r.Post("/{uuid}/publish", app.PublishArticleHandler)
+		r.Post("/{uuid}/archive", app.ArchiveArticleHandler)
+		r.Post("/{uuid}/schedule", app.ScheduleArticleHandler)
+		r.Delete("/{uuid}/schedule", app.CancelScheduleArticleHandler)
+		r.With(app.idempotent).Post("/{uuid}/comments", app.StoreCommentHandler)
+	})
+
+	// Comments
+	mux.Route("/comments", func(r chi.Router) {
+		r.Put("/{uuid}", app.UpdateCommentHandler)
+		r.Delete("/{uuid}", app.DeleteCommentHandler)
+		r.Post("/{uuid}/moderate", app.ModerateCommentHandler)
+	})
+
+	// Event store feed, used to rebuild read models
+	mux.Get("/events", app.GetEventsHandler)
+
+	return mux
+}
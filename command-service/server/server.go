@@ -0,0 +1,375 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/command-service/command"
+	"github.com/Adhiana46/command-service/event"
+	"github.com/Adhiana46/command-service/idempotency"
+	"github.com/Adhiana46/command-service/model"
+	"github.com/Adhiana46/command-service/outbox"
+	"github.com/Adhiana46/command-service/scheduler"
+	"github.com/go-redis/redis/v9"
+	_ "github.com/jackc/pgx/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	appName    = "Command Service"
+	appVersion = "1.0"
+	port       = "80"
+	grpcPort   = "50051"
+
+	defaultIdempotencyTTL  = 24 * time.Hour
+	defaultTrashRetention  = 30 * 24 * time.Hour
+	defaultOutboxRetention = 7 * 24 * time.Hour
+)
+
+type Config struct {
+	AppName    string
+	AppVersion string
+
+	DB             *sqlx.DB
+	eventTransport string
+	rabbitConn     *event.Connection
+	kafka          event.KafkaConfig
+	rds            *redis.Client
+
+	cmdArticle      command.ArticleCommand
+	cmdComment      command.CommentCommand
+	eventFeed       command.EventFeed
+	idempotencyKeys idempotency.Store
+	trashRetention  time.Duration
+	outboxRetention time.Duration
+	scheduler       *scheduler.Scheduler
+	gatewaySecret   string
+}
+
+// Run connects the service to Postgres, the event transport and Redis and
+// serves the HTTP and gRPC APIs until the HTTP server fails.
+func Run() {
+	app := Config{
+		AppName:       appName,
+		AppVersion:    appVersion,
+		gatewaySecret: os.Getenv("GATEWAY_SECRET"),
+	}
+
+	// open db connection (postgresql)
+	err := app.openDB()
+	if err != nil {
+		log.Panicf("Can't open database connection: %s", err)
+	}
+	defer app.closeDB()
+
+	// open the event transport
+	err = app.openEvents()
+	if err != nil {
+		log.Panicf("Can't open %s event transport: %s", app.eventTransport, err)
+	}
+	defer app.closeEvents()
+
+	// open redis
+	err = app.openRedis()
+	if err != nil {
+		log.Panicf("Can't open Redis connection: %s", err)
+	}
+	defer app.closeRedis()
+
+	app.registerCommand()
+
+	// relay outbox events to the event transport
+	relay, err := app.newOutboxRelay()
+	if err != nil {
+		log.Panicf("Can't start outbox relay: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go relay.Run(ctx)
+	go app.purgeOutbox(ctx, relay)
+	go app.purgeIdempotencyKeys(ctx)
+	go app.purgeTrash(ctx)
+	go app.scheduler.Run(ctx)
+
+	go func() {
+		if err := app.serveGRPC(); err != nil {
+			log.Panic(err)
+		}
+	}()
+
+	log.Printf("Starting %s service on port %s\n", appName, port)
+
+	s := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: app.Routes(),
+	}
+
+	// starting the server
+	if err := s.ListenAndServe(); err != nil {
+		log.Panic(err)
+	}
+}
+
+func (app *Config) registerCommand() {
+	app.cmdArticle = command.NewArticleCommandPg(app.DB, app.rds)
+	app.cmdComment = command.NewCommentCommandPg(app.DB)
+	app.eventFeed = command.NewEventFeedPg(app.DB)
+
+	ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	app.idempotencyKeys = idempotency.NewStorePg(app.DB, ttl)
+
+	app.trashRetention, err = time.ParseDuration(os.Getenv("TRASH_RETENTION"))
+	if err != nil || app.trashRetention <= 0 {
+		app.trashRetention = defaultTrashRetention
+	}
+
+	app.outboxRetention, err = time.ParseDuration(os.Getenv("OUTBOX_RETENTION"))
+	if err != nil || app.outboxRetention <= 0 {
+		app.outboxRetention = defaultOutboxRetention
+	}
+
+	app.scheduler = scheduler.NewScheduler(scheduler.NewStorePg(app.DB))
+	app.scheduler.Handle(command.PublishArticleJob, app.publishScheduledArticle)
+}
+
+// publishScheduledArticle runs the publish job of a scheduled article.
+func (app *Config) publishScheduledArticle(ctx context.Context, job model.ScheduledJob) error {
+	published, err := app.cmdArticle.PublishScheduled(ctx, job.AggregateID)
+	if err != nil {
+		return err
+	}
+
+	if published {
+		log.Printf("Published scheduled article %s\n", job.AggregateID)
+	}
+
+	return nil
+}
+
+// purgeIdempotencyKeys drops keys that left the replay window.
+func (app *Config) purgeIdempotencyKeys(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := app.idempotencyKeys.Purge(ctx)
+			if err != nil {
+				log.Println("Can't purge idempotency keys:", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d expired idempotency keys\n", purged)
+			}
+		}
+	}
+}
+
+// purgeTrash removes the articles that stayed in the trash longer than the
+// retention period.
+func (app *Config) purgeTrash(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := app.cmdArticle.PurgeTrash(ctx, time.Now().UTC().Add(-app.trashRetention))
+			if err != nil {
+				log.Println("Can't purge trashed articles:", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d trashed articles\n", purged)
+			}
+		}
+	}
+}
+
+// purgeOutbox deletes the outbox rows that were sent longer than the
+// retention period ago.
+func (app *Config) purgeOutbox(ctx context.Context, relay *outbox.Relay) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := relay.PurgeSent(ctx, time.Now().Add(-app.outboxRetention))
+			if err != nil {
+				log.Println("Can't purge sent outbox rows:", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d sent outbox rows\n", purged)
+			}
+		}
+	}
+}
+
+func (app *Config) newOutboxRelay() (*outbox.Relay, error) {
+	var emitter event.Emitter
+	var err error
+
+	switch app.eventTransport {
+	case event.TransportKafka:
+		emitter, err = event.NewEventEmitterKafka(app.kafka, "articles")
+	case event.TransportMemory:
+		emitter = event.NewEventEmitterMemory()
+	default:
+		emitter, err = event.NewEventEmitterRabbitmq(app.rabbitConn, "articles")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return outbox.NewRelay(app.DB, emitter), nil
+}
+
+// Postgresql
+func (app *Config) openDB() error {
+	var count int64
+	var retryTime = 1 * time.Second
+	var connection *sqlx.DB
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s",
+		os.Getenv("CMD_DB_HOST"),
+		os.Getenv("CMD_DB_PORT"),
+		os.Getenv("CMD_DB_USER"),
+		os.Getenv("CMD_DB_DATABASE"),
+		os.Getenv("CMD_DB_PASSWORD"),
+	)
+
+	for {
+		c, err := sqlx.Connect("pgx", dsn)
+		if err != nil {
+			log.Println("Postgresql not ready yet...", err)
+			count++
+		} else {
+			log.Println("Connected to Postgresql")
+
+			c.SetMaxOpenConns(60)
+			c.SetConnMaxLifetime(120 * time.Second)
+			c.SetMaxIdleConns(30)
+			c.SetConnMaxIdleTime(20 * time.Second)
+			if err = c.Ping(); err != nil {
+				return err
+			}
+
+			connection = c
+
+			break
+		}
+
+		if count > 5 {
+			log.Println("Could not connect to Postgresql", err)
+			return err
+		}
+
+		retryTime = time.Duration(math.Pow(float64(count), 2)) * time.Second
+		log.Println("Retrying in", retryTime)
+		time.Sleep(retryTime)
+		continue
+	}
+
+	app.DB = connection
+
+	return nil
+}
+
+func (app *Config) closeDB() {
+	app.DB.Close()
+}
+
+// Events, EVENT_TRANSPORT is one of rabbitmq (default), kafka or memory
+func (app *Config) openEvents() error {
+	app.eventTransport = os.Getenv("EVENT_TRANSPORT")
+	if app.eventTransport == "" {
+		app.eventTransport = event.TransportRabbitmq
+	}
+
+	switch app.eventTransport {
+	case event.TransportRabbitmq:
+		return app.openRabbitmq()
+	case event.TransportKafka:
+		app.kafka = event.KafkaConfig{
+			Brokers:     splitList(os.Getenv("KAFKA_BROKERS")),
+			TopicPrefix: os.Getenv("KAFKA_TOPIC_PREFIX"),
+		}
+		return nil
+	case event.TransportMemory:
+		log.Println("Events are kept in memory, nothing is published")
+		return nil
+	default:
+		return fmt.Errorf("unknown EVENT_TRANSPORT %q", app.eventTransport)
+	}
+}
+
+func (app *Config) closeEvents() {
+	if app.rabbitConn != nil {
+		app.closeRabbitmq()
+	}
+}
+
+// Rabbitmq
+func (app *Config) openRabbitmq() error {
+	dsn := fmt.Sprintf(
+		"amqp://%s:%s@%s:%s/",
+		os.Getenv("AMQP_USER"),
+		os.Getenv("AMQP_PASSWORD"),
+		os.Getenv("AMQP_HOST"),
+		os.Getenv("AMQP_PORT"),
+	)
+
+	// Don't continue until rabbit is ready, reconnects are handled by event.Connection
+	connection, err := event.Dial(dsn)
+	if err != nil {
+		return err
+	}
+
+	app.rabbitConn = connection
+
+	return nil
+}
+
+func (app *Config) closeRabbitmq() {
+	app.rabbitConn.Close()
+}
+
+// Redis
+func (app *Config) openRedis() error {
+	app.rds = redis.NewClient(&redis.Options{
+		Addr:        fmt.Sprintf("%v:%v", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		Password:    os.Getenv("REDIS_PASSWORD"),
+		DB:          0, // use default DB
+		ReadTimeout: -1,
+	})
+
+	return nil
+}
+
+func (app *Config) closeRedis() {
+	app.rds.Close()
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
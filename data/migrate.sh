@@ -0,0 +1,21 @@
+#!/bin/sh
+# Applies the migrations of data/migrations the database hasn't seen yet, in file
+# name order and each in its own transaction. The postgres container runs it after
+# initdb.sql on a new database; an existing one is brought up to date with
+#   docker compose exec postgres /docker-entrypoint-initdb.d/001_migrate.sh
+set -eu
+
+migrations="${MIGRATIONS_DIR:-/migrations}"
+psql="psql -X -q -v ON_ERROR_STOP=1 --username ${POSTGRES_USER} --dbname ${POSTGRES_DB}"
+
+$psql -c "CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT NOT NULL, applied_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (version))"
+
+for file in "$migrations"/*.sql; do
+	version=$(basename "$file" .sql)
+	if [ -n "$($psql -tA -c "SELECT 1 FROM schema_migrations WHERE version = '$version'")" ]; then
+		continue
+	fi
+
+	echo "migrate: applying $version"
+	$psql --single-transaction -f "$file" -c "INSERT INTO schema_migrations (version) VALUES ('$version')"
+done
@@ -0,0 +1,16 @@
+-- Transactional outbox, written in the same transaction as the article events and
+-- drained by the outbox relay in command-service
+CREATE TABLE outbox
+(
+	id BIGSERIAL NOT NULL,
+	event_name TEXT NOT NULL,
+	payload JSONB NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	sent_at TIMESTAMP(0),
+	PRIMARY KEY (id)
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
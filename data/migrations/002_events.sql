@@ -0,0 +1,16 @@
+-- Append-only event store, the source of truth for every article
+-- (an article is rebuilt by replaying its events ordered by sequence).
+-- The articles of the articles table are moved into it by 009_backfill_articles.
+CREATE TABLE events
+(
+	id BIGSERIAL NOT NULL,
+	aggregate_id CHAR(36) NOT NULL,
+	aggregate_type TEXT NOT NULL,
+	sequence INT NOT NULL,
+	event_type TEXT NOT NULL,
+	payload JSONB NOT NULL,
+	metadata JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (id),
+	UNIQUE (aggregate_id, sequence)
+);
@@ -0,0 +1,10 @@
+-- Idempotency-Key records for POST /articles, replayed within IDEMPOTENCY_TTL
+CREATE TABLE idempotency_keys
+(
+	key TEXT NOT NULL,
+	request_hash CHAR(64) NOT NULL,
+	status_code INT,
+	response JSONB,
+	created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (key)
+);
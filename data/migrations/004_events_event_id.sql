@@ -0,0 +1,8 @@
+-- Every event has an id of its own, published in its envelope so consumers can
+-- drop duplicates. Events stored before get a random one.
+ALTER TABLE events ADD COLUMN event_id CHAR(36);
+
+UPDATE events SET event_id = gen_random_uuid();
+
+ALTER TABLE events ALTER COLUMN event_id SET NOT NULL;
+ALTER TABLE events ADD CONSTRAINT events_event_id_key UNIQUE (event_id);
@@ -0,0 +1,19 @@
+-- Jobs due at run_at, written in the same transaction as the article events that
+-- ask for them and run by the scheduler in command-service. There is one pending
+-- job per aggregate and job type, scheduling again moves it.
+CREATE TABLE scheduled_jobs
+(
+	id BIGSERIAL NOT NULL,
+	aggregate_id CHAR(36) NOT NULL,
+	job_type TEXT NOT NULL,
+	run_at TIMESTAMP(0) NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT,
+	cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	done_at TIMESTAMP(0),
+	PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX scheduled_jobs_pending_key ON scheduled_jobs (aggregate_id, job_type) WHERE done_at IS NULL;
+CREATE INDEX scheduled_jobs_due_idx ON scheduled_jobs (run_at) WHERE done_at IS NULL;
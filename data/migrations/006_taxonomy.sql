@@ -0,0 +1,33 @@
+-- Tags and categories of the articles, kept in step with the article events in the
+-- same transaction. An article has any number of tags and at most one category.
+CREATE TABLE tags
+(
+	id BIGSERIAL NOT NULL,
+	name TEXT NOT NULL UNIQUE,
+	PRIMARY KEY (id)
+);
+
+CREATE TABLE article_tags
+(
+	article_id CHAR(36) NOT NULL,
+	tag_id BIGINT NOT NULL REFERENCES tags (id),
+	PRIMARY KEY (article_id, tag_id)
+);
+
+CREATE INDEX article_tags_tag_idx ON article_tags (tag_id);
+
+CREATE TABLE categories
+(
+	id BIGSERIAL NOT NULL,
+	name TEXT NOT NULL UNIQUE,
+	PRIMARY KEY (id)
+);
+
+CREATE TABLE article_categories
+(
+	article_id CHAR(36) NOT NULL,
+	category_id BIGINT NOT NULL REFERENCES categories (id),
+	PRIMARY KEY (article_id)
+);
+
+CREATE INDEX article_categories_category_idx ON article_categories (category_id);
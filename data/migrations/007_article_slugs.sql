@@ -0,0 +1,12 @@
+-- Slugs of the articles, kept in step with the article events in the same transaction.
+-- A retitled article keeps its previous slugs so they still resolve to it, a purged
+-- article releases them.
+CREATE TABLE article_slugs
+(
+	slug TEXT NOT NULL,
+	article_id CHAR(36) NOT NULL,
+	created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (slug)
+);
+
+CREATE INDEX article_slugs_article_idx ON article_slugs (article_id);
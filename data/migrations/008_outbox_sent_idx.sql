@@ -0,0 +1,2 @@
+-- Sent outbox rows are purged after OUTBOX_RETENTION
+CREATE INDEX outbox_sent_idx ON outbox (sent_at) WHERE sent_at IS NOT NULL;
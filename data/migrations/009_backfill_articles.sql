@@ -0,0 +1,94 @@
+-- Moves the articles of the articles table, which predates the event store, into it.
+-- Every article gets an article.created event with its current content, a slug and an
+-- outbox envelope, so the query-service projects it like any new article. The slug
+-- keeps the ASCII letters and digits of the title, a title taken before gets the next
+-- numeric suffix. The table is dropped afterwards, the events are the source of truth.
+CREATE TEMPORARY TABLE backfilled_articles AS
+WITH based AS (
+	SELECT
+		id,
+		uuid,
+		COALESCE(author, '') AS author,
+		COALESCE(title, '') AS title,
+		COALESCE(body, '') AS body,
+		COALESCE(created_at, LOCALTIMESTAMP(0)) AS created_at,
+		COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(TRIM(BOTH '-' FROM
+			REGEXP_REPLACE(LOWER(COALESCE(title, '')), '[^a-z0-9]+', '-', 'g')), 80)), ''), 'article') AS base
+	FROM articles
+), numbered AS (
+	SELECT *, ROW_NUMBER() OVER (PARTITION BY base ORDER BY id) AS n
+	FROM based
+)
+SELECT
+	id,
+	uuid,
+	author,
+	title,
+	body,
+	created_at,
+	CASE WHEN n = 1 THEN base
+		ELSE TRIM(BOTH '-' FROM LEFT(base, 80 - LENGTH('-' || n))) || '-' || n
+	END AS slug,
+	gen_random_uuid()::TEXT AS event_id,
+	TO_CHAR(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"') AS occurred_at
+FROM numbered;
+
+INSERT INTO events (event_id, aggregate_id, aggregate_type, sequence, event_type, payload, created_at)
+SELECT
+	event_id,
+	uuid,
+	'article',
+	1,
+	'article.created',
+	JSONB_BUILD_OBJECT(
+		'slug', slug,
+		'author', author,
+		'title', title,
+		'body', body,
+		'status', 'published'
+	),
+	created_at
+FROM backfilled_articles
+ORDER BY id;
+
+INSERT INTO article_slugs (slug, article_id, created_at)
+SELECT slug, uuid, created_at
+FROM backfilled_articles;
+
+-- the envelope newEnvelope in command-service builds for the event, its data is the
+-- article right after it
+INSERT INTO outbox (event_name, payload, created_at)
+SELECT
+	'article.created',
+	JSONB_BUILD_OBJECT(
+		'event_id', event_id,
+		'event_type', 'article.created',
+		'aggregate_id', uuid,
+		'aggregate_type', 'article',
+		'aggregate_version', 1,
+		'occurred_at', occurred_at,
+		'producer', 'command-service',
+		'schema_version', 1,
+		'data', JSONB_BUILD_OBJECT(
+			'uuid', uuid,
+			'slug', slug,
+			'author', author,
+			'title', title,
+			'body', body,
+			'tags', '[]'::JSONB,
+			'category', '',
+			'status', 'published',
+			'version', 1,
+			'revision', 1,
+			'updated_by', author,
+			'created_at', occurred_at,
+			'updated_at', occurred_at
+		)
+	),
+	created_at
+FROM backfilled_articles
+ORDER BY id;
+
+DROP TABLE backfilled_articles;
+DROP TABLE articles;
+DROP SEQUENCE articles_seq;
@@ -0,0 +1,14 @@
+-- The relay keeps the events of every aggregate in order rather than the whole table,
+-- it claims the oldest pending row of each aggregate. A row that keeps failing is
+-- parked with its last_error and no longer holds its aggregate back.
+ALTER TABLE outbox ADD COLUMN aggregate_id CHAR(36);
+ALTER TABLE outbox ADD COLUMN parked_at TIMESTAMP(0);
+
+-- rows written before envelopes existed hold the article itself
+UPDATE outbox SET aggregate_id = COALESCE(payload->>'aggregate_id', payload->>'uuid', '');
+
+ALTER TABLE outbox ALTER COLUMN aggregate_id SET NOT NULL;
+
+DROP INDEX outbox_pending_idx;
+CREATE INDEX outbox_pending_idx ON outbox (aggregate_id, id) WHERE sent_at IS NULL AND parked_at IS NULL;
+CREATE INDEX outbox_parked_idx ON outbox (parked_at) WHERE parked_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- A key being processed is leased, a request that crashed before completing it no
+-- longer holds the key for the whole IDEMPOTENCY_TTL. Keys in progress today are
+-- released right away.
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,292 @@
+package e2e
+
+import (
+	// ahead of the services, see the package
+	_ "github.com/Adhiana46/e2e/internal/protoconflict"
+
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cmdcache "github.com/Adhiana46/command-service/cache"
+	cmdevent "github.com/Adhiana46/command-service/event"
+	cmdserver "github.com/Adhiana46/command-service/server"
+	querycache "github.com/Adhiana46/query-service/cache"
+	queryevent "github.com/Adhiana46/query-service/event"
+	queryserver "github.com/Adhiana46/query-service/server"
+	"github.com/Adhiana46/rest-gateway/auth"
+	"github.com/Adhiana46/rest-gateway/dto"
+	gateway "github.com/Adhiana46/rest-gateway/server"
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc"
+)
+
+var testSecret = []byte("test-secret")
+
+// stack is rest-gateway in front of command-service and query-service, all
+// three in this process on their in-memory implementations. The events
+// command-service emits are handed to the broker query-service consumes.
+type stack struct {
+	gateway *httptest.Server
+}
+
+func newStack(t *testing.T) *stack {
+	t.Helper()
+
+	emitter := cmdevent.NewEventEmitterMemory()
+	broker := queryevent.NewMemoryBroker()
+	emitter.Subscribe(func(msg cmdevent.MemoryMessage) error {
+		return broker.Publish(&queryevent.Message{
+			ID:          msg.ID,
+			EventType:   msg.EventType,
+			Key:         msg.Key,
+			ContentType: msg.ContentType,
+			Timestamp:   time.Now(),
+			Body:        msg.Body,
+		})
+	})
+
+	// command-service
+	commands := cmdserver.NewMemory(emitter, cmdcache.NewCacheMemory())
+	commandHTTP := httptest.NewServer(commands.Routes())
+	t.Cleanup(commandHTTP.Close)
+	commandGRPC := serveGRPC(t, commands.GRPCServer())
+
+	// query-service
+	queries := queryserver.NewMemory(broker, querycache.NewCacheMemory())
+	done := make(chan struct{})
+	go func() {
+		queries.ListenEvents()
+		close(done)
+	}()
+	for deadline := time.Now().Add(time.Second); broker.Subscribed() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the event consumer of query-service did not subscribe")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Cleanup(func() {
+		broker.Close()
+		<-done
+	})
+	queryHTTP := httptest.NewServer(queries.Routes())
+	t.Cleanup(queryHTTP.Close)
+	queryGRPC := serveGRPC(t, queries.GRPCServer())
+
+	// rest-gateway
+	verifier, err := auth.NewVerifier(auth.Options{HMACSecret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := gateway.New(gateway.Options{
+		QueryURL:    queryHTTP.URL,
+		CommandURL:  commandHTTP.URL,
+		GRPCQuery:   queryGRPC,
+		GRPCCommand: commandGRPC,
+		Verifier:    verifier,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(app.Close)
+
+	server := httptest.NewServer(app.Routes())
+	t.Cleanup(server.Close)
+
+	return &stack{gateway: server}
+}
+
+// serveGRPC serves server on a free local port and returns its address.
+func serveGRPC(t *testing.T, server *grpc.Server) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func token(t *testing.T, subject string, roles ...string) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: roles,
+	}).SignedString(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// do sends a request through the gateway, as the caller of the token or
+// anonymously without one, and decodes the data of the response into data.
+func (s *stack) do(t *testing.T, method string, path string, token string, body any, headers http.Header, data any) *http.Response {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, s.gateway.URL+"/api/v1"+path, &reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	payload := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode == http.StatusOK && data != nil {
+		if err := json.Unmarshal(payload.Data, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return res
+}
+
+// eventually reads the article until check accepts it, the read model is
+// projected asynchronously.
+func (s *stack) eventually(t *testing.T, uuid string, token string, check func(status int, article *dto.ResponseArticle) bool) *dto.ResponseArticle {
+	t.Helper()
+
+	for deadline := time.Now().Add(2 * time.Second); ; {
+		article := &dto.ResponseArticle{}
+		res := s.do(t, http.MethodGet, "/articles/"+uuid, token, nil, nil, article)
+		if check(res.StatusCode, article) {
+			return article
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("article %s answered %d with %+v", uuid, res.StatusCode, article)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (s *stack) listTitles(t *testing.T, token string) []string {
+	t.Helper()
+
+	list := dto.ResponseListArticle{}
+	if res := s.do(t, http.MethodGet, "/articles", token, nil, nil, &list); res.StatusCode != http.StatusOK {
+		t.Fatalf("list answered %d", res.StatusCode)
+	}
+
+	titles := []string{}
+	for _, article := range list.Articles {
+		titles = append(titles, article.Title)
+	}
+	return titles
+}
+
+func TestArticleThroughTheGateway(t *testing.T) {
+	s := newStack(t)
+	alice := token(t, "alice", "editor")
+
+	// create through gRPC, the draft is projected for its author only
+	created := &dto.ResponseArticle{}
+	res := s.do(t, http.MethodPost, "/articles", alice, dto.RequestStoreArticle{
+		Title: "First title",
+		Body:  "First body",
+		Tags:  []string{"go"},
+	}, nil, created)
+	if res.StatusCode != http.StatusOK || created.Uuid == "" || created.Author != "alice" {
+		t.Fatalf("create answered %d with %+v", res.StatusCode, created)
+	}
+	uuid := created.Uuid
+
+	s.eventually(t, uuid, alice, func(status int, article *dto.ResponseArticle) bool {
+		return status == http.StatusOK && article.Title == "First title"
+	})
+	if res := s.do(t, http.MethodGet, "/articles/"+uuid, "", nil, nil, nil); res.StatusCode == http.StatusOK {
+		t.Errorf("anonymous read of the draft answered %d", res.StatusCode)
+	}
+
+	// the workflow goes through the HTTP proxy
+	for _, step := range []string{"submit", "publish"} {
+		if res := s.do(t, http.MethodPost, "/articles/"+uuid+"/"+step, alice, nil, nil, nil); res.StatusCode != http.StatusOK {
+			t.Fatalf("%s answered %d", step, res.StatusCode)
+		}
+	}
+	published := s.eventually(t, uuid, "", func(status int, article *dto.ResponseArticle) bool {
+		return status == http.StatusOK
+	})
+	if published.Title != "First title" {
+		t.Errorf("published article %+v, want the first title", published)
+	}
+	if titles := s.listTitles(t, ""); len(titles) != 1 || titles[0] != "First title" {
+		t.Errorf("list = %v, want the published article", titles)
+	}
+
+	// the update replaces the cached article and list
+	updated := &dto.ResponseArticle{}
+	res = s.do(t, http.MethodPut, "/articles/"+uuid, alice, dto.RequestUpdateArticle{
+		Title: "Second title",
+		Body:  "Second body",
+		Tags:  []string{"go"},
+	}, http.Header{"If-Match": {fmt.Sprintf(`"%d"`, published.Version)}}, updated)
+	if res.StatusCode != http.StatusOK || updated.Version <= published.Version {
+		t.Fatalf("update answered %d with %+v", res.StatusCode, updated)
+	}
+	s.eventually(t, uuid, "", func(status int, article *dto.ResponseArticle) bool {
+		return status == http.StatusOK && article.Title == "Second title"
+	})
+	if titles := s.listTitles(t, ""); len(titles) != 1 || titles[0] != "Second title" {
+		t.Errorf("list = %v after the update, want the new title", titles)
+	}
+
+	// an update based on the version read before is refused
+	res = s.do(t, http.MethodPut, "/articles/"+uuid, alice, dto.RequestUpdateArticle{
+		Title: "Lost title",
+		Body:  "Lost body",
+	}, http.Header{"If-Match": {`"1"`}}, nil)
+	if res.StatusCode != http.StatusConflict {
+		t.Errorf("stale update answered %d, want 409", res.StatusCode)
+	}
+
+	// another author can't delete the article
+	if res := s.do(t, http.MethodDelete, "/articles/"+uuid, token(t, "bob", "author"), nil, nil, nil); res.StatusCode != http.StatusForbidden {
+		t.Errorf("delete by bob answered %d, want 403", res.StatusCode)
+	}
+
+	if res := s.do(t, http.MethodDelete, "/articles/"+uuid, alice, nil, nil, nil); res.StatusCode != http.StatusOK {
+		t.Fatalf("delete answered %d", res.StatusCode)
+	}
+	s.eventually(t, uuid, "", func(status int, article *dto.ResponseArticle) bool {
+		return status == http.StatusNotFound
+	})
+	if titles := s.listTitles(t, ""); len(titles) != 0 {
+		t.Errorf("list = %v after the delete, want nothing", titles)
+	}
+}
@@ -0,0 +1,14 @@
+// Package protoconflict lets the generated code of all three services be
+// linked into one binary. Each service carries its own copy of the code
+// generated from proto/, so article.proto is registered once per service.
+// The copies are identical and the conflict is safe to ignore.
+//
+// It has to be imported ahead of the services, its init then runs before the
+// generated code registers.
+package protoconflict
+
+import "os"
+
+func init() {
+	os.Setenv("GOLANG_PROTOBUF_REGISTRATION_CONFLICT", "ignore")
+}
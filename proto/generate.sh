@@ -0,0 +1,26 @@
+#!/bin/sh
+# Generates the Go code of the protobuf definitions into every service that
+# uses them. Needs protoc, protoc-gen-go and protoc-gen-go-grpc on the PATH.
+set -e
+
+cd "$(dirname "$0")"
+
+generate() {
+	module=$1
+	shift
+
+	opts=""
+	for file in "$@"; do
+		opts="$opts,M$file=github.com/Adhiana46/$module/pb;pb"
+	done
+	opts="paths=source_relative$opts"
+
+	protoc -I . \
+		--go_out="../$module/pb" --go_opt="$opts" \
+		--go-grpc_out="../$module/pb" --go-grpc_opt="$opts" \
+		"$@"
+}
+
+generate command-service article.proto article_command.proto
+generate query-service article.proto article_query.proto
+generate rest-gateway article.proto article_command.proto article_query.proto
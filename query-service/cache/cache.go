@@ -0,0 +1,20 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores short-lived copies of serialized values. Get reports ErrMiss
+// for keys that are unknown or expired.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+	// Incr adds one to the integer value of every key in a single step,
+	// missing keys count from zero.
+	Incr(ctx context.Context, keys ...string) error
+}
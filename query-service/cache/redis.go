@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+type cacheRedis struct {
+	rds *redis.Client
+}
+
+func NewCacheRedis(rds *redis.Client) Cache {
+	return &cacheRedis{
+		rds: rds,
+	}
+}
+
+func (c *cacheRedis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.rds.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+
+	return value, err
+}
+
+func (c *cacheRedis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rds.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *cacheRedis) Del(ctx context.Context, keys ...string) error {
+	return c.rds.Del(ctx, keys...).Err()
+}
+
+func (c *cacheRedis) Incr(ctx context.Context, keys ...string) error {
+	pipe := c.rds.TxPipeline()
+	for _, key := range keys {
+		pipe.Incr(ctx, key)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
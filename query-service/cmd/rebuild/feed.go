@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Adhiana46/query-service/model"
+)
+
+type jsonResponse struct {
+	Error   bool            `json:"error"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// storedEvent mirrors an entry of command-service's GET /events feed.
+type storedEvent struct {
+	Position    int64           `json:"position"`
+	AggregateID string          `json:"aggregate_id"`
+	Sequence    int             `json:"sequence"`
+	EventType   string          `json:"event_type"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type articlePayload struct {
+	Slug     string   `json:"slug"`
+	Author   string   `json:"author"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Editor   string   `json:"editor"`
+	Revision int      `json:"revision"`
+	Status   string   `json:"status"`
+	Tags     []string `json:"tags"`
+	Category string   `json:"category"`
+
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+type commentPayload struct {
+	ArticleID string `json:"article_id"`
+	ParentID  string `json:"parent_id"`
+	ThreadID  string `json:"thread_id"`
+	Depth     int    `json:"depth"`
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	Editor    string `json:"editor"`
+	Status    string `json:"status"`
+	Moderator string `json:"moderator"`
+	Reason    string `json:"reason"`
+}
+
+// toComment applies a stored event to a copy of the comment it changes,
+// previous is nil for a comment.created. Comment events only carry what
+// changed, the timestamps come from the event itself.
+func (e storedEvent) toComment(previous *model.Comment) (*model.Comment, error) {
+	comment := model.Comment{}
+	if previous != nil {
+		comment = *previous
+	}
+	comment.Version = e.Sequence
+	comment.UpdatedAt = e.CreatedAt
+
+	var payload commentPayload
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return nil, err
+	}
+
+	switch e.EventType {
+	case commentCreatedEvent:
+		comment.Uuid = e.AggregateID
+		comment.ArticleID = payload.ArticleID
+		comment.ParentID = payload.ParentID
+		comment.ThreadID = payload.ThreadID
+		comment.Depth = payload.Depth
+		comment.Author = payload.Author
+		comment.Body = payload.Body
+		comment.Status = model.CommentVisible
+		comment.UpdatedBy = payload.Author
+		comment.CreatedAt = e.CreatedAt
+	case commentEditedEvent:
+		comment.Body = payload.Body
+		comment.UpdatedBy = payload.Editor
+	case commentDeletedEvent:
+		deletedAt := e.CreatedAt
+		comment.DeletedAt = &deletedAt
+	case commentModeratedEvent:
+		comment.Status = payload.Status
+		comment.ModeratedBy = payload.Moderator
+		comment.ModerationReason = payload.Reason
+	}
+
+	return &comment, nil
+}
+
+// toArticle turns a stored event into the article snapshot the projection
+// expects, the timestamps come from the event itself.
+func (e storedEvent) toArticle() (*model.Article, error) {
+	article := model.Article{
+		Uuid:      e.AggregateID,
+		Version:   e.Sequence,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.CreatedAt,
+	}
+
+	// only creates and updates carry the article fields
+	switch e.EventType {
+	case articleDeletedEvent, articleRestoredEvent, articlePurgedEvent:
+		return &article, nil
+	case articleSubmittedEvent:
+		article.Status = model.StatusInReview
+		return &article, nil
+	case articlePublishedEvent:
+		article.Status = model.StatusPublished
+		return &article, nil
+	case articleArchivedEvent:
+		article.Status = model.StatusArchived
+		return &article, nil
+	case articlePublishCancelledEvent:
+		return &article, nil
+	}
+
+	var payload articlePayload
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return nil, err
+	}
+
+	switch e.EventType {
+	case articlePublishScheduledEvent:
+		article.PublishAt = payload.PublishAt
+		return &article, nil
+	case articleSlugChangedEvent:
+		article.Slug = payload.Slug
+		return &article, nil
+	}
+
+	article.Slug = payload.Slug
+	article.Author = payload.Author
+	article.Title = payload.Title
+	article.Body = payload.Body
+	article.Tags = payload.Tags
+	article.Category = payload.Category
+	article.Revision = payload.Revision
+	article.UpdatedBy = payload.Editor
+	if article.UpdatedBy == "" {
+		article.UpdatedBy = payload.Author
+	}
+
+	// updates leave the status alone, articles created before the editorial
+	// workflow were published right away
+	if e.EventType == articleCreatedEvent {
+		article.Status = payload.Status
+		if article.Status == "" {
+			article.Status = model.StatusPublished
+		}
+	}
+
+	return &article, nil
+}
+
+func (app *Config) fetchEvents(after int64) ([]storedEvent, error) {
+	url := fmt.Sprintf("%s/events?after=%d&limit=%d", app.commandURL, after, app.batchSize)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var jsonFromService jsonResponse
+	err = json.NewDecoder(response.Body).Decode(&jsonFromService)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK || jsonFromService.Error {
+		return nil, errors.New("Error calling GET /events: " + jsonFromService.Message)
+	}
+
+	events := []storedEvent{}
+	err = json.Unmarshal(jsonFromService.Data, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
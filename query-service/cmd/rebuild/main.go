@@ -0,0 +1,481 @@
+// Command rebuild repopulates the Mongo articles read model from the event
+// store of command-service.
+//
+// The events are projected into a shadow collection which is then swapped in
+// with renameCollection. Live events keep landing in the old collection while
+// the shadow is built, so after the swap the tail of the feed is applied again
+// to the new collection. The projection is version guarded, re-applying an
+// event is harmless.
+//
+// The revision history is written straight to the live revisions collection,
+// a revision is only inserted when it is missing. The comments are written
+// straight to the live comments collection too, version guarded like the
+// articles. The tag and category counts and the comment counts are recounted
+// once the articles are caught up.
+//
+// The articles and lists the service cached from the swapped out collection
+// are dropped from Redis once the new collection caught up.
+//
+// With an Elasticsearch search backend the rebuilt articles are also indexed,
+// which backfills an empty or new index.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Adhiana46/query-service/cache"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/projection"
+	"github.com/Adhiana46/query-service/query"
+	"github.com/Adhiana46/query-service/search"
+	"github.com/go-redis/redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	articleCreatedEvent  = "article.created"
+	articleUpdatedEvent  = "article.updated"
+	articleDeletedEvent  = "article.deleted"
+	articleRestoredEvent = "article.restored"
+	articlePurgedEvent   = "article.purged"
+
+	articleSubmittedEvent = "article.submitted"
+	articlePublishedEvent = "article.published"
+	articleArchivedEvent  = "article.archived"
+
+	articlePublishScheduledEvent = "article.publish_scheduled"
+	articlePublishCancelledEvent = "article.publish_cancelled"
+
+	articleSlugChangedEvent = "article.slug_changed"
+
+	commentCreatedEvent   = "comment.created"
+	commentEditedEvent    = "comment.edited"
+	commentDeletedEvent   = "comment.deleted"
+	commentModeratedEvent = "comment.moderated"
+
+	databaseName             = "articles"
+	collectionName           = "articles"
+	revisionsCollectionName  = "revisions"
+	commentsCollectionName   = "comments"
+	tagsCollectionName       = "tags"
+	categoriesCollectionName = "categories"
+)
+
+type Config struct {
+	commandURL string
+	batchSize  int
+	overlap    int64
+
+	mongoDb *mongo.Client
+	rds     *redis.Client
+	cache   cache.Cache
+
+	// events written before revisions were numbered are numbered here in
+	// feed order, numberedTo is the last position that was numbered
+	revisionNumbers map[string]int
+	numberedTo      int64
+
+	// comment events only carry what changed, the comments are folded here
+	// from their events
+	comments map[string]*model.Comment
+}
+
+func main() {
+	app := Config{
+		revisionNumbers: map[string]int{},
+		comments:        map[string]*model.Comment{},
+	}
+
+	flag.StringVar(&app.commandURL, "command-url", os.Getenv("URL_COMMAND_SVC"), "base url of command-service")
+	flag.IntVar(&app.batchSize, "batch", 500, "number of events fetched per request")
+	flag.Int64Var(&app.overlap, "overlap", 1000, "number of positions re-applied after the swap to cover late commits")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	err := app.openMongodb(ctx)
+	if err != nil {
+		log.Fatalf("Can't open MongoDB connection: %s", err)
+	}
+	defer app.mongoDb.Disconnect(ctx)
+
+	app.openRedis()
+	defer app.rds.Close()
+	app.cache = cache.NewCacheRedis(app.rds)
+
+	started := time.Now()
+	shadowName := fmt.Sprintf("%s_rebuild_%d", collectionName, started.Unix())
+	shadow := app.mongoDb.Database(databaseName).Collection(shadowName)
+
+	log.Printf("Rebuilding %s.%s into %s", databaseName, collectionName, shadowName)
+
+	err = query.EnsureArticleIndexes(ctx, shadow)
+	if err != nil {
+		log.Fatalf("Can't create indexes on %s: %s", shadowName, err)
+	}
+
+	revisionsCollection := app.mongoDb.Database(databaseName).Collection(revisionsCollectionName)
+	err = projection.EnsureRevisionIndexes(ctx, revisionsCollection)
+	if err != nil {
+		log.Fatalf("Can't create indexes on %s: %s", revisionsCollectionName, err)
+	}
+	revisions := projection.NewRevisionProjectionMongo(revisionsCollection)
+
+	live := app.mongoDb.Database(databaseName).Collection(collectionName)
+
+	commentsCollection := app.mongoDb.Database(databaseName).Collection(commentsCollectionName)
+	err = projection.EnsureCommentIndexes(ctx, commentsCollection)
+	if err != nil {
+		log.Fatalf("Can't create indexes on %s: %s", commentsCollectionName, err)
+	}
+	comments := projection.NewCommentProjectionMongo(commentsCollection, live)
+
+	position, applied, err := app.replay(ctx, projection.NewArticleProjectionMongo(shadow), revisions, comments, 0)
+	if err != nil {
+		shadow.Drop(ctx)
+		log.Fatalf("Rebuild failed at position %d: %s", position, err)
+	}
+
+	log.Printf("Shadow collection built: %d events up to position %d in %s", applied, position, time.Since(started))
+
+	// the service caches what it reads from the collection that is swapped
+	// out, until the swap
+	staleUuids, staleAuthors, err := cachedArticles(ctx, live)
+	if err != nil {
+		shadow.Drop(ctx)
+		log.Fatalf("Can't list the cached articles of %s: %s", collectionName, err)
+	}
+
+	err = app.swap(ctx, shadowName)
+	if err != nil {
+		shadow.Drop(ctx)
+		log.Fatalf("Can't swap %s in: %s", shadowName, err)
+	}
+
+	log.Printf("Swapped %s into %s.%s", shadowName, databaseName, collectionName)
+
+	// events committed while the shadow was built went to the old collection
+	catchUpFrom := position - app.overlap
+	if catchUpFrom < 0 {
+		catchUpFrom = 0
+	}
+
+	position, applied, err = app.replay(ctx, projection.NewArticleProjectionMongo(live), revisions, comments, catchUpFrom)
+	if err != nil {
+		log.Fatalf("Catch-up failed at position %d: %s", position, err)
+	}
+
+	log.Printf("Caught up %d events up to position %d", applied, position)
+
+	uuids, authors, err := cachedArticles(ctx, live)
+	if err != nil {
+		log.Fatalf("Can't list the articles of %s: %s", collectionName, err)
+	}
+
+	dropped, err := app.dropCachedArticles(ctx, append(staleUuids, uuids...), append(staleAuthors, authors...))
+	if err != nil {
+		log.Fatalf("Can't drop the cached articles: %s", err)
+	}
+
+	log.Printf("Dropped %d cached articles and the article lists", dropped)
+
+	facets := projection.NewFacetProjectionMongo(
+		live,
+		app.mongoDb.Database(databaseName).Collection(tagsCollectionName),
+		app.mongoDb.Database(databaseName).Collection(categoriesCollectionName),
+	)
+	if err := facets.RefreshAll(ctx); err != nil {
+		log.Fatalf("Can't recount tags and categories: %s", err)
+	}
+
+	log.Println("Recounted tags and categories")
+
+	if err := comments.RecountAll(ctx); err != nil {
+		log.Fatalf("Can't recount comments: %s", err)
+	}
+
+	log.Println("Recounted comments")
+
+	// the bleve index is owned by the running service, it can't be opened here
+	switch os.Getenv("SEARCH_BACKEND") {
+	case "elasticsearch", "opensearch":
+		indexed, err := app.reindex(ctx, live)
+		if err != nil {
+			log.Fatalf("Reindex failed after %d articles: %s", indexed, err)
+		}
+
+		log.Printf("Indexed %d articles", indexed)
+	}
+
+	log.Printf("Rebuild finished in %s", time.Since(started))
+}
+
+// reindex writes every article of the collection to the search index. The
+// index rewrites the versions it holds and refuses older ones, so the
+// articles it holds get their recounted comments and the missing ones land.
+func (app *Config) reindex(ctx context.Context, collection *mongo.Collection) (int, error) {
+	index, err := search.NewElasticsearchIndex(ctx, os.Getenv("ELASTICSEARCH_URL"), os.Getenv("ELASTICSEARCH_INDEX"))
+	if err != nil {
+		return 0, err
+	}
+	defer index.Close()
+
+	// only published articles are indexed, trashed ones are kept out
+	cursor, err := collection.Find(ctx, bson.M{
+		"deleted_at": nil,
+		"status":     bson.M{"$in": bson.A{model.StatusPublished, nil}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	indexed := 0
+	for cursor.Next(ctx) {
+		var article model.Article
+		if err := cursor.Decode(&article); err != nil {
+			return indexed, err
+		}
+
+		if err := index.Index(ctx, &article); err != nil {
+			return indexed, err
+		}
+		indexed++
+	}
+
+	return indexed, cursor.Err()
+}
+
+// cachedArticles returns the uuids of the articles of the collection and
+// their authors, the keys the service caches them and their lists under.
+func cachedArticles(ctx context.Context, collection *mongo.Collection) ([]string, []string, error) {
+	uuids, err := collection.Distinct(ctx, "uuid", bson.M{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authors, err := collection.Distinct(ctx, "author", bson.M{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return distinctStrings(uuids), distinctStrings(authors), nil
+}
+
+func distinctStrings(values []interface{}) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+
+	return strs
+}
+
+// dropCachedArticles deletes the cached articles and moves every list the
+// authors show up in to a new generation. It returns how many article keys
+// were deleted.
+func (app *Config) dropCachedArticles(ctx context.Context, uuids []string, authors []string) (int, error) {
+	const chunkSize = 500
+
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, uuid := range uuids {
+		if seen[uuid] {
+			continue
+		}
+		seen[uuid] = true
+		keys = append(keys, fmt.Sprintf("article-%s", uuid))
+	}
+
+	for start := 0; start < len(keys); start += chunkSize {
+		end := start + chunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		if err := app.cache.Del(ctx, keys[start:end]...); err != nil {
+			return start, err
+		}
+	}
+
+	return len(keys), query.InvalidateArticleLists(ctx, app.cache, authors...)
+}
+
+// replay applies every event after the given position and returns the last
+// applied position.
+func (app *Config) replay(ctx context.Context, articles projection.ArticleProjection, revisions projection.RevisionProjection, comments projection.CommentProjection, after int64) (int64, int, error) {
+	applied := 0
+
+	for {
+		events, err := app.fetchEvents(after)
+		if err != nil {
+			return after, applied, err
+		}
+
+		if len(events) == 0 {
+			return after, applied, nil
+		}
+
+		for _, e := range events {
+			if err := app.apply(ctx, articles, revisions, comments, e); err != nil {
+				return after, applied, err
+			}
+
+			after = e.Position
+			applied++
+		}
+
+		log.Printf("Applied %d events (position %d)", applied, after)
+	}
+}
+
+func (app *Config) apply(ctx context.Context, articles projection.ArticleProjection, revisions projection.RevisionProjection, comments projection.CommentProjection, e storedEvent) error {
+	switch e.EventType {
+	case commentCreatedEvent, commentEditedEvent, commentDeletedEvent, commentModeratedEvent:
+		return app.applyComment(ctx, comments, e)
+	}
+
+	article, err := e.toArticle()
+	if err != nil {
+		return err
+	}
+
+	switch e.EventType {
+	case articleCreatedEvent:
+		if err := app.recordRevision(ctx, revisions, article, e.Position); err != nil {
+			return err
+		}
+		_, err := articles.Created(ctx, article)
+		return err
+	case articleUpdatedEvent:
+		if err := app.recordRevision(ctx, revisions, article, e.Position); err != nil {
+			return err
+		}
+		_, _, err = articles.Updated(ctx, article)
+		return err
+	case articleDeletedEvent:
+		_, err = articles.Deleted(ctx, article, e.CreatedAt)
+		return err
+	case articleRestoredEvent:
+		_, err = articles.Restored(ctx, article, e.CreatedAt)
+		return err
+	case articleSubmittedEvent, articlePublishedEvent, articleArchivedEvent:
+		_, err = articles.StatusChanged(ctx, article, e.CreatedAt)
+		return err
+	case articlePublishScheduledEvent, articlePublishCancelledEvent:
+		_, err = articles.ScheduleChanged(ctx, article, e.CreatedAt)
+		return err
+	case articleSlugChangedEvent:
+		_, err = articles.SlugChanged(ctx, article, e.CreatedAt)
+		return err
+	case articlePurgedEvent:
+		// the events are replayed in order, a missing article has nothing
+		// left to purge
+		_, err = articles.Purged(ctx, article.Uuid, article.Version)
+		if err != nil && !errors.Is(err, projection.ErrNotProjected) {
+			return err
+		}
+		if err := revisions.Purged(ctx, article.Uuid); err != nil {
+			return err
+		}
+		return comments.ArticlePurged(ctx, article.Uuid)
+	}
+
+	log.Printf("Skipping unknown event %s at position %d", e.EventType, e.Position)
+
+	return nil
+}
+
+// applyComment folds the event into its comment and writes the result. The
+// catch-up pass leaves the events that were folded already alone.
+func (app *Config) applyComment(ctx context.Context, comments projection.CommentProjection, e storedEvent) error {
+	previous := app.comments[e.AggregateID]
+	if previous != nil && previous.Version >= e.Sequence {
+		return nil
+	}
+
+	if previous == nil && e.EventType != commentCreatedEvent {
+		log.Printf("Skipping %s of unknown comment %s at position %d", e.EventType, e.AggregateID, e.Position)
+		return nil
+	}
+
+	comment, err := e.toComment(previous)
+	if err != nil {
+		return err
+	}
+
+	app.comments[comment.Uuid] = comment
+
+	if e.EventType == commentCreatedEvent {
+		return comments.Created(ctx, comment)
+	}
+
+	_, err = comments.Updated(ctx, comment)
+	return err
+}
+
+// recordRevision writes the revision of a create or update. Events without a
+// revision number are numbered after the previous revision of the article,
+// the catch-up pass leaves the positions that were numbered already alone.
+func (app *Config) recordRevision(ctx context.Context, revisions projection.RevisionProjection, article *model.Article, position int64) error {
+	if article.Revision == 0 {
+		if position <= app.numberedTo {
+			return nil
+		}
+
+		article.Revision = app.revisionNumbers[article.Uuid] + 1
+		app.numberedTo = position
+	}
+
+	app.revisionNumbers[article.Uuid] = article.Revision
+
+	return revisions.Recorded(ctx, model.RevisionOf(article))
+}
+
+// swap atomically replaces the live collection with the shadow one.
+func (app *Config) swap(ctx context.Context, shadowName string) error {
+	return app.mongoDb.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: fmt.Sprintf("%s.%s", databaseName, shadowName)},
+		{Key: "to", Value: fmt.Sprintf("%s.%s", databaseName, collectionName)},
+		{Key: "dropTarget", Value: true},
+	}).Err()
+}
+
+func (app *Config) openRedis() {
+	app.rds = redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%v:%v", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       0, // use default DB
+	})
+}
+
+func (app *Config) openMongodb(ctx context.Context) error {
+	clientOptions := options.Client().ApplyURI(os.Getenv("MONGO_URL"))
+	clientOptions.SetAuth(options.Credential{
+		Username: os.Getenv("MONGO_USERNAME"),
+		Password: os.Getenv("MONGO_PASSWORD"),
+	})
+
+	c, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Ping(ctx, nil); err != nil {
+		return err
+	}
+
+	app.mongoDb = c
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	contextLines = 3
+
+	// above this many line pairs the texts are reported as replaced as a
+	// whole instead of being compared line by line
+	maxCompareCells = 10_000_000
+)
+
+type lineOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// Unified returns the line diff of two texts in unified format with three
+// lines of context, or an empty string when they are equal.
+func Unified(fromName, toName, from, to string) string {
+	a := splitLines(from)
+	b := splitLines(to)
+
+	ops := lineOps(a, b)
+
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	// line number in a and b before every op
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aPos[i+1] = aPos[i]
+		bPos[i+1] = bPos[i]
+		if op.kind != '+' {
+			aPos[i+1]++
+		}
+		if op.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	i := 0
+	for i < len(ops) {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+
+		// grow the hunk while the next change is close enough to share context
+		j := i
+		for {
+			for j < len(ops) && ops[j].kind != ' ' {
+				j++
+			}
+			k := j
+			for k < len(ops) && ops[k].kind == ' ' {
+				k++
+			}
+			if k < len(ops) && k-j <= 2*contextLines {
+				j = k
+				continue
+			}
+			break
+		}
+
+		end := j + contextLines
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		writeHunk(&out, ops[start:end], aPos[start], aPos[end]-aPos[start], bPos[start], bPos[end]-bPos[start])
+		i = end
+	}
+
+	return out.String()
+}
+
+func writeHunk(out *strings.Builder, ops []lineOp, aStart, aCount, bStart, bCount int) {
+	// ranges start at line 1, an empty range names the line before it
+	if aCount > 0 {
+		aStart++
+	}
+	if bCount > 0 {
+		bStart++
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+	for _, op := range ops {
+		out.WriteByte(op.kind)
+		out.WriteString(op.text)
+		out.WriteByte('\n')
+	}
+}
+
+// lineOps turns a into b through a longest common subsequence of lines.
+func lineOps(a, b []string) []lineOp {
+	ops := []lineOp{}
+
+	if len(a)*len(b) > maxCompareCells {
+		for _, line := range a {
+			ops = append(ops, lineOp{kind: '-', text: line})
+		}
+		for _, line := range b {
+			ops = append(ops, lineOp{kind: '+', text: line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the common subsequence length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, lineOp{kind: ' ', text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{kind: '-', text: a[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{kind: '+', text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, lineOp{kind: '-', text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, lineOp{kind: '+', text: b[j]})
+	}
+
+	return ops
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -8,32 +8,98 @@ import (
 
 type ResponseArticle struct {
 	Uuid      string    `json:"uuid"`
+	Slug      string    `json:"slug"`
 	Author    string    `json:"author"`
 	Title     string    `json:"title"`
 	Body      string    `json:"body"`
+	Tags      []string  `json:"tags"`
+	Category  string    `json:"category"`
+	Status    string    `json:"status"`
+	Version   int       `json:"version"`
+	Revision  int       `json:"revision"`
+	UpdatedBy string    `json:"updated_by"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	CommentCount int64 `json:"comment_count"`
+
+	DeletedAt  *time.Time          `json:"deleted_at,omitempty"`
+	PublishAt  *time.Time          `json:"publish_at,omitempty"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+// Viewer is the authenticated caller, empty for anonymous reads. Unpublished
+// articles are only shown to their author and to editors.
+type Viewer struct {
+	Subject string `json:"subject"`
+	Editor  bool   `json:"editor"`
+}
+
+// CanSee reports whether the viewer may read the article.
+func (v Viewer) CanSee(article *model.Article) bool {
+	return article.IsPublished() || v.Editor || (v.Subject != "" && v.Subject == article.Author)
 }
 
 type RequestSingleArticle struct {
-	Uuid string `validate:"required"`
+	Uuid   string `validate:"required"`
+	Viewer Viewer
+}
+
+// RequestArticleBySlug looks an article up by its current or a retired slug.
+type RequestArticleBySlug struct {
+	Slug   string `validate:"required,max=100"`
+	Viewer Viewer
 }
 
 type RequestListArticle struct {
-	Page   int    `json:"page" validate:""`
-	Limit  int    `json:"limit" validate:""`
+	Page   int    `json:"page" validate:"min=1"`
+	Limit  int    `json:"limit" validate:"min=1,max=100"`
 	Query  string `json:"query" validate:""`
 	Author string `json:"author" validate:""`
+	Status string `json:"status" validate:"omitempty,oneof=draft in_review published archived"`
+	Viewer Viewer `json:"viewer"`
+
+	// Tag and every tag of TagsAll must be on the article, and at least one
+	// of TagsAny
+	Tag      string   `json:"tag" validate:"max=50"`
+	TagsAll  []string `json:"tags_all" validate:"max=20,dive,max=50"`
+	TagsAny  []string `json:"tags_any" validate:"max=20,dive,max=50"`
+	Category string   `json:"category" validate:"max=50"`
+
+	// zero values leave the range open
+	CreatedFrom time.Time `json:"created_from"`
+	CreatedTo   time.Time `json:"created_to"`
+}
+
+type ResponseListArticle struct {
+	Articles   []*ResponseArticle `json:"articles"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
 }
 
 func ArticleToResponseDTO(article *model.Article) *ResponseArticle {
 	return &ResponseArticle{
 		Uuid:      article.Uuid,
+		Slug:      article.Slug,
 		Author:    article.Author,
 		Title:     article.Title,
 		Body:      article.Body,
+		Tags:      tagsOf(article),
+		Category:  article.Category,
+		Status:    article.Status,
+		Version:   article.Version,
+		Revision:  article.Revision,
+		UpdatedBy: article.UpdatedBy,
 		CreatedAt: article.CreatedAt,
 		UpdatedAt: article.UpdatedAt,
+
+		CommentCount: article.CommentCount,
+
+		DeletedAt:  article.DeletedAt,
+		PublishAt:  article.PublishAt,
+		Highlights: article.Highlights,
 	}
 }
 
@@ -44,3 +110,27 @@ func ArticlesToResponseDtos(articles []*model.Article) []*ResponseArticle {
 	}
 	return result
 }
+
+func ArticlesToResponseListDTO(articles []*model.Article, total int64, reqDto RequestListArticle) *ResponseListArticle {
+	totalPages := 0
+	if reqDto.Limit > 0 {
+		totalPages = int((total + int64(reqDto.Limit) - 1) / int64(reqDto.Limit))
+	}
+
+	return &ResponseListArticle{
+		Articles:   ArticlesToResponseDtos(articles),
+		Total:      total,
+		Page:       reqDto.Page,
+		Limit:      reqDto.Limit,
+		TotalPages: totalPages,
+	}
+}
+
+// tagsOf is never nil, articles projected before tags existed have none.
+func tagsOf(article *model.Article) []string {
+	if article.Tags == nil {
+		return []string{}
+	}
+
+	return article.Tags
+}
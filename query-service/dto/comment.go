@@ -0,0 +1,114 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Adhiana46/query-service/model"
+)
+
+// ResponseComment is a comment with its replies. Deleted comments keep their
+// place in the thread without author or body, hidden ones without body
+// unless the viewer is their author or an editor.
+type ResponseComment struct {
+	Uuid      string    `json:"uuid"`
+	ArticleID string    `json:"article_id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Depth     int       `json:"depth"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Status    string    `json:"status"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	ModerationReason string     `json:"moderation_reason,omitempty"`
+
+	Replies []*ResponseComment `json:"replies"`
+}
+
+// RequestListComment pages through the top-level comments of an article,
+// every page carries the whole threads of its comments.
+type RequestListComment struct {
+	ArticleID string `json:"article_id" validate:"required"`
+	Page      int    `json:"page" validate:"min=1"`
+	Limit     int    `json:"limit" validate:"min=1,max=100"`
+	Viewer    Viewer `json:"viewer"`
+}
+
+type ResponseListComment struct {
+	Comments   []*ResponseComment `json:"comments"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
+
+func CommentToResponseDTO(comment *model.Comment, viewer Viewer) *ResponseComment {
+	result := &ResponseComment{
+		Uuid:      comment.Uuid,
+		ArticleID: comment.ArticleID,
+		ParentID:  comment.ParentID,
+		Depth:     comment.Depth,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		Status:    comment.Status,
+		Version:   comment.Version,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+
+		DeletedAt: comment.DeletedAt,
+		Replies:   []*ResponseComment{},
+	}
+
+	switch {
+	case comment.DeletedAt != nil:
+		result.Author = ""
+		result.Body = ""
+	case comment.Status == model.CommentHidden:
+		if viewer.Editor || (viewer.Subject != "" && viewer.Subject == comment.Author) {
+			result.ModerationReason = comment.ModerationReason
+		} else {
+			result.Body = ""
+		}
+	}
+
+	return result
+}
+
+// CommentsToResponseListDTO nests the replies under the comments they
+// answer, in the order the comments come in. The total counts the top-level
+// comments.
+func CommentsToResponseListDTO(comments []*model.Comment, total int64, reqDto RequestListComment) *ResponseListComment {
+	totalPages := 0
+	if reqDto.Limit > 0 {
+		totalPages = int((total + int64(reqDto.Limit) - 1) / int64(reqDto.Limit))
+	}
+
+	responses := map[string]*ResponseComment{}
+	for _, comment := range comments {
+		responses[comment.Uuid] = CommentToResponseDTO(comment, reqDto.Viewer)
+	}
+
+	result := []*ResponseComment{}
+	for _, comment := range comments {
+		response := responses[comment.Uuid]
+		if comment.ParentID == "" {
+			result = append(result, response)
+			continue
+		}
+
+		// the page carries whole threads, the parent of a reply is in it
+		if parent, ok := responses[comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, response)
+		}
+	}
+
+	return &ResponseListComment{
+		Comments:   result,
+		Total:      total,
+		Page:       reqDto.Page,
+		Limit:      reqDto.Limit,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Adhiana46/query-service/event"
+)
+
+type ResponseDeadLetter struct {
+	MessageID      string          `json:"message_id"`
+	RoutingKey     string          `json:"routing_key"`
+	Error          string          `json:"error"`
+	Retries        int             `json:"retries"`
+	DeadLetteredAt time.Time       `json:"dead_lettered_at"`
+	Body           json.RawMessage `json:"body"`
+}
+
+func DeadLetterToResponseDTO(deadLetter *event.DeadLetter) *ResponseDeadLetter {
+	body := json.RawMessage(deadLetter.Body)
+	if !json.Valid(body) {
+		// keep undecodable payloads readable
+		body, _ = json.Marshal(string(deadLetter.Body))
+	}
+
+	return &ResponseDeadLetter{
+		MessageID:      deadLetter.MessageID,
+		RoutingKey:     deadLetter.RoutingKey,
+		Error:          deadLetter.Error,
+		Retries:        deadLetter.Retries,
+		DeadLetteredAt: deadLetter.DeadLetteredAt,
+		Body:           body,
+	}
+}
+
+func DeadLettersToResponseDtos(deadLetters []event.DeadLetter) []*ResponseDeadLetter {
+	result := []*ResponseDeadLetter{}
+	for i := range deadLetters {
+		result = append(result, DeadLetterToResponseDTO(&deadLetters[i]))
+	}
+	return result
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/Adhiana46/query-service/model"
+
+type ResponseFacet struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// RequestListFacet asks for the most used tags or categories.
+type RequestListFacet struct {
+	Limit int `json:"limit" validate:"min=1,max=1000"`
+}
+
+func FacetsToResponseDtos(facets []*model.Facet) []*ResponseFacet {
+	result := []*ResponseFacet{}
+	for _, facet := range facets {
+		result = append(result, &ResponseFacet{
+			Name:  facet.Name,
+			Count: facet.Count,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Adhiana46/query-service/model"
+)
+
+type ResponseRevision struct {
+	Uuid      string    `json:"uuid"`
+	Revision  int       `json:"revision"`
+	Version   int       `json:"version"`
+	Author    string    `json:"author"`
+	Editor    string    `json:"editor"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RequestListRevision struct {
+	Uuid  string `json:"uuid" validate:"required"`
+	Page  int    `json:"page" validate:"min=1"`
+	Limit int    `json:"limit" validate:"min=1,max=100"`
+}
+
+type RequestSingleRevision struct {
+	Uuid     string `validate:"required"`
+	Revision int    `validate:"min=1"`
+}
+
+type ResponseListRevision struct {
+	Revisions  []*ResponseRevision `json:"revisions"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// ResponseDiff is the unified diff between two revisions, revision 0 stands
+// for the empty article before it was created.
+type ResponseDiff struct {
+	Uuid string `json:"uuid"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Diff string `json:"diff"`
+}
+
+func RevisionToResponseDTO(revision *model.Revision) *ResponseRevision {
+	return &ResponseRevision{
+		Uuid:      revision.Uuid,
+		Revision:  revision.Revision,
+		Version:   revision.Version,
+		Author:    revision.Author,
+		Editor:    revision.Editor,
+		Title:     revision.Title,
+		Body:      revision.Body,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+func RevisionsToResponseListDTO(revisions []*model.Revision, total int64, reqDto RequestListRevision) *ResponseListRevision {
+	totalPages := 0
+	if reqDto.Limit > 0 {
+		totalPages = int((total + int64(reqDto.Limit) - 1) / int64(reqDto.Limit))
+	}
+
+	result := []*ResponseRevision{}
+	for _, revision := range revisions {
+		result = append(result, RevisionToResponseDTO(revision))
+	}
+
+	return &ResponseListRevision{
+		Revisions:  result,
+		Total:      total,
+		Page:       reqDto.Page,
+		Limit:      reqDto.Limit,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,201 @@
+package event
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type ConnectionState string
+
+const (
+	StateConnecting   ConnectionState = "connecting"
+	StateConnected    ConnectionState = "connected"
+	StateReconnecting ConnectionState = "reconnecting"
+	StateClosed       ConnectionState = "closed"
+
+	maxDialAttempts   = 5
+	maxReconnectDelay = 30 * time.Second
+)
+
+var (
+	ErrNotConnected     = errors.New("RabbitMQ connection is not available")
+	ErrConnectionClosed = errors.New("RabbitMQ connection was closed")
+)
+
+// Connection wraps an AMQP connection and redials it with backoff whenever
+// the broker drops it. Emitters and consumers ask it for a fresh channel on
+// every use, and wait on NotifyReconnect to redeclare their topology.
+type Connection struct {
+	dsn string
+
+	mu          sync.RWMutex
+	conn        *amqp.Connection
+	state       ConnectionState
+	lastErr     error
+	reconnected chan struct{}
+}
+
+// Dial opens the connection, retrying a few times while the broker starts.
+func Dial(dsn string) (*Connection, error) {
+	c := &Connection{
+		dsn:         dsn,
+		state:       StateConnecting,
+		reconnected: make(chan struct{}),
+	}
+
+	var count int64
+	for {
+		conn, err := amqp.Dial(dsn)
+		if err == nil {
+			log.Println("Connected to RabbitMQ...")
+			c.setConnected(conn)
+			break
+		}
+
+		log.Println("RabbitMQ not yet ready...", err)
+		count++
+
+		if count > maxDialAttempts {
+			log.Println("Could not connect to RabbitMQ", err)
+			return nil, err
+		}
+
+		retryTime := time.Duration(math.Pow(float64(count), 2)) * time.Second
+		log.Println("Retrying in", retryTime)
+		time.Sleep(retryTime)
+	}
+
+	return c, nil
+}
+
+// Channel opens a channel on the current connection.
+func (c *Connection) Channel() (*amqp.Channel, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.state == StateClosed {
+		return nil, ErrConnectionClosed
+	}
+	if c.state != StateConnected {
+		return nil, ErrNotConnected
+	}
+
+	return c.conn.Channel()
+}
+
+func (c *Connection) State() ConnectionState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.state
+}
+
+func (c *Connection) IsConnected() bool {
+	return c.State() == StateConnected
+}
+
+// LastError is the error that caused the latest disconnect.
+func (c *Connection) LastError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastErr
+}
+
+// NotifyReconnect returns a channel that is closed on the next successful
+// reconnect.
+func (c *Connection) NotifyReconnect() <-chan struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.reconnected
+}
+
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateClosed {
+		return nil
+	}
+
+	c.state = StateClosed
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+func (c *Connection) setConnected(conn *amqp.Connection) {
+	c.mu.Lock()
+	c.conn = conn
+	c.state = StateConnected
+	c.mu.Unlock()
+
+	go c.watch(conn)
+}
+
+// watch waits for the connection to drop and redials it until it succeeds or
+// Close is called.
+func (c *Connection) watch(conn *amqp.Connection) {
+	amqpErr, ok := <-conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	c.mu.Lock()
+	if c.state == StateClosed {
+		c.mu.Unlock()
+		return
+	}
+	c.state = StateReconnecting
+	if ok && amqpErr != nil {
+		c.lastErr = amqpErr
+	} else {
+		c.lastErr = ErrNotConnected
+	}
+	c.mu.Unlock()
+
+	log.Println("RabbitMQ connection lost:", c.LastError())
+
+	var count int64
+	for {
+		count++
+		retryTime := time.Duration(math.Pow(2, float64(count-1))) * time.Second
+		if retryTime > maxReconnectDelay {
+			retryTime = maxReconnectDelay
+		}
+		log.Println("Reconnecting to RabbitMQ in", retryTime)
+		time.Sleep(retryTime)
+
+		if c.State() == StateClosed {
+			return
+		}
+
+		newConn, err := amqp.Dial(c.dsn)
+		if err != nil {
+			log.Println("RabbitMQ not yet ready...", err)
+			continue
+		}
+
+		log.Println("Reconnected to RabbitMQ...")
+
+		c.mu.Lock()
+		if c.state == StateClosed {
+			c.mu.Unlock()
+			newConn.Close()
+			return
+		}
+		c.conn = newConn
+		c.state = StateConnected
+		close(c.reconnected)
+		c.reconnected = make(chan struct{})
+		c.mu.Unlock()
+
+		go c.watch(newConn)
+		return
+	}
+}
@@ -1,49 +1,146 @@
 package event
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-type Consumer struct {
-	conn         *amqp.Connection
+const (
+	headerRetryCount         = "x-retry-count"
+	headerOriginalRoutingKey = "x-original-routing-key"
+	headerError              = "x-error"
+	headerDeadLetteredAt     = "x-dead-lettered-at"
+)
+
+// RetryPolicy controls how often a failing message is retried before it is
+// dead-lettered. The n-th retry waits BaseDelay * 2^(n-1).
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  1 * time.Second,
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	return time.Duration(math.Pow(2, float64(attempt-1))) * p.BaseDelay
+}
+
+// PermanentError marks a failure that retrying can not fix, such as a
+// payload that can not be decoded. The message is dead-lettered right away.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+type rabbitmqConsumer struct {
+	conn         *Connection
 	exchangeName string
+	policy       RetryPolicy
 
-	handlePayload func(msg *amqp.Delivery)
+	handlePayload Handler
 }
 
-func NewConsumer(conn *amqp.Connection, exchangeName string, handlePayload func(msg *amqp.Delivery)) (Consumer, error) {
-	consumer := Consumer{
+func NewConsumerRabbitmq(conn *Connection, exchangeName string, policy RetryPolicy, handlePayload Handler) (Consumer, error) {
+	consumer := &rabbitmqConsumer{
 		conn:          conn,
 		exchangeName:  exchangeName,
+		policy:        policy,
 		handlePayload: handlePayload,
 	}
 
 	err := consumer.setup()
 	if err != nil {
-		return Consumer{}, err
+		return nil, err
 	}
 
 	return consumer, nil
 }
 
-func (c *Consumer) setup() error {
+func (c *rabbitmqConsumer) setup() error {
 	ch, err := c.conn.Channel()
 	if err != nil {
 		return err
 	}
+	defer ch.Close()
+
+	err = declareExchange(ch, c.exchangeName)
+	if err != nil {
+		return err
+	}
+
+	// one delay queue per attempt, each one with its own TTL
+	for attempt := 1; attempt <= c.policy.MaxRetries; attempt++ {
+		_, err = declareDelayQueue(ch, retryQueueName(c.exchangeName, attempt), c.exchangeName, c.policy.delay(attempt))
+		if err != nil {
+			return err
+		}
+	}
+
+	// messages that keep failing end up in the dead-letter queue
+	err = declareDeadLetterExchange(ch, deadLetterExchangeName(c.exchangeName))
+	if err != nil {
+		return err
+	}
+
+	dlq, err := declareQueue(ch, deadLetterQueueName(c.exchangeName))
+	if err != nil {
+		return err
+	}
 
-	return declareExchange(ch, c.exchangeName)
+	return ch.QueueBind(dlq.Name, "", deadLetterExchangeName(c.exchangeName), false, nil)
 }
 
-type Payload struct {
-	Name string `json:"name"`
-	Data string `json:"data"`
+// Listen consumes the topics until the connection is closed. When the broker
+// drops the connection or the channel, the topology is declared again and
+// consuming resumes as soon as the connection is back.
+func (c *rabbitmqConsumer) Listen(topics []string) error {
+	for {
+		reconnected := c.conn.NotifyReconnect()
+
+		err := c.consume(topics)
+		if c.conn.State() == StateClosed {
+			return ErrConnectionClosed
+		}
+
+		log.Println("Consumer stopped:", err)
+
+		if c.conn.IsConnected() {
+			// only the channel was closed, open a new one
+			time.Sleep(time.Second)
+			continue
+		}
+
+		log.Println("Waiting for RabbitMQ to reconnect...")
+		<-reconnected
+	}
 }
 
-func (c *Consumer) Listen(topics []string) error {
+// consume declares the topology and handles messages until the channel closes.
+func (c *rabbitmqConsumer) consume(topics []string) error {
+	err := c.setup()
+	if err != nil {
+		return err
+	}
+
 	ch, err := c.conn.Channel()
 	if err != nil {
 		return err
@@ -92,17 +189,140 @@ func (c *Consumer) Listen(topics []string) error {
 		return err
 	}
 
-	forever := make(chan bool)
-	go func() {
-		for msg := range messages {
-			log.Println("[MSG]:", msg.Exchange, msg.RoutingKey)
-			c.handlePayload(&msg)
+	fmt.Printf("Waiting for messages [Exchange, Queue] [%s, %s]\n", c.exchangeName, q.Name)
+
+	for msg := range messages {
+		// retried messages come back through the default exchange
+		if routingKey, ok := msg.Headers[headerOriginalRoutingKey].(string); ok && routingKey != "" {
+			msg.RoutingKey = routingKey
 		}
-	}()
 
-	fmt.Printf("Waiting for messages [Exchange, Queue] [%s, %s]\n", c.exchangeName, q.Name)
+		log.Println("[MSG]:", msg.Exchange, msg.RoutingKey)
+
+		err := c.handlePayload(fromDelivery(&msg))
+		if err == nil {
+			msg.Ack(false)
+			continue
+		}
+
+		c.handleFailure(ch, &msg, err)
+	}
+
+	return errors.New("delivery channel closed")
+}
+
+// handleFailure schedules a retry through the delay queue of the next
+// attempt, or dead-letters the message once the retries are exhausted.
+func (c *rabbitmqConsumer) handleFailure(ch *amqp.Channel, msg *amqp.Delivery, handleErr error) {
+	attempt := retryCount(msg) + 1
+
+	var permanent *PermanentError
+	var err error
+	if errors.As(handleErr, &permanent) || attempt > c.policy.MaxRetries {
+		log.Printf("[DLQ]: %s after %d attempts: %s", msg.RoutingKey, attempt, handleErr)
+		err = c.deadLetter(ch, msg, attempt-1, handleErr)
+	} else {
+		log.Printf("[RETRY]: %s attempt %d in %s: %s", msg.RoutingKey, attempt, c.policy.delay(attempt), handleErr)
+		err = c.retry(ch, msg, attempt)
+	}
+
+	if err != nil {
+		// could not move the message, let the broker deliver it again
+		log.Println("Can't reschedule message:", err)
+		msg.Nack(false, true)
+		return
+	}
+
+	msg.Ack(false)
+}
+
+func (c *rabbitmqConsumer) retry(ch *amqp.Channel, msg *amqp.Delivery, attempt int) error {
+	headers := copyHeaders(msg.Headers)
+	headers[headerRetryCount] = int32(attempt)
+	headers[headerOriginalRoutingKey] = msg.RoutingKey
+
+	return ch.Publish(
+		"",                                      // default exchange
+		retryQueueName(c.exchangeName, attempt), // routing key = delay queue
+		false,
+		false,
+		republishing(msg, headers),
+	)
+}
 
-	<-forever
+func (c *rabbitmqConsumer) deadLetter(ch *amqp.Channel, msg *amqp.Delivery, retries int, handleErr error) error {
+	headers := copyHeaders(msg.Headers)
+	headers[headerRetryCount] = int32(retries)
+	headers[headerOriginalRoutingKey] = msg.RoutingKey
+	headers[headerError] = handleErr.Error()
+	headers[headerDeadLetteredAt] = time.Now().UTC().Format(time.RFC3339)
+
+	publishing := republishing(msg, headers)
+	if publishing.MessageId == "" {
+		publishing.MessageId = newMessageID()
+	}
+
+	return ch.Publish(
+		deadLetterExchangeName(c.exchangeName),
+		msg.RoutingKey,
+		false,
+		false,
+		publishing,
+	)
+}
+
+func fromDelivery(msg *amqp.Delivery) *Message {
+	return &Message{
+		ID:          msg.MessageId,
+		EventType:   msg.RoutingKey,
+		ContentType: msg.ContentType,
+		Timestamp:   msg.Timestamp,
+		Body:        msg.Body,
+	}
+}
+
+func republishing(msg *amqp.Delivery, headers amqp.Table) amqp.Publishing {
+	return amqp.Publishing{
+		Headers:       headers,
+		ContentType:   msg.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: msg.CorrelationId,
+		MessageId:     msg.MessageId,
+		Timestamp:     msg.Timestamp,
+		Type:          msg.Type,
+		Body:          msg.Body,
+	}
+}
+
+func retryCount(msg *amqp.Delivery) int {
+	switch count := msg.Headers[headerRetryCount].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	}
+
+	return 0
+}
+
+func copyHeaders(headers amqp.Table) amqp.Table {
+	result := amqp.Table{}
+	for key, value := range headers {
+		result[key] = value
+	}
+	return result
+}
+
+func retryQueueName(queueName string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", queueName, attempt)
+}
+
+func deadLetterExchangeName(exchangeName string) string {
+	return exchangeName + ".dlx"
+}
 
-	return nil
+func deadLetterQueueName(queueName string) string {
+	return queueName + ".dead"
 }
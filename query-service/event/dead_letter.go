@@ -0,0 +1,172 @@
+package event
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead-lettered message not found")
+
+type DeadLetter struct {
+	MessageID      string
+	RoutingKey     string
+	Error          string
+	Retries        int
+	DeadLetteredAt time.Time
+	ContentType    string
+	Body           []byte
+}
+
+// DeadLetters gives access to the dead-letter queue of a consumer. Messages
+// are browsed with basic.get and handed back to the queue when the channel is
+// closed, only the ones acked explicitly leave it.
+type DeadLetters struct {
+	conn         *Connection
+	exchangeName string
+}
+
+func NewDeadLetters(conn *Connection, exchangeName string) DeadLetters {
+	return DeadLetters{
+		conn:         conn,
+		exchangeName: exchangeName,
+	}
+}
+
+func (d *DeadLetters) List(limit int) ([]DeadLetter, error) {
+	ch, err := d.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	defer ch.Close()
+
+	result := []DeadLetter{}
+	for len(result) < limit {
+		msg, ok, err := ch.Get(deadLetterQueueName(d.exchangeName), false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		result = append(result, toDeadLetter(&msg))
+	}
+
+	return result, nil
+}
+
+func (d *DeadLetters) Get(messageID string) (*DeadLetter, error) {
+	var result *DeadLetter
+
+	err := d.find(messageID, func(ch *amqp.Channel, msg *amqp.Delivery) error {
+		deadLetter := toDeadLetter(msg)
+		result = &deadLetter
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Requeue publishes the message to the consumer exchange again with a fresh
+// retry budget and removes it from the dead-letter queue.
+func (d *DeadLetters) Requeue(messageID string) (*DeadLetter, error) {
+	var result *DeadLetter
+
+	err := d.find(messageID, func(ch *amqp.Channel, msg *amqp.Delivery) error {
+		deadLetter := toDeadLetter(msg)
+
+		headers := copyHeaders(msg.Headers)
+		delete(headers, headerRetryCount)
+		delete(headers, headerOriginalRoutingKey)
+		delete(headers, headerError)
+		delete(headers, headerDeadLetteredAt)
+
+		err := ch.Publish(d.exchangeName, deadLetter.RoutingKey, false, false, republishing(msg, headers))
+		if err != nil {
+			return err
+		}
+
+		result = &deadLetter
+		return msg.Ack(false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *DeadLetters) Delete(messageID string) error {
+	return d.find(messageID, func(ch *amqp.Channel, msg *amqp.Delivery) error {
+		return msg.Ack(false)
+	})
+}
+
+func (d *DeadLetters) Purge() (int, error) {
+	ch, err := d.conn.Channel()
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	return ch.QueuePurge(deadLetterQueueName(d.exchangeName), false)
+}
+
+// find walks the dead-letter queue until messageID shows up and calls fn
+// with it. Every other message goes back to the queue.
+func (d *DeadLetters) find(messageID string, fn func(ch *amqp.Channel, msg *amqp.Delivery) error) error {
+	ch, err := d.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	for {
+		msg, ok, err := ch.Get(deadLetterQueueName(d.exchangeName), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrDeadLetterNotFound
+		}
+
+		if msg.MessageId == messageID {
+			return fn(ch, &msg)
+		}
+	}
+}
+
+func toDeadLetter(msg *amqp.Delivery) DeadLetter {
+	deadLetter := DeadLetter{
+		MessageID:   msg.MessageId,
+		RoutingKey:  msg.RoutingKey,
+		Retries:     retryCount(msg),
+		ContentType: msg.ContentType,
+		Body:        msg.Body,
+	}
+
+	if routingKey, ok := msg.Headers[headerOriginalRoutingKey].(string); ok && routingKey != "" {
+		deadLetter.RoutingKey = routingKey
+	}
+	if errMessage, ok := msg.Headers[headerError].(string); ok {
+		deadLetter.Error = errMessage
+	}
+	if deadLetteredAt, ok := msg.Headers[headerDeadLetteredAt].(string); ok {
+		deadLetter.DeadLetteredAt, _ = time.Parse(time.RFC3339, deadLetteredAt)
+	}
+
+	return deadLetter
+}
+
+func newMessageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,41 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Envelope is the wire format of the events published by command-service.
+type Envelope struct {
+	EventID          string          `json:"event_id"`
+	EventType        string          `json:"event_type"`
+	AggregateID      string          `json:"aggregate_id"`
+	AggregateType    string          `json:"aggregate_type"`
+	AggregateVersion int             `json:"aggregate_version"`
+	OccurredAt       time.Time       `json:"occurred_at"`
+	CorrelationID    string          `json:"correlation_id,omitempty"`
+	CausationID      string          `json:"causation_id,omitempty"`
+	Producer         string          `json:"producer"`
+	SchemaVersion    int             `json:"schema_version"`
+	Data             json.RawMessage `json:"data"`
+}
+
+// DecodeEnvelope reads the envelope of a message. Messages published before
+// envelopes existed carry the bare payload, they are wrapped in an envelope
+// without metadata.
+func DecodeEnvelope(msg *Message) (*Envelope, error) {
+	if msg.ContentType != "application/json" {
+		return &Envelope{
+			EventID:   msg.ID,
+			EventType: msg.EventType,
+			Data:      msg.Body,
+		}, nil
+	}
+
+	envelope := Envelope{}
+	if err := json.Unmarshal(msg.Body, &envelope); err != nil {
+		return nil, err
+	}
+
+	return &envelope, nil
+}
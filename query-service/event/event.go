@@ -1,6 +1,8 @@
 package event
 
 import (
+	"time"
+
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -37,3 +39,32 @@ func declareRandomQueue(ch *amqp.Channel) (amqp.Queue, error) {
 		nil,   // args
 	)
 }
+
+// declareDelayQueue declares a queue without consumers, messages expire after
+// ttl and are dead-lettered straight back into the target queue.
+func declareDelayQueue(ch *amqp.Channel, name string, targetQueue string, ttl time.Duration) (amqp.Queue, error) {
+	return ch.QueueDeclare(
+		name,  // name?
+		true,  // durable?
+		false, // delete when unuse?
+		false, // exclusive?
+		false, // no-wait?
+		amqp.Table{
+			"x-message-ttl":             ttl.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": targetQueue,
+		},
+	)
+}
+
+func declareDeadLetterExchange(ch *amqp.Channel, exchangeName string) error {
+	return ch.ExchangeDeclare(
+		exchangeName, // name exchange
+		"fanout",     // type
+		true,         // durable?
+		false,        // auto-delete?
+		false,        // use-internally?
+		false,        // no-wait?
+		nil,          // arguments
+	)
+}
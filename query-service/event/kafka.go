@@ -0,0 +1,176 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaConfig locates the cluster and names the topics. Every exchange maps
+// to one topic, TopicPrefix + exchange, and the consumers of one GroupID
+// share its partitions.
+type KafkaConfig struct {
+	Brokers     []string
+	TopicPrefix string
+	GroupID     string
+}
+
+func (c KafkaConfig) TopicName(exchangeName string) string {
+	return c.TopicPrefix + exchangeName
+}
+
+// PingKafka checks that one of the brokers accepts connections.
+func PingKafka(ctx context.Context, brokers []string) error {
+	var err error
+	for _, broker := range brokers {
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			return conn.Close()
+		}
+	}
+
+	if err == nil {
+		err = errors.New("no Kafka broker configured")
+	}
+	return err
+}
+
+// kafkaConsumer reads a topic as a member of a consumer group. Offsets are
+// committed once a message is handled, so a restart resumes after the last
+// handled message. Kafka has no delayed redelivery, failures are retried in
+// process and then written to the dead-letter topic.
+type kafkaConsumer struct {
+	config       KafkaConfig
+	exchangeName string
+	policy       RetryPolicy
+
+	handlePayload Handler
+	deadLetters   *kafka.Writer
+}
+
+func NewConsumerKafka(config KafkaConfig, exchangeName string, policy RetryPolicy, handlePayload Handler) (Consumer, error) {
+	if len(config.Brokers) == 0 {
+		return nil, errors.New("no Kafka broker configured")
+	}
+	if config.GroupID == "" {
+		return nil, errors.New("no Kafka consumer group configured")
+	}
+
+	return &kafkaConsumer{
+		config:        config,
+		exchangeName:  exchangeName,
+		policy:        policy,
+		handlePayload: handlePayload,
+		deadLetters: &kafka.Writer{
+			Addr:                   kafka.TCP(config.Brokers...),
+			Topic:                  deadLetterQueueName(config.TopicName(exchangeName)),
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+	}, nil
+}
+
+// Listen consumes the topic until the reader is closed. The reader rejoins
+// the group and reconnects to the brokers on its own.
+func (c *kafkaConsumer) Listen(eventTypes []string) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  c.config.Brokers,
+		GroupID:  c.config.GroupID,
+		Topic:    c.config.TopicName(c.exchangeName),
+		MinBytes: 1,
+		MaxBytes: 10e6,
+	})
+	defer reader.Close()
+	defer c.deadLetters.Close()
+
+	wanted := map[string]bool{}
+	for _, eventType := range eventTypes {
+		wanted[eventType] = true
+	}
+
+	fmt.Printf("Waiting for messages [Topic, Group] [%s, %s]\n", reader.Config().Topic, c.config.GroupID)
+
+	ctx := context.Background()
+	for {
+		kafkaMsg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			return err
+		}
+
+		msg := fromKafkaMessage(&kafkaMsg)
+
+		// the topic carries every event of the exchange
+		if wanted[msg.EventType] {
+			log.Println("[MSG]:", kafkaMsg.Topic, msg.EventType)
+
+			retries, err := handleWithRetry(c.policy, c.handlePayload, msg)
+			if err != nil {
+				c.deadLetter(ctx, &kafkaMsg, msg, retries, err)
+			}
+		}
+
+		for {
+			err = reader.CommitMessages(ctx, kafkaMsg)
+			if err == nil {
+				break
+			}
+
+			log.Println("Can't commit Kafka offset:", err)
+			time.Sleep(time.Second)
+		}
+	}
+}
+
+// deadLetter writes the message to the dead-letter topic, it is not
+// committed before the write succeeds.
+func (c *kafkaConsumer) deadLetter(ctx context.Context, kafkaMsg *kafka.Message, msg *Message, retries int, handleErr error) {
+	headers := append([]kafka.Header{}, kafkaMsg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: headerRetryCount, Value: []byte(strconv.Itoa(retries))},
+		kafka.Header{Key: headerOriginalRoutingKey, Value: []byte(msg.EventType)},
+		kafka.Header{Key: headerError, Value: []byte(handleErr.Error())},
+		kafka.Header{Key: headerDeadLetteredAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+
+	for {
+		err := c.deadLetters.WriteMessages(ctx, kafka.Message{
+			Key:     kafkaMsg.Key,
+			Headers: headers,
+			Time:    kafkaMsg.Time,
+			Value:   kafkaMsg.Value,
+		})
+		if err == nil {
+			return
+		}
+
+		log.Println("Can't dead-letter message:", err)
+		time.Sleep(time.Second)
+	}
+}
+
+func fromKafkaMessage(kafkaMsg *kafka.Message) *Message {
+	msg := &Message{
+		Key:       string(kafkaMsg.Key),
+		Timestamp: kafkaMsg.Time,
+		Body:      kafkaMsg.Value,
+	}
+
+	for _, header := range kafkaMsg.Headers {
+		switch header.Key {
+		case "content-type":
+			msg.ContentType = string(header.Value)
+		case "event-type":
+			msg.EventType = string(header.Value)
+		case "message-id":
+			msg.ID = string(header.Value)
+		}
+	}
+
+	return msg
+}
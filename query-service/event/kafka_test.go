@@ -0,0 +1,70 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestFromKafkaMessage(t *testing.T) {
+	occurredAt := time.Date(2022, 11, 5, 10, 0, 0, 0, time.UTC)
+
+	msg := fromKafkaMessage(&kafka.Message{
+		Key: []byte("article-1"),
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte("application/json")},
+			{Key: "event-type", Value: []byte("article.created")},
+			{Key: "message-id", Value: []byte("event-1")},
+			{Key: "correlation-id", Value: []byte("request-1")},
+		},
+		Time:  occurredAt,
+		Value: []byte(`{"event_id":"event-1"}`),
+	})
+
+	if msg.Key != "article-1" || msg.EventType != "article.created" || msg.ID != "event-1" || msg.ContentType != "application/json" {
+		t.Errorf("message = %+v, want the key and headers of the Kafka message", msg)
+	}
+	if !msg.Timestamp.Equal(occurredAt) {
+		t.Errorf("timestamp = %s, want %s", msg.Timestamp, occurredAt)
+	}
+	if string(msg.Body) != `{"event_id":"event-1"}` {
+		t.Errorf("body = %s, want the message value", msg.Body)
+	}
+}
+
+func TestNewConsumerKafkaConfig(t *testing.T) {
+	handle := func(msg *Message) error { return nil }
+
+	tests := []struct {
+		name    string
+		config  KafkaConfig
+		wantErr bool
+	}{
+		{"no brokers", KafkaConfig{GroupID: "query-service"}, true},
+		{"no group", KafkaConfig{Brokers: []string{"kafka:9092"}}, true},
+		{"complete", KafkaConfig{Brokers: []string{"kafka:9092"}, GroupID: "query-service"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConsumerKafka(tt.config, "articles", DefaultRetryPolicy, handle)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKafkaDeadLetterTopic(t *testing.T) {
+	config := KafkaConfig{Brokers: []string{"kafka:9092"}, GroupID: "query-service", TopicPrefix: "staging."}
+
+	consumer, err := NewConsumerKafka(config, "articles", DefaultRetryPolicy, func(msg *Message) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if topic := consumer.(*kafkaConsumer).deadLetters.Topic; topic != "staging.articles.dead" {
+		t.Errorf("dead-letter topic = %q, want %q", topic, "staging.articles.dead")
+	}
+}
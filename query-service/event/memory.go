@@ -0,0 +1,134 @@
+package event
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrBrokerClosed = errors.New("memory broker was closed")
+
+// MemoryBroker delivers published messages to the consumers of the same
+// process, it needs no broker and keeps nothing across restarts.
+type MemoryBroker struct {
+	mu          sync.Mutex
+	closed      bool
+	subscribers []chan *Message
+	deadLetters []*Message
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Publish hands msg to every consumer, it blocks while a consumer is busy.
+func (b *MemoryBroker) Publish(msg *Message) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrBrokerClosed
+	}
+	subscribers := append([]chan *Message{}, b.subscribers...)
+	b.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber <- msg
+	}
+
+	return nil
+}
+
+// DeadLetters returns the messages that kept failing.
+func (b *MemoryBroker) DeadLetters() []*Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]*Message{}, b.deadLetters...)
+}
+
+// Subscribed reports how many consumers listen, messages published before a
+// consumer listens never reach it.
+func (b *MemoryBroker) Subscribed() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscribers)
+}
+
+func (b *MemoryBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.closed = true
+	for _, subscriber := range b.subscribers {
+		close(subscriber)
+	}
+}
+
+func (b *MemoryBroker) subscribe() (chan *Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+
+	subscriber := make(chan *Message, 64)
+	b.subscribers = append(b.subscribers, subscriber)
+
+	return subscriber, nil
+}
+
+func (b *MemoryBroker) deadLetter(msg *Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.deadLetters = append(b.deadLetters, msg)
+}
+
+type memoryConsumer struct {
+	broker *MemoryBroker
+	policy RetryPolicy
+
+	handlePayload Handler
+}
+
+func NewConsumerMemory(broker *MemoryBroker, policy RetryPolicy, handlePayload Handler) (Consumer, error) {
+	if broker == nil {
+		return nil, errors.New("no memory broker")
+	}
+
+	return &memoryConsumer{
+		broker:        broker,
+		policy:        policy,
+		handlePayload: handlePayload,
+	}, nil
+}
+
+// Listen consumes until the broker is closed.
+func (c *memoryConsumer) Listen(eventTypes []string) error {
+	messages, err := c.broker.subscribe()
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, eventType := range eventTypes {
+		wanted[eventType] = true
+	}
+
+	for msg := range messages {
+		if !wanted[msg.EventType] {
+			continue
+		}
+
+		if _, err := handleWithRetry(c.policy, c.handlePayload, msg); err != nil {
+			c.broker.deadLetter(msg)
+		}
+	}
+
+	return ErrBrokerClosed
+}
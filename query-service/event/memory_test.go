@@ -0,0 +1,159 @@
+package event
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testPolicy = RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}
+
+// listenMemory runs a memory consumer for eventTypes and waits until it is
+// subscribed, the returned channel yields the result of Listen.
+func listenMemory(t *testing.T, broker *MemoryBroker, eventTypes []string, handle Handler) <-chan error {
+	t.Helper()
+
+	consumer, err := NewConsumerMemory(broker, testPolicy, handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Listen(eventTypes)
+	}()
+
+	for deadline := time.Now().Add(time.Second); ; {
+		if broker.Subscribed() > 0 {
+			return done
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("consumer did not subscribe")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// closeMemory closes the broker and waits until the consumer handled every
+// message it was given.
+func closeMemory(t *testing.T, broker *MemoryBroker, done <-chan error) {
+	t.Helper()
+
+	broker.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrBrokerClosed) {
+			t.Errorf("Listen returned %v, want %v", err, ErrBrokerClosed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("consumer did not stop")
+	}
+}
+
+func TestMemoryConsumerDeliversListedEvents(t *testing.T) {
+	broker := NewMemoryBroker()
+
+	var mu sync.Mutex
+	handled := []string{}
+	done := listenMemory(t, broker, []string{"article.created", "article.updated"}, func(msg *Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		handled = append(handled, msg.ID)
+		return nil
+	})
+
+	for _, msg := range []*Message{
+		{ID: "event-1", EventType: "article.created"},
+		{ID: "event-2", EventType: "comment.created"},
+		{ID: "event-3", EventType: "article.updated"},
+	} {
+		if err := broker.Publish(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closeMemory(t, broker, done)
+
+	if len(handled) != 2 || handled[0] != "event-1" || handled[1] != "event-3" {
+		t.Errorf("handled %v, want [event-1 event-3] in order", handled)
+	}
+	if deadLetters := broker.DeadLetters(); len(deadLetters) != 0 {
+		t.Errorf("dead-lettered %d messages, want none", len(deadLetters))
+	}
+}
+
+func TestMemoryConsumerRetriesFailures(t *testing.T) {
+	broker := NewMemoryBroker()
+
+	attempts := 0
+	done := listenMemory(t, broker, []string{"article.created"}, func(msg *Message) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("read model unavailable")
+		}
+		return nil
+	})
+
+	if err := broker.Publish(&Message{ID: "event-1", EventType: "article.created"}); err != nil {
+		t.Fatal(err)
+	}
+
+	closeMemory(t, broker, done)
+
+	if attempts != 2 {
+		t.Errorf("handled %d times, want a retry after the failure", attempts)
+	}
+	if deadLetters := broker.DeadLetters(); len(deadLetters) != 0 {
+		t.Errorf("dead-lettered %d messages, want none", len(deadLetters))
+	}
+}
+
+func TestMemoryConsumerDeadLetters(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"retries exhausted", errors.New("read model unavailable"), testPolicy.MaxRetries + 1},
+		{"permanent failure", Permanent(errors.New("malformed payload")), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := NewMemoryBroker()
+
+			attempts := 0
+			done := listenMemory(t, broker, []string{"article.created"}, func(msg *Message) error {
+				attempts++
+				return tt.err
+			})
+
+			if err := broker.Publish(&Message{ID: "event-1", EventType: "article.created"}); err != nil {
+				t.Fatal(err)
+			}
+
+			closeMemory(t, broker, done)
+
+			if attempts != tt.attempts {
+				t.Errorf("handled %d times, want %d", attempts, tt.attempts)
+			}
+
+			deadLetters := broker.DeadLetters()
+			if len(deadLetters) != 1 || deadLetters[0].ID != "event-1" {
+				t.Errorf("dead letters = %v, want event-1", deadLetters)
+			}
+		})
+	}
+}
+
+func TestMemoryBrokerRefusesAfterClose(t *testing.T) {
+	broker := NewMemoryBroker()
+	broker.Close()
+
+	if err := broker.Publish(&Message{ID: "event-1"}); !errors.Is(err, ErrBrokerClosed) {
+		t.Errorf("Publish returned %v, want %v", err, ErrBrokerClosed)
+	}
+}
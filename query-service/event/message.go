@@ -0,0 +1,55 @@
+package event
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	TransportRabbitmq = "rabbitmq"
+	TransportKafka    = "kafka"
+	TransportMemory   = "memory"
+)
+
+// Message is an event as delivered by any transport. Key is the aggregate
+// id on transports that partition by it.
+type Message struct {
+	ID          string
+	EventType   string
+	Key         string
+	ContentType string
+	Timestamp   time.Time
+	Body        []byte
+}
+
+type Handler func(msg *Message) error
+
+// Consumer hands the messages of the listed event types to its handler
+// until the transport is closed.
+type Consumer interface {
+	Listen(eventTypes []string) error
+}
+
+// handleWithRetry calls handle until it succeeds, fails permanently or runs
+// out of retries, waiting between attempts as the policy says. It is used by
+// the transports that can't schedule a redelivery in the broker.
+func handleWithRetry(policy RetryPolicy, handle Handler, msg *Message) (int, error) {
+	retries := 0
+	for {
+		err := handle(msg)
+		if err == nil {
+			return retries, nil
+		}
+
+		var permanent *PermanentError
+		if errors.As(err, &permanent) || retries >= policy.MaxRetries {
+			log.Printf("[DLQ]: %s after %d attempts: %s", msg.EventType, retries+1, err)
+			return retries, err
+		}
+
+		retries++
+		log.Printf("[RETRY]: %s attempt %d in %s: %s", msg.EventType, retries, policy.delay(retries), err)
+		time.Sleep(policy.delay(retries))
+	}
+}
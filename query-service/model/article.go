@@ -2,12 +2,45 @@ package model
 
 import "time"
 
+// Article statuses, only published articles are public.
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
 type Article struct {
 	ID        string    `bson:"_id,omitempty" json:"id"`
 	Uuid      string    `bson:"uuid" json:"uuid"`
+	Slug      string    `bson:"slug,omitempty" json:"slug"`
 	Author    string    `bson:"author" json:"author"`
 	Title     string    `bson:"title" json:"title"`
 	Body      string    `bson:"body" json:"body"`
+	Tags      []string  `bson:"tags" json:"tags"`
+	Category  string    `bson:"category,omitempty" json:"category"`
+	Status    string    `bson:"status" json:"status"`
+	Version   int       `bson:"version" json:"version"`
+	Revision  int       `bson:"revision" json:"revision"`
+	UpdatedBy string    `bson:"updated_by" json:"updated_by"`
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+
+	// set while the article is in the trash
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	// set while an unpublished article is scheduled to be published
+	PublishAt *time.Time `bson:"publish_at,omitempty" json:"publish_at,omitempty"`
+	// every slug the article held, the current one included
+	Slugs []string `bson:"slugs,omitempty" json:"slugs,omitempty"`
+	// visible comments, kept up to date by the comment projection
+	CommentCount int64 `bson:"comment_count,omitempty" json:"comment_count"`
+
+	// matched fragments per field, only set by a search backend
+	Highlights map[string][]string `bson:"-" json:"highlights,omitempty"`
+}
+
+// IsPublished reports whether the article is public. Articles projected
+// before the editorial workflow existed have no status and are published.
+func (a *Article) IsPublished() bool {
+	return a.Status == StatusPublished || a.Status == ""
 }
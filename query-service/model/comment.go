@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Comment statuses, hidden comments were taken down by a moderator.
+const (
+	CommentVisible = "visible"
+	CommentHidden  = "hidden"
+)
+
+// Comment is a comment on an article or a reply to another comment. ThreadID
+// is the top-level comment of the thread, Depth is 0 for a top-level comment.
+type Comment struct {
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	Uuid      string    `bson:"uuid" json:"uuid"`
+	ArticleID string    `bson:"article_id" json:"article_id"`
+	ParentID  string    `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	ThreadID  string    `bson:"thread_id" json:"thread_id"`
+	Depth     int       `bson:"depth" json:"depth"`
+	Author    string    `bson:"author" json:"author"`
+	Body      string    `bson:"body" json:"body"`
+	Status    string    `bson:"status" json:"status"`
+	Version   int       `bson:"version" json:"version"`
+	UpdatedBy string    `bson:"updated_by" json:"updated_by"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+
+	// set once the comment is deleted
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	// set by the last moderation
+	ModeratedBy      string `bson:"moderated_by,omitempty" json:"moderated_by,omitempty"`
+	ModerationReason string `bson:"moderation_reason,omitempty" json:"moderation_reason,omitempty"`
+}
+
+// IsCounted reports whether the comment counts towards the comments of its
+// article: visible and not deleted.
+func (c *Comment) IsCounted() bool {
+	return c.Status != CommentHidden && c.DeletedAt == nil
+}
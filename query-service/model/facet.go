@@ -0,0 +1,8 @@
+package model
+
+// Facet is a tag or a category with the number of published articles that
+// have it.
+type Facet struct {
+	Name  string `bson:"name" json:"name"`
+	Count int64  `bson:"count" json:"count"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type ProcessedEvent struct {
+	EventID          string    `bson:"_id" json:"event_id"`
+	EventType        string    `bson:"event_type" json:"event_type"`
+	AggregateID      string    `bson:"aggregate_id" json:"aggregate_id"`
+	AggregateVersion int       `bson:"aggregate_version" json:"aggregate_version"`
+	ProcessedAt      time.Time `bson:"processed_at" json:"processed_at"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Revision is the snapshot of an article right after one of its creates or
+// updates, revisions are never changed once written.
+type Revision struct {
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	Uuid      string    `bson:"uuid" json:"uuid"`
+	Revision  int       `bson:"revision" json:"revision"`
+	Version   int       `bson:"version" json:"version"`
+	Author    string    `bson:"author" json:"author"`
+	Editor    string    `bson:"editor" json:"editor"`
+	Title     string    `bson:"title" json:"title"`
+	Body      string    `bson:"body" json:"body"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// RevisionOf is the revision an article snapshot was written as.
+func RevisionOf(article *Article) *Revision {
+	return &Revision{
+		Uuid:      article.Uuid,
+		Revision:  article.Revision,
+		Version:   article.Version,
+		Author:    article.Author,
+		Editor:    article.UpdatedBy,
+		Title:     article.Title,
+		Body:      article.Body,
+		CreatedAt: article.UpdatedAt,
+	}
+}
@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: article_query.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ArticleQuery_GetSingle_FullMethodName = "/article.ArticleQuery/GetSingle"
+	ArticleQuery_GetBySlug_FullMethodName = "/article.ArticleQuery/GetBySlug"
+	ArticleQuery_GetList_FullMethodName   = "/article.ArticleQuery/GetList"
+)
+
+// ArticleQueryClient is the client API for ArticleQuery service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ArticleQueryClient interface {
+	GetSingle(ctx context.Context, in *GetSingleArticleRequest, opts ...grpc.CallOption) (*GetSingleArticleResponse, error)
+	// GetBySlug resolves the current and the retired slugs of an article, the
+	// article carries its current slug
+	GetBySlug(ctx context.Context, in *GetArticleBySlugRequest, opts ...grpc.CallOption) (*GetSingleArticleResponse, error)
+	GetList(ctx context.Context, in *GetListArticleRequest, opts ...grpc.CallOption) (*GetListArticleResponse, error)
+}
+
+type articleQueryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewArticleQueryClient(cc grpc.ClientConnInterface) ArticleQueryClient {
+	return &articleQueryClient{cc}
+}
+
+func (c *articleQueryClient) GetSingle(ctx context.Context, in *GetSingleArticleRequest, opts ...grpc.CallOption) (*GetSingleArticleResponse, error) {
+	out := new(GetSingleArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleQuery_GetSingle_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleQueryClient) GetBySlug(ctx context.Context, in *GetArticleBySlugRequest, opts ...grpc.CallOption) (*GetSingleArticleResponse, error) {
+	out := new(GetSingleArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleQuery_GetBySlug_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleQueryClient) GetList(ctx context.Context, in *GetListArticleRequest, opts ...grpc.CallOption) (*GetListArticleResponse, error) {
+	out := new(GetListArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleQuery_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ArticleQueryServer is the server API for ArticleQuery service.
+// All implementations must embed UnimplementedArticleQueryServer
+// for forward compatibility
+type ArticleQueryServer interface {
+	GetSingle(context.Context, *GetSingleArticleRequest) (*GetSingleArticleResponse, error)
+	// GetBySlug resolves the current and the retired slugs of an article, the
+	// article carries its current slug
+	GetBySlug(context.Context, *GetArticleBySlugRequest) (*GetSingleArticleResponse, error)
+	GetList(context.Context, *GetListArticleRequest) (*GetListArticleResponse, error)
+	mustEmbedUnimplementedArticleQueryServer()
+}
+
+// UnimplementedArticleQueryServer must be embedded to have forward compatible implementations.
+type UnimplementedArticleQueryServer struct {
+}
+
+func (UnimplementedArticleQueryServer) GetSingle(context.Context, *GetSingleArticleRequest) (*GetSingleArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSingle not implemented")
+}
+func (UnimplementedArticleQueryServer) GetBySlug(context.Context, *GetArticleBySlugRequest) (*GetSingleArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBySlug not implemented")
+}
+func (UnimplementedArticleQueryServer) GetList(context.Context, *GetListArticleRequest) (*GetListArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedArticleQueryServer) mustEmbedUnimplementedArticleQueryServer() {}
+
+// UnsafeArticleQueryServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ArticleQueryServer will
+// result in compilation errors.
+type UnsafeArticleQueryServer interface {
+	mustEmbedUnimplementedArticleQueryServer()
+}
+
+func RegisterArticleQueryServer(s grpc.ServiceRegistrar, srv ArticleQueryServer) {
+	s.RegisterService(&ArticleQuery_ServiceDesc, srv)
+}
+
+func _ArticleQuery_GetSingle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSingleArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleQueryServer).GetSingle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleQuery_GetSingle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleQueryServer).GetSingle(ctx, req.(*GetSingleArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleQuery_GetBySlug_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArticleBySlugRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleQueryServer).GetBySlug(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleQuery_GetBySlug_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleQueryServer).GetBySlug(ctx, req.(*GetArticleBySlugRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleQuery_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetListArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleQueryServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleQuery_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleQueryServer).GetList(ctx, req.(*GetListArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ArticleQuery_ServiceDesc is the grpc.ServiceDesc for ArticleQuery service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ArticleQuery_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "article.ArticleQuery",
+	HandlerType: (*ArticleQueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSingle",
+			Handler:    _ArticleQuery_GetSingle_Handler,
+		},
+		{
+			MethodName: "GetBySlug",
+			Handler:    _ArticleQuery_GetBySlug_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _ArticleQuery_GetList_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "article_query.proto",
+}
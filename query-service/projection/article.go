@@ -0,0 +1,339 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Adhiana46/query-service/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ArticleProjection applies article events to the read model. Writes are
+// guarded by the article version, so replaying an event that is already
+// reflected in the read model does nothing.
+type ArticleProjection interface {
+	// Created inserts the article and reports false when it was already
+	// projected.
+	Created(ctx context.Context, article *model.Article) (bool, error)
+	// Updated upserts the article snapshot and returns the article it
+	// replaced, nil when the article was not projected yet. It reports false
+	// when the stored article is already at the same or a newer version, so
+	// an out-of-order article.updated is never applied.
+	Updated(ctx context.Context, article *model.Article) (*model.Article, bool, error)
+	// StatusChanged moves the article through the editorial workflow to the
+	// status of the snapshot and reports whether it did, a missing article is
+	// inserted from the snapshot.
+	StatusChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error)
+	// ScheduleChanged sets the scheduled publication of the snapshot, a nil
+	// PublishAt cancels it, and reports whether it did. Publishing and
+	// deleting the article drop the schedule too, creates and updates leave
+	// it alone. A missing article is inserted from the snapshot.
+	ScheduleChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error)
+	// SlugChanged moves the article to the slug of the snapshot and reports
+	// whether it did. The previous slugs stay in the history of the article,
+	// updates only set the slug of an article they insert. A missing article
+	// is inserted from the snapshot.
+	SlugChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error)
+	// Deleted moves the article to the trash and reports whether it did. An
+	// article that is not projected yet is inserted from the snapshot, so the
+	// delete survives an article.created delivered after it.
+	Deleted(ctx context.Context, article *model.Article, deletedAt time.Time) (bool, error)
+	// Restored takes the article out of the trash and reports whether it
+	// did, a missing article is inserted from the snapshot like Deleted.
+	Restored(ctx context.Context, article *model.Article, restoredAt time.Time) (bool, error)
+	// Purged removes the article for good and reports whether it did. It
+	// reports ErrNotProjected while the article is missing, the event is
+	// retried until the article.created ahead of it was projected.
+	Purged(ctx context.Context, uuid string, version int) (bool, error)
+}
+
+// ErrNotProjected is reported for an event about an article the read model
+// doesn't hold yet, the event must be retried.
+var ErrNotProjected = errors.New("article is not projected yet")
+
+type articleProjectionMongo struct {
+	collection *mongo.Collection
+}
+
+func NewArticleProjectionMongo(collection *mongo.Collection) ArticleProjection {
+	return &articleProjectionMongo{
+		collection: collection,
+	}
+}
+
+func (p *articleProjectionMongo) Created(ctx context.Context, article *model.Article) (bool, error) {
+	result, err := p.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": article.Uuid},
+		bson.D{
+			{Key: "$setOnInsert", Value: snapshotFields(article, map[string]bool{"slugs": true})},
+			// an event that overtook the create inserted the article at a
+			// later slug, the first one keeps resolving to it
+			{Key: "$addToSet", Value: bson.D{
+				{Key: "slugs", Value: bson.D{{Key: "$each", Value: slugList(article.Slug)}}},
+			}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		// a concurrent upsert of the same article already inserted it
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
+func (p *articleProjectionMongo) Updated(ctx context.Context, article *model.Article) (*model.Article, bool, error) {
+	var previous model.Article
+
+	set := bson.D{
+		{Key: "author", Value: article.Author},
+		{Key: "title", Value: article.Title},
+		{Key: "body", Value: article.Body},
+		{Key: "tags", Value: tagList(article.Tags)},
+		{Key: "category", Value: article.Category},
+		{Key: "version", Value: article.Version},
+		{Key: "revision", Value: article.Revision},
+		{Key: "updated_by", Value: article.UpdatedBy},
+		{Key: "updated_at", Value: article.UpdatedAt},
+	}
+	// an update without a status leaves the stored one alone
+	if article.Status != "" {
+		set = append(set, bson.E{Key: "status", Value: article.Status})
+	}
+
+	err := p.collection.FindOneAndUpdate(
+		ctx,
+		olderThan(article.Uuid, article.Version),
+		bson.D{
+			{Key: "$set", Value: set},
+			{Key: "$setOnInsert", Value: bson.D{
+				{Key: "slug", Value: article.Slug},
+				{Key: "slugs", Value: slugList(article.Slug)},
+				{Key: "created_at", Value: article.CreatedAt},
+			}},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(&previous)
+	if err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			// nothing to replace, the article was inserted
+			return nil, true, nil
+		case mongo.IsDuplicateKeyError(err):
+			// the upsert hit the unique uuid index: a newer version is stored
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return &previous, true, nil
+}
+
+func (p *articleProjectionMongo) StatusChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error) {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: article.Status},
+			{Key: "version", Value: article.Version},
+			{Key: "updated_at", Value: changedAt},
+		}},
+	}
+	if article.Status == model.StatusPublished {
+		update = append(update, bson.E{Key: "$unset", Value: bson.D{
+			{Key: "publish_at", Value: ""},
+		}})
+	}
+
+	return p.upsert(ctx, article, update)
+}
+
+func (p *articleProjectionMongo) ScheduleChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error) {
+	set := bson.D{
+		{Key: "version", Value: article.Version},
+		{Key: "updated_at", Value: changedAt},
+	}
+
+	if article.PublishAt != nil {
+		set = append(set, bson.E{Key: "publish_at", Value: *article.PublishAt})
+	}
+
+	update := bson.D{{Key: "$set", Value: set}}
+	if article.PublishAt == nil {
+		update = append(update, bson.E{Key: "$unset", Value: bson.D{
+			{Key: "publish_at", Value: ""},
+		}})
+	}
+
+	return p.upsert(ctx, article, update)
+}
+
+func (p *articleProjectionMongo) SlugChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error) {
+	return p.upsert(ctx, article, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "slug", Value: article.Slug},
+			{Key: "version", Value: article.Version},
+			{Key: "updated_at", Value: changedAt},
+		}},
+		{Key: "$addToSet", Value: bson.D{
+			{Key: "slugs", Value: article.Slug},
+		}},
+	})
+}
+
+func (p *articleProjectionMongo) Deleted(ctx context.Context, article *model.Article, deletedAt time.Time) (bool, error) {
+	return p.upsert(ctx, article, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "version", Value: article.Version},
+			{Key: "updated_at", Value: deletedAt},
+			{Key: "deleted_at", Value: deletedAt},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "publish_at", Value: ""},
+		}},
+	})
+}
+
+func (p *articleProjectionMongo) Restored(ctx context.Context, article *model.Article, restoredAt time.Time) (bool, error) {
+	return p.upsert(ctx, article, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "version", Value: article.Version},
+			{Key: "updated_at", Value: restoredAt},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "deleted_at", Value: ""},
+		}},
+	})
+}
+
+func (p *articleProjectionMongo) Purged(ctx context.Context, uuid string, version int) (bool, error) {
+	result, err := p.collection.DeleteOne(ctx, olderThan(uuid, version))
+	if err != nil {
+		return false, err
+	}
+
+	if result.DeletedCount > 0 {
+		return true, nil
+	}
+
+	// nothing newer is stored either: the article wasn't projected yet
+	stored, err := p.collection.CountDocuments(ctx, bson.M{"uuid": uuid})
+	if err != nil {
+		return false, err
+	}
+	if stored == 0 {
+		return false, ErrNotProjected
+	}
+
+	return false, nil
+}
+
+// upsert applies the update when the stored article is older than the
+// snapshot, and inserts the snapshot with the update applied when the article
+// is not stored yet. A newer stored version makes the insert hit the unique
+// uuid index, the update is then not applied.
+func (p *articleProjectionMongo) upsert(ctx context.Context, article *model.Article, update bson.D) (bool, error) {
+	update = append(update, bson.E{
+		Key:   "$setOnInsert",
+		Value: snapshotFields(article, updatedFields(update)),
+	})
+
+	result, err := p.collection.UpdateOne(
+		ctx,
+		olderThan(article.Uuid, article.Version),
+		update,
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return result.ModifiedCount > 0 || result.UpsertedCount > 0, nil
+}
+
+// snapshotFields are the fields an article is inserted with, without the
+// ones in skip the update sets itself.
+func snapshotFields(article *model.Article, skip map[string]bool) bson.D {
+	fields := bson.D{
+		{Key: "uuid", Value: article.Uuid},
+		{Key: "slug", Value: article.Slug},
+		{Key: "slugs", Value: slugList(article.Slug)},
+		{Key: "author", Value: article.Author},
+		{Key: "title", Value: article.Title},
+		{Key: "body", Value: article.Body},
+		{Key: "tags", Value: tagList(article.Tags)},
+		{Key: "category", Value: article.Category},
+		{Key: "status", Value: article.Status},
+		{Key: "version", Value: article.Version},
+		{Key: "revision", Value: article.Revision},
+		{Key: "updated_by", Value: article.UpdatedBy},
+		{Key: "created_at", Value: article.CreatedAt},
+		{Key: "updated_at", Value: article.UpdatedAt},
+	}
+
+	result := bson.D{}
+	for _, field := range fields {
+		if !skip[field.Key] {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// updatedFields are the fields the operators of an update write, MongoDB
+// refuses to $setOnInsert them as well.
+func updatedFields(update bson.D) map[string]bool {
+	fields := map[string]bool{}
+	for _, operator := range update {
+		values, ok := operator.Value.(bson.D)
+		if !ok {
+			continue
+		}
+		for _, field := range values {
+			fields[field.Key] = true
+		}
+	}
+
+	return fields
+}
+
+// olderThan matches the article only when the stored document has not seen
+// the given version yet. Documents projected before versions existed have no
+// version field and always match.
+func olderThan(uuid string, version int) bson.M {
+	return bson.M{
+		"uuid": uuid,
+		"$or": bson.A{
+			bson.M{"version": bson.M{"$lt": version}},
+			bson.M{"version": bson.M{"$exists": false}},
+		},
+	}
+}
+
+// slugList is the slug history of a new article, empty for articles created
+// before slugs existed.
+func slugList(slug string) []string {
+	if slug == "" {
+		return []string{}
+	}
+
+	return []string{slug}
+}
+
+// tagList stores an article without tags with an empty list rather than null.
+func tagList(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
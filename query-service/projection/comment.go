@@ -0,0 +1,220 @@
+package projection
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Adhiana46/query-service/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// countedComments matches the comments an article counts: visible and not
+// deleted.
+var countedComments = bson.M{
+	"status":     model.CommentVisible,
+	"deleted_at": nil,
+}
+
+// CommentProjection applies comment events to the comments collection. Every
+// comment event carries the whole comment, writes are guarded by the comment
+// version like the article writes are.
+type CommentProjection interface {
+	Created(ctx context.Context, comment *model.Comment) error
+	// Updated replaces the stored comment with a newer snapshot, inserting
+	// it when the comment was not projected yet, and reports whether it did.
+	Updated(ctx context.Context, comment *model.Comment) (bool, error)
+	// ArticlePurged removes every comment of the article.
+	ArticlePurged(ctx context.Context, articleID string) error
+	// Recount stores the number of counted comments on the article and
+	// returns the article, nil when the article is not projected. The count
+	// is recounted rather than incremented, so a replayed or out-of-order
+	// event can't skew it.
+	Recount(ctx context.Context, articleID string) (*model.Article, error)
+	// Count returns the number of counted comments of the article.
+	Count(ctx context.Context, articleID string) (int64, error)
+	// RecountAll recounts the comments of every article.
+	RecountAll(ctx context.Context) error
+}
+
+// EnsureCommentIndexes creates the unique uuid index the projection relies on
+// and the indexes the comment pages are read with, it is safe to call on
+// every startup.
+func EnsureCommentIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "uuid", Value: 1}},
+			Options: options.Index().SetName("comments_uuid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "article_id", Value: 1}, {Key: "depth", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("comments_article_depth_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "thread_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("comments_thread_created"),
+		},
+	})
+
+	return err
+}
+
+type commentProjectionMongo struct {
+	comments *mongo.Collection
+	articles *mongo.Collection
+}
+
+func NewCommentProjectionMongo(comments, articles *mongo.Collection) CommentProjection {
+	return &commentProjectionMongo{
+		comments: comments,
+		articles: articles,
+	}
+}
+
+func (p *commentProjectionMongo) Created(ctx context.Context, comment *model.Comment) error {
+	_, err := p.comments.UpdateOne(
+		ctx,
+		bson.M{"uuid": comment.Uuid},
+		bson.D{
+			{Key: "$setOnInsert", Value: commentDocument(comment)},
+		},
+		options.Update().SetUpsert(true),
+	)
+	// a concurrent upsert of the same comment already inserted it
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (p *commentProjectionMongo) Updated(ctx context.Context, comment *model.Comment) (bool, error) {
+	result, err := p.comments.UpdateOne(
+		ctx,
+		olderThan(comment.Uuid, comment.Version),
+		bson.D{
+			{Key: "$set", Value: commentDocument(comment)},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		// the upsert hit the unique uuid index: a newer version is stored
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return result.ModifiedCount > 0 || result.UpsertedCount > 0, nil
+}
+
+func (p *commentProjectionMongo) ArticlePurged(ctx context.Context, articleID string) error {
+	_, err := p.comments.DeleteMany(ctx, bson.M{"article_id": articleID})
+
+	return err
+}
+
+func (p *commentProjectionMongo) Recount(ctx context.Context, articleID string) (*model.Article, error) {
+	count, err := p.Count(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var article model.Article
+
+	err = p.articles.FindOneAndUpdate(
+		ctx,
+		bson.M{"uuid": articleID},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "comment_count", Value: count}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&article)
+	if err != nil {
+		// the article is purged or not projected yet
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+func (p *commentProjectionMongo) Count(ctx context.Context, articleID string) (int64, error) {
+	filter := bson.M{"article_id": articleID}
+	for key, value := range countedComments {
+		filter[key] = value
+	}
+
+	return p.comments.CountDocuments(ctx, filter)
+}
+
+// RecountAll groups the counted comments by article and replaces the stored
+// counts with the result, articles left out of the result count none.
+func (p *commentProjectionMongo) RecountAll(ctx context.Context) error {
+	cursor, err := p.comments.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: countedComments}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$article_id"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	articleIDs := bson.A{}
+	for cursor.Next(ctx) {
+		var group struct {
+			ArticleID string `bson:"_id"`
+			Count     int64  `bson:"count"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+
+		_, err := p.articles.UpdateOne(
+			ctx,
+			bson.M{"uuid": group.ArticleID},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "comment_count", Value: group.Count}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		articleIDs = append(articleIDs, group.ArticleID)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	_, err = p.articles.UpdateMany(
+		ctx,
+		bson.M{"uuid": bson.M{"$nin": articleIDs}, "comment_count": bson.M{"$gt": 0}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "comment_count", Value: 0}}}},
+	)
+	return err
+}
+
+// commentDocument is the stored form of a comment snapshot, a comment that
+// was never deleted stores a null deleted_at.
+func commentDocument(comment *model.Comment) bson.D {
+	return bson.D{
+		{Key: "uuid", Value: comment.Uuid},
+		{Key: "article_id", Value: comment.ArticleID},
+		{Key: "parent_id", Value: comment.ParentID},
+		{Key: "thread_id", Value: comment.ThreadID},
+		{Key: "depth", Value: comment.Depth},
+		{Key: "author", Value: comment.Author},
+		{Key: "body", Value: comment.Body},
+		{Key: "status", Value: comment.Status},
+		{Key: "version", Value: comment.Version},
+		{Key: "updated_by", Value: comment.UpdatedBy},
+		{Key: "created_at", Value: comment.CreatedAt},
+		{Key: "updated_at", Value: comment.UpdatedAt},
+		{Key: "deleted_at", Value: comment.DeletedAt},
+		{Key: "moderated_by", Value: comment.ModeratedBy},
+		{Key: "moderation_reason", Value: comment.ModerationReason},
+	}
+}
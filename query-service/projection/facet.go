@@ -0,0 +1,154 @@
+package projection
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// publicArticles matches the articles the facets count: published and not in
+// the trash. Documents projected before the editorial workflow have no status
+// and are published.
+var publicArticles = bson.M{
+	"deleted_at": nil,
+	"status":     bson.M{"$in": bson.A{model.StatusPublished, nil}},
+}
+
+// FacetProjection keeps the number of public articles per tag and category.
+// Counts are recounted from the articles rather than incremented, so a
+// replayed or out-of-order event can't skew them.
+type FacetProjection interface {
+	// Refresh recounts the given tags and categories, a name no public
+	// article has anymore is dropped.
+	Refresh(ctx context.Context, tags []string, categories []string) error
+	// RefreshAll recounts every tag and category.
+	RefreshAll(ctx context.Context) error
+}
+
+// EnsureFacetIndexes creates the unique name index of a facet collection, it
+// is safe to call on every startup.
+func EnsureFacetIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName("facets_name").SetUnique(true),
+	})
+
+	return err
+}
+
+type facetProjectionMongo struct {
+	articles   *mongo.Collection
+	tags       *mongo.Collection
+	categories *mongo.Collection
+}
+
+func NewFacetProjectionMongo(articles, tags, categories *mongo.Collection) FacetProjection {
+	return &facetProjectionMongo{
+		articles:   articles,
+		tags:       tags,
+		categories: categories,
+	}
+}
+
+func (p *facetProjectionMongo) Refresh(ctx context.Context, tags []string, categories []string) error {
+	for _, tag := range tags {
+		if err := p.recount(ctx, p.tags, "tags", tag); err != nil {
+			return err
+		}
+	}
+
+	for _, category := range categories {
+		if category == "" {
+			continue
+		}
+		if err := p.recount(ctx, p.categories, "category", category); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *facetProjectionMongo) RefreshAll(ctx context.Context) error {
+	err := p.recountAll(ctx, p.tags, bson.D{{Key: "$unwind", Value: "$tags"}}, "$tags")
+	if err != nil {
+		return err
+	}
+
+	return p.recountAll(ctx, p.categories, bson.D{{Key: "$match", Value: bson.M{"category": bson.M{"$nin": bson.A{"", nil}}}}}, "$category")
+}
+
+// recount stores the number of public articles whose field holds name.
+func (p *facetProjectionMongo) recount(ctx context.Context, facets *mongo.Collection, field string, name string) error {
+	filter := bson.M{field: name}
+	for key, value := range publicArticles {
+		filter[key] = value
+	}
+
+	count, err := p.articles.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		_, err = facets.DeleteOne(ctx, bson.M{"name": name})
+		return err
+	}
+
+	_, err = facets.UpdateOne(
+		ctx,
+		bson.M{"name": name},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "count", Value: count}}}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// recountAll groups the public articles by the facet field and replaces the
+// stored counts with the result.
+func (p *facetProjectionMongo) recountAll(ctx context.Context, facets *mongo.Collection, stage bson.D, field string) error {
+	cursor, err := p.articles.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: publicArticles}},
+		stage,
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: field},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	names := bson.A{}
+	for cursor.Next(ctx) {
+		var group struct {
+			Name  string `bson:"_id"`
+			Count int64  `bson:"count"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+
+		_, err := facets.UpdateOne(
+			ctx,
+			bson.M{"name": group.Name},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "count", Value: group.Count}}}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, group.Name)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	_, err = facets.DeleteMany(ctx, bson.M{"name": bson.M{"$nin": names}})
+	return err
+}
@@ -0,0 +1,59 @@
+package projection
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/query-service/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Inbox remembers the ids of the events that were already projected, so a
+// redelivered event is acknowledged without being applied twice.
+type Inbox interface {
+	Seen(ctx context.Context, eventID string) (bool, error)
+	MarkProcessed(ctx context.Context, processed *model.ProcessedEvent) error
+}
+
+// EnsureInboxIndexes expires inbox entries after the retention period, events
+// older than that are not redelivered by the broker anymore.
+func EnsureInboxIndexes(ctx context.Context, collection *mongo.Collection, retention time.Duration) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "processed_at", Value: 1}},
+		Options: options.Index().
+			SetName("processed_events_ttl").
+			SetExpireAfterSeconds(int32(retention.Seconds())),
+	})
+
+	return err
+}
+
+type inboxMongo struct {
+	collection *mongo.Collection
+}
+
+func NewInboxMongo(collection *mongo.Collection) Inbox {
+	return &inboxMongo{
+		collection: collection,
+	}
+}
+
+func (i *inboxMongo) Seen(ctx context.Context, eventID string) (bool, error) {
+	count, err := i.collection.CountDocuments(ctx, bson.M{"_id": eventID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (i *inboxMongo) MarkProcessed(ctx context.Context, processed *model.ProcessedEvent) error {
+	_, err := i.collection.InsertOne(ctx, processed)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
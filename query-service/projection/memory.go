@@ -0,0 +1,563 @@
+package projection
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/query-service/model"
+)
+
+// ArticleProjectionMemory keeps the read model in a map, it applies the same
+// version rules as the Mongo projection and is read by the memory query.
+type ArticleProjectionMemory struct {
+	mu       sync.RWMutex
+	articles map[string]model.Article
+}
+
+func NewArticleProjectionMemory() *ArticleProjectionMemory {
+	return &ArticleProjectionMemory{
+		articles: map[string]model.Article{},
+	}
+}
+
+func (p *ArticleProjectionMemory) Created(ctx context.Context, article *model.Article) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if stored, ok := p.articles[article.Uuid]; ok {
+		// an event that overtook the create inserted the article at a
+		// later slug, the first one keeps resolving to it
+		if article.Slug != "" && !hasSlug(stored.Slugs, article.Slug) {
+			stored.Slugs = append(append([]string{}, stored.Slugs...), article.Slug)
+			p.articles[article.Uuid] = stored
+		}
+		return false, nil
+	}
+
+	created := *article
+	created.PublishAt = nil
+	created.Slugs = slugList(article.Slug)
+	p.articles[article.Uuid] = created
+
+	return true, nil
+}
+
+func (p *ArticleProjectionMemory) Updated(ctx context.Context, article *model.Article) (*model.Article, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous, ok := p.articles[article.Uuid]
+	if !ok {
+		updated := *article
+		updated.PublishAt = nil
+		updated.Slugs = slugList(article.Slug)
+		p.articles[article.Uuid] = updated
+		return nil, true, nil
+	}
+
+	if previous.Version >= article.Version {
+		return nil, false, nil
+	}
+
+	updated := *article
+	updated.CreatedAt = previous.CreatedAt
+	updated.PublishAt = previous.PublishAt
+	updated.Slug = previous.Slug
+	updated.Slugs = previous.Slugs
+	updated.CommentCount = previous.CommentCount
+	if updated.Status == "" {
+		updated.Status = previous.Status
+	}
+	p.articles[article.Uuid] = updated
+
+	return &previous, true, nil
+}
+
+func (p *ArticleProjectionMemory) StatusChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.stored(article)
+	if !ok {
+		return false, nil
+	}
+
+	stored.Status = article.Status
+	stored.Version = article.Version
+	stored.UpdatedAt = changedAt
+	if article.Status == model.StatusPublished {
+		stored.PublishAt = nil
+	}
+	p.articles[article.Uuid] = stored
+
+	return true, nil
+}
+
+func (p *ArticleProjectionMemory) ScheduleChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.stored(article)
+	if !ok {
+		return false, nil
+	}
+
+	stored.Version = article.Version
+	stored.UpdatedAt = changedAt
+	stored.PublishAt = article.PublishAt
+	p.articles[article.Uuid] = stored
+
+	return true, nil
+}
+
+func (p *ArticleProjectionMemory) SlugChanged(ctx context.Context, article *model.Article, changedAt time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.stored(article)
+	if !ok {
+		return false, nil
+	}
+
+	stored.Slug = article.Slug
+	stored.Version = article.Version
+	stored.UpdatedAt = changedAt
+	if !hasSlug(stored.Slugs, article.Slug) {
+		// a copy, the history may be shared with an article handed out before
+		stored.Slugs = append(append([]string{}, stored.Slugs...), article.Slug)
+	}
+	p.articles[article.Uuid] = stored
+
+	return true, nil
+}
+
+func (p *ArticleProjectionMemory) Deleted(ctx context.Context, article *model.Article, deletedAt time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.stored(article)
+	if !ok {
+		return false, nil
+	}
+
+	stored.Version = article.Version
+	stored.UpdatedAt = deletedAt
+	stored.DeletedAt = &deletedAt
+	stored.PublishAt = nil
+	p.articles[article.Uuid] = stored
+
+	return true, nil
+}
+
+func (p *ArticleProjectionMemory) Restored(ctx context.Context, article *model.Article, restoredAt time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.stored(article)
+	if !ok {
+		return false, nil
+	}
+
+	stored.Version = article.Version
+	stored.UpdatedAt = restoredAt
+	stored.DeletedAt = nil
+	p.articles[article.Uuid] = stored
+
+	return true, nil
+}
+
+func (p *ArticleProjectionMemory) Purged(ctx context.Context, uuid string, version int) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.articles[uuid]
+	if !ok {
+		return false, ErrNotProjected
+	}
+	if stored.Version >= version {
+		return false, nil
+	}
+
+	delete(p.articles, uuid)
+
+	return true, nil
+}
+
+// stored returns the article an event applies to, the snapshot when the
+// article is not stored yet. It reports false when the stored article is at
+// the same or a newer version.
+func (p *ArticleProjectionMemory) stored(article *model.Article) (model.Article, bool) {
+	stored, ok := p.articles[article.Uuid]
+	if !ok {
+		inserted := *article
+		inserted.PublishAt = nil
+		inserted.DeletedAt = nil
+		inserted.Slugs = slugList(article.Slug)
+		return inserted, true
+	}
+
+	return stored, stored.Version < article.Version
+}
+
+func hasSlug(slugs []string, slug string) bool {
+	for _, held := range slugs {
+		if held == slug {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Find returns a copy of the stored article, trashed ones included.
+func (p *ArticleProjectionMemory) Find(uuid string) (*model.Article, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	article, ok := p.articles[uuid]
+	if !ok {
+		return nil, false
+	}
+
+	return &article, true
+}
+
+// setCommentCount stores the comment count of the article, it returns a copy
+// of the article and false when the article is not stored.
+func (p *ArticleProjectionMemory) setCommentCount(uuid string, count int64) (*model.Article, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.articles[uuid]
+	if !ok {
+		return nil, false
+	}
+
+	stored.CommentCount = count
+	p.articles[uuid] = stored
+
+	return &stored, true
+}
+
+// Articles returns a copy of every stored article, trashed ones included, in
+// no particular order.
+func (p *ArticleProjectionMemory) Articles() []*model.Article {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	articles := make([]*model.Article, 0, len(p.articles))
+	for _, article := range p.articles {
+		article := article
+		articles = append(articles, &article)
+	}
+
+	return articles
+}
+
+type inboxMemory struct {
+	mu        sync.Mutex
+	processed map[string]model.ProcessedEvent
+}
+
+func NewInboxMemory() Inbox {
+	return &inboxMemory{
+		processed: map[string]model.ProcessedEvent{},
+	}
+}
+
+func (i *inboxMemory) Seen(ctx context.Context, eventID string) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	_, ok := i.processed[eventID]
+	return ok, nil
+}
+
+func (i *inboxMemory) MarkProcessed(ctx context.Context, processed *model.ProcessedEvent) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, ok := i.processed[processed.EventID]; !ok {
+		i.processed[processed.EventID] = *processed
+	}
+
+	return nil
+}
+
+// RevisionProjectionMemory keeps the revisions per article, it is read by the
+// memory revision query.
+type RevisionProjectionMemory struct {
+	mu        sync.RWMutex
+	revisions map[string]map[int]model.Revision
+}
+
+func NewRevisionProjectionMemory() *RevisionProjectionMemory {
+	return &RevisionProjectionMemory{
+		revisions: map[string]map[int]model.Revision{},
+	}
+}
+
+func (p *RevisionProjectionMemory) Recorded(ctx context.Context, revision *model.Revision) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	revisions, ok := p.revisions[revision.Uuid]
+	if !ok {
+		revisions = map[int]model.Revision{}
+		p.revisions[revision.Uuid] = revisions
+	}
+
+	if _, ok := revisions[revision.Revision]; !ok {
+		revisions[revision.Revision] = *revision
+	}
+
+	return nil
+}
+
+func (p *RevisionProjectionMemory) Purged(ctx context.Context, uuid string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.revisions, uuid)
+
+	return nil
+}
+
+// Revisions returns a copy of the revisions of an article, newest first.
+func (p *RevisionProjectionMemory) Revisions(uuid string) []*model.Revision {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	revisions := make([]*model.Revision, 0, len(p.revisions[uuid]))
+	for _, revision := range p.revisions[uuid] {
+		revision := revision
+		revisions = append(revisions, &revision)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+
+	return revisions
+}
+
+// FacetProjectionMemory counts the tags and categories of the in-memory read
+// model, it is read by the memory facet query.
+type FacetProjectionMemory struct {
+	mu         sync.RWMutex
+	articles   *ArticleProjectionMemory
+	tags       map[string]int64
+	categories map[string]int64
+}
+
+func NewFacetProjectionMemory(articles *ArticleProjectionMemory) *FacetProjectionMemory {
+	return &FacetProjectionMemory{
+		articles:   articles,
+		tags:       map[string]int64{},
+		categories: map[string]int64{},
+	}
+}
+
+func (p *FacetProjectionMemory) Refresh(ctx context.Context, tags []string, categories []string) error {
+	tagCounts, categoryCounts := p.count()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, tag := range tags {
+		setCount(p.tags, tag, tagCounts[tag])
+	}
+	for _, category := range categories {
+		setCount(p.categories, category, categoryCounts[category])
+	}
+
+	return nil
+}
+
+func (p *FacetProjectionMemory) RefreshAll(ctx context.Context) error {
+	tagCounts, categoryCounts := p.count()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.tags = tagCounts
+	p.categories = categoryCounts
+
+	return nil
+}
+
+// Tags returns the stored tag counts, most used first.
+func (p *FacetProjectionMemory) Tags() []*model.Facet {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return sortedFacets(p.tags)
+}
+
+// Categories returns the stored category counts, most used first.
+func (p *FacetProjectionMemory) Categories() []*model.Facet {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return sortedFacets(p.categories)
+}
+
+// count counts the tags and categories of the public articles.
+func (p *FacetProjectionMemory) count() (map[string]int64, map[string]int64) {
+	tags := map[string]int64{}
+	categories := map[string]int64{}
+	for _, article := range p.articles.Articles() {
+		if article.DeletedAt != nil || !article.IsPublished() {
+			continue
+		}
+		for _, tag := range article.Tags {
+			tags[tag]++
+		}
+		if article.Category != "" {
+			categories[article.Category]++
+		}
+	}
+
+	return tags, categories
+}
+
+func setCount(counts map[string]int64, name string, count int64) {
+	if count == 0 {
+		delete(counts, name)
+		return
+	}
+
+	counts[name] = count
+}
+
+func sortedFacets(counts map[string]int64) []*model.Facet {
+	facets := make([]*model.Facet, 0, len(counts))
+	for name, count := range counts {
+		facets = append(facets, &model.Facet{Name: name, Count: count})
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+
+	return facets
+}
+
+// CommentProjectionMemory keeps the comments in a map and their counts on the
+// articles of the in-memory read model, it is read by the memory comment
+// query.
+type CommentProjectionMemory struct {
+	mu       sync.RWMutex
+	articles *ArticleProjectionMemory
+	comments map[string]model.Comment
+}
+
+func NewCommentProjectionMemory(articles *ArticleProjectionMemory) *CommentProjectionMemory {
+	return &CommentProjectionMemory{
+		articles: articles,
+		comments: map[string]model.Comment{},
+	}
+}
+
+func (p *CommentProjectionMemory) Created(ctx context.Context, comment *model.Comment) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.comments[comment.Uuid]; !ok {
+		p.comments[comment.Uuid] = *comment
+	}
+
+	return nil
+}
+
+func (p *CommentProjectionMemory) Updated(ctx context.Context, comment *model.Comment) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.comments[comment.Uuid]
+	if ok && stored.Version >= comment.Version {
+		return false, nil
+	}
+
+	p.comments[comment.Uuid] = *comment
+
+	return true, nil
+}
+
+func (p *CommentProjectionMemory) ArticlePurged(ctx context.Context, articleID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for uuid, comment := range p.comments {
+		if comment.ArticleID == articleID {
+			delete(p.comments, uuid)
+		}
+	}
+
+	return nil
+}
+
+func (p *CommentProjectionMemory) Recount(ctx context.Context, articleID string) (*model.Article, error) {
+	count, err := p.Count(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	article, ok := p.articles.setCommentCount(articleID, count)
+	if !ok {
+		return nil, nil
+	}
+
+	return article, nil
+}
+
+func (p *CommentProjectionMemory) Count(ctx context.Context, articleID string) (int64, error) {
+	var count int64
+	for _, comment := range p.Comments(articleID) {
+		if comment.IsCounted() {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (p *CommentProjectionMemory) RecountAll(ctx context.Context) error {
+	for _, article := range p.articles.Articles() {
+		if _, err := p.Recount(ctx, article.Uuid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Comments returns a copy of the comments of an article, deleted ones
+// included, oldest first.
+func (p *CommentProjectionMemory) Comments(articleID string) []*model.Comment {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	comments := []*model.Comment{}
+	for _, comment := range p.comments {
+		if comment.ArticleID != articleID {
+			continue
+		}
+		comment := comment
+		comments = append(comments, &comment)
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].Uuid < comments[j].Uuid
+	})
+
+	return comments
+}
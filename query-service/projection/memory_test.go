@@ -0,0 +1,170 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/query-service/model"
+)
+
+func testArticle(version int) *model.Article {
+	createdAt := time.Date(2022, 11, 5, 10, 0, 0, 0, time.UTC)
+
+	return &model.Article{
+		Uuid:      "article-1",
+		Slug:      "first-title",
+		Author:    "alice",
+		Title:     "First title",
+		Body:      "Body",
+		Tags:      []string{"go"},
+		Status:    model.StatusPublished,
+		Version:   version,
+		Revision:  1,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func TestDeletedAheadOfCreated(t *testing.T) {
+	ctx := context.Background()
+	articles := NewArticleProjectionMemory()
+
+	deletedAt := time.Date(2022, 11, 6, 10, 0, 0, 0, time.UTC)
+	deleted, err := articles.Deleted(ctx, testArticle(2), deletedAt)
+	if err != nil || !deleted {
+		t.Fatalf("Deleted = %v, %v, want the snapshot inserted in the trash", deleted, err)
+	}
+
+	// the create that was retried lands afterwards
+	created, err := articles.Created(ctx, testArticle(1))
+	if err != nil || created {
+		t.Fatalf("Created = %v, %v, want the trashed article kept", created, err)
+	}
+
+	stored, ok := articles.Find("article-1")
+	if !ok || stored.DeletedAt == nil || stored.Version != 2 || stored.Title != "First title" {
+		t.Fatalf("stored %+v, want the trashed snapshot at version 2", stored)
+	}
+
+	// the delete is stale once a newer version is stored
+	restored, err := articles.Restored(ctx, testArticle(3), deletedAt)
+	if err != nil || !restored {
+		t.Fatalf("Restored = %v, %v", restored, err)
+	}
+	deleted, err = articles.Deleted(ctx, testArticle(2), deletedAt)
+	if err != nil || deleted {
+		t.Errorf("stale Deleted = %v, %v, want it ignored", deleted, err)
+	}
+}
+
+func TestRestoredAheadOfCreated(t *testing.T) {
+	ctx := context.Background()
+	articles := NewArticleProjectionMemory()
+
+	restoredAt := time.Date(2022, 11, 7, 10, 0, 0, 0, time.UTC)
+	restored, err := articles.Restored(ctx, testArticle(3), restoredAt)
+	if err != nil || !restored {
+		t.Fatalf("Restored = %v, %v, want the snapshot inserted", restored, err)
+	}
+
+	deleted, err := articles.Deleted(ctx, testArticle(2), restoredAt)
+	if err != nil || deleted {
+		t.Errorf("Deleted = %v, %v after the restore, want it ignored", deleted, err)
+	}
+
+	stored, ok := articles.Find("article-1")
+	if !ok || stored.DeletedAt != nil || stored.Version != 3 {
+		t.Errorf("stored %+v, want the restored article at version 3", stored)
+	}
+}
+
+func TestPurgedAheadOfCreated(t *testing.T) {
+	ctx := context.Background()
+	articles := NewArticleProjectionMemory()
+
+	if _, err := articles.Purged(ctx, "article-1", 3); !errors.Is(err, ErrNotProjected) {
+		t.Fatalf("Purged = %v, want %v so the event is retried", err, ErrNotProjected)
+	}
+
+	if _, err := articles.Created(ctx, testArticle(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := articles.Purged(ctx, "article-1", 3)
+	if err != nil || !purged {
+		t.Fatalf("retried Purged = %v, %v, want the article removed", purged, err)
+	}
+	if _, ok := articles.Find("article-1"); ok {
+		t.Error("the purged article is still stored")
+	}
+}
+
+func TestStatusChangedAheadOfCreated(t *testing.T) {
+	ctx := context.Background()
+	articles := NewArticleProjectionMemory()
+
+	archived := testArticle(2)
+	archived.Status = model.StatusArchived
+	changed, err := articles.StatusChanged(ctx, archived, time.Date(2022, 11, 6, 10, 0, 0, 0, time.UTC))
+	if err != nil || !changed {
+		t.Fatalf("StatusChanged = %v, %v, want the snapshot inserted", changed, err)
+	}
+
+	if created, err := articles.Created(ctx, testArticle(1)); err != nil || created {
+		t.Fatalf("Created = %v, %v, want the archived article kept", created, err)
+	}
+
+	stored, ok := articles.Find("article-1")
+	if !ok || stored.Status != model.StatusArchived || stored.Version != 2 {
+		t.Errorf("stored %+v, want the archived article at version 2", stored)
+	}
+}
+
+func TestScheduleChangedAheadOfCreated(t *testing.T) {
+	ctx := context.Background()
+	articles := NewArticleProjectionMemory()
+
+	publishAt := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	scheduled := testArticle(2)
+	scheduled.Status = model.StatusDraft
+	scheduled.PublishAt = &publishAt
+	changed, err := articles.ScheduleChanged(ctx, scheduled, time.Date(2022, 11, 6, 10, 0, 0, 0, time.UTC))
+	if err != nil || !changed {
+		t.Fatalf("ScheduleChanged = %v, %v, want the snapshot inserted", changed, err)
+	}
+
+	if created, err := articles.Created(ctx, testArticle(1)); err != nil || created {
+		t.Fatalf("Created = %v, %v, want the scheduled article kept", created, err)
+	}
+
+	stored, ok := articles.Find("article-1")
+	if !ok || stored.PublishAt == nil || !stored.PublishAt.Equal(publishAt) || stored.Version != 2 {
+		t.Errorf("stored %+v, want the schedule at version 2", stored)
+	}
+}
+
+func TestSlugChangedAheadOfCreated(t *testing.T) {
+	ctx := context.Background()
+	articles := NewArticleProjectionMemory()
+
+	renamed := testArticle(2)
+	renamed.Slug = "second-title"
+	changed, err := articles.SlugChanged(ctx, renamed, time.Date(2022, 11, 6, 10, 0, 0, 0, time.UTC))
+	if err != nil || !changed {
+		t.Fatalf("SlugChanged = %v, %v, want the snapshot inserted", changed, err)
+	}
+
+	if created, err := articles.Created(ctx, testArticle(1)); err != nil || created {
+		t.Fatalf("Created = %v, %v, want the renamed article kept", created, err)
+	}
+
+	stored, ok := articles.Find("article-1")
+	if !ok || stored.Slug != "second-title" || stored.Version != 2 {
+		t.Fatalf("stored %+v, want the new slug at version 2", stored)
+	}
+	if !hasSlug(stored.Slugs, "first-title") || !hasSlug(stored.Slugs, "second-title") {
+		t.Errorf("slugs = %v, want both slugs kept", stored.Slugs)
+	}
+}
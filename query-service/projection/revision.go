@@ -0,0 +1,71 @@
+package projection
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RevisionProjection keeps the revision history of the articles. A revision
+// is written once, recording it again does nothing.
+type RevisionProjection interface {
+	Recorded(ctx context.Context, revision *model.Revision) error
+	// Purged removes every revision of the article.
+	Purged(ctx context.Context, uuid string) error
+}
+
+// EnsureRevisionIndexes creates the unique (uuid, revision) index the
+// projection relies on, it is safe to call on every startup.
+func EnsureRevisionIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "uuid", Value: 1}, {Key: "revision", Value: -1}},
+		Options: options.Index().SetName("revisions_uuid_revision").SetUnique(true),
+	})
+
+	return err
+}
+
+type revisionProjectionMongo struct {
+	collection *mongo.Collection
+}
+
+func NewRevisionProjectionMongo(collection *mongo.Collection) RevisionProjection {
+	return &revisionProjectionMongo{
+		collection: collection,
+	}
+}
+
+func (p *revisionProjectionMongo) Recorded(ctx context.Context, revision *model.Revision) error {
+	_, err := p.collection.UpdateOne(
+		ctx,
+		bson.M{"uuid": revision.Uuid, "revision": revision.Revision},
+		bson.D{
+			{Key: "$setOnInsert", Value: bson.D{
+				{Key: "uuid", Value: revision.Uuid},
+				{Key: "revision", Value: revision.Revision},
+				{Key: "version", Value: revision.Version},
+				{Key: "author", Value: revision.Author},
+				{Key: "editor", Value: revision.Editor},
+				{Key: "title", Value: revision.Title},
+				{Key: "body", Value: revision.Body},
+				{Key: "created_at", Value: revision.CreatedAt},
+			}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	// a concurrent upsert of the same revision already inserted it
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (p *revisionProjectionMongo) Purged(ctx context.Context, uuid string) error {
+	_, err := p.collection.DeleteMany(ctx, bson.M{"uuid": uuid})
+
+	return err
+}
@@ -2,17 +2,13 @@ package query
 
 import (
 	"context"
-	"crypto/md5"
-	"encoding/hex"
-	"encoding/json"
-	"fmt"
 	"log"
-	"time"
+	"regexp"
+	"strings"
 
 	"github.com/Adhiana46/query-service/dto"
 	"github.com/Adhiana46/query-service/model"
-	"github.com/go-redis/redis/v9"
-	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -20,51 +16,172 @@ import (
 
 type ArticleQuery interface {
 	GetSingle(ctx context.Context, reqDto dto.RequestSingleArticle) (*model.Article, error)
-	GetList(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, error)
+	// GetBySlug finds the article holding the slug now or before.
+	GetBySlug(ctx context.Context, reqDto dto.RequestArticleBySlug) (*model.Article, error)
+	GetList(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error)
+	// GetTrash lists the deleted articles that were not purged yet.
+	GetTrash(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error)
+}
+
+// EnsureArticleIndexes creates the indexes GetList relies on, it is safe to
+// call on every startup.
+func EnsureArticleIndexes(ctx context.Context, collection *mongo.Collection) error {
+	err := removeDuplicateArticles(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "uuid", Value: 1}},
+			Options: options.Index().SetName("articles_uuid").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "body", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("articles_text").
+				SetWeights(bson.D{
+					{Key: "title", Value: 5},
+					{Key: "body", Value: 1},
+				}),
+		},
+		{
+			Keys:    bson.D{{Key: "slugs", Value: 1}},
+			Options: options.Index().SetName("articles_slugs"),
+		},
+		{
+			Keys:    bson.D{{Key: "author", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("articles_author_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("articles_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("articles_status_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("articles_tags_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "category", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("articles_category_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetName("articles_deleted_at").SetSparse(true),
+		},
+	})
+
+	return err
+}
+
+// removeDuplicateArticles keeps the newest document of every uuid, so the
+// unique uuid index can be built on a collection that was projected twice.
+func removeDuplicateArticles(ctx context.Context, collection *mongo.Collection) error {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "version", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$uuid"},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var duplicate struct {
+			Ids []interface{} `bson:"ids"`
+		}
+		if err := cursor.Decode(&duplicate); err != nil {
+			return err
+		}
+
+		_, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": duplicate.Ids[1:]}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// isAuthorPrefix reports whether the author filter is a prefix search.
+func isAuthorPrefix(author string) bool {
+	return strings.HasSuffix(author, "*")
+}
+
+// normalizeTag matches the tags and categories as command-service stores
+// them, lowercased and trimmed.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func normalizeTags(tags []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// requiredTags are the tags an article must all have, the tag filter and the
+// tags_all filter combined.
+func requiredTags(reqDto dto.RequestListArticle) []string {
+	return normalizeTags(append([]string{reqDto.Tag}, reqDto.TagsAll...))
 }
 
 type articleQueryMongo struct {
-	mongoDb    *mongo.Client
-	rabbitConn *amqp.Connection
-	rds        *redis.Client
+	mongoDb *mongo.Client
 }
 
-func NewArticleQueryMongo(mongoDb *mongo.Client, rabbitConn *amqp.Connection, rds *redis.Client) ArticleQuery {
+func NewArticleQueryMongo(mongoDb *mongo.Client) ArticleQuery {
 	return &articleQueryMongo{
-		mongoDb:    mongoDb,
-		rabbitConn: rabbitConn,
-		rds:        rds,
+		mongoDb: mongoDb,
 	}
 }
 
 func (query *articleQueryMongo) GetSingle(ctx context.Context, reqDto dto.RequestSingleArticle) (*model.Article, error) {
-	cacheKey := fmt.Sprintf("article-%s", reqDto.Uuid)
-	cacheResult, err := query.rds.Get(ctx, cacheKey).Result()
 	var article model.Article
 
-	// get from cache, ignore error
-	if err == nil && cacheResult != "" {
-		err = json.Unmarshal([]byte(cacheResult), &article)
-		if err == nil {
-			return &article, nil
-		}
-	}
-
-	// get from mongodb collection
 	collection := query.mongoDb.Database("articles").Collection("articles")
 
-	err = collection.FindOne(ctx, bson.M{"uuid": reqDto.Uuid}).Decode(&article)
+	filter := withVisibility(bson.M{"uuid": reqDto.Uuid, "deleted_at": nil}, reqDto.Viewer, "")
+
+	err := collection.FindOne(ctx, filter).Decode(&article)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store to cache
-	articleJson, err := json.Marshal(article)
-	if err != nil {
+	return &article, nil
+}
+
+func (query *articleQueryMongo) GetBySlug(ctx context.Context, reqDto dto.RequestArticleBySlug) (*model.Article, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
 		return nil, err
 	}
 
-	err = query.rds.Set(ctx, cacheKey, string(articleJson), 10*time.Minute).Err()
+	var article model.Article
+
+	collection := query.mongoDb.Database("articles").Collection("articles")
+
+	filter := withVisibility(bson.M{"slugs": reqDto.Slug, "deleted_at": nil}, reqDto.Viewer, "")
+
+	err := collection.FindOne(ctx, filter).Decode(&article)
 	if err != nil {
 		return nil, err
 	}
@@ -72,35 +189,114 @@ func (query *articleQueryMongo) GetSingle(ctx context.Context, reqDto dto.Reques
 	return &article, nil
 }
 
-func (query *articleQueryMongo) GetList(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, error) {
-	// cache-key based on reqDto json -> md5
-	reqDtoJson, _ := json.Marshal(reqDto)
-	hash := md5.Sum(reqDtoJson)
-	cacheKey := fmt.Sprintf("article-list-%s", hex.EncodeToString(hash[:]))
+func (query *articleQueryMongo) GetList(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
 
-	var articles []*model.Article
+	filter := articleFilter(reqDto)
+	filter["deleted_at"] = nil
+	withVisibility(filter, reqDto.Viewer, reqDto.Status)
 
-	// get from cache, ignore error
-	cacheResult, err := query.rds.Get(ctx, cacheKey).Result()
-	if err == nil && cacheResult != "" {
-		err = json.Unmarshal([]byte(cacheResult), &articles)
-		if err == nil {
-			return articles, nil
+	opts := options.Find()
+	if reqDto.Query != "" {
+		// most relevant first when searching
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+		opts.SetSort(bson.D{
+			{Key: "score", Value: bson.M{"$meta": "textScore"}},
+			{Key: "created_at", Value: -1},
+		})
+	} else {
+		opts.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	}
+
+	return query.find(ctx, filter, opts, reqDto)
+}
+
+func (query *articleQueryMongo) GetTrash(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	filter := articleFilter(reqDto)
+	filter["deleted_at"] = bson.M{"$ne": nil}
+	withVisibility(filter, reqDto.Viewer, reqDto.Status)
+
+	// most recently deleted first
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+
+	return query.find(ctx, filter, opts, reqDto)
+}
+
+// articleFilter matches the search, author, tags, category and creation date
+// of the request.
+func articleFilter(reqDto dto.RequestListArticle) bson.M {
+	filter := bson.M{}
+	if reqDto.Query != "" {
+		filter["$text"] = bson.M{"$search": reqDto.Query}
+	}
+	if reqDto.Author != "" {
+		if isAuthorPrefix(reqDto.Author) {
+			prefix := strings.TrimSuffix(reqDto.Author, "*")
+			filter["author"] = bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+		} else {
+			filter["author"] = reqDto.Author
 		}
 	}
 
+	tags := bson.M{}
+	if required := requiredTags(reqDto); len(required) > 0 {
+		tags["$all"] = required
+	}
+	if anyOf := normalizeTags(reqDto.TagsAny); len(anyOf) > 0 {
+		tags["$in"] = anyOf
+	}
+	if len(tags) > 0 {
+		filter["tags"] = tags
+	}
+	if category := normalizeTag(reqDto.Category); category != "" {
+		filter["category"] = category
+	}
+
+	createdAt := bson.M{}
+	if !reqDto.CreatedFrom.IsZero() {
+		createdAt["$gte"] = reqDto.CreatedFrom
+	}
+	if !reqDto.CreatedTo.IsZero() {
+		createdAt["$lte"] = reqDto.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	return filter
+}
+
+// find counts the matching articles and reads the requested page.
+func (query *articleQueryMongo) find(ctx context.Context, filter bson.M, opts *options.FindOptions, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
 	collection := query.mongoDb.Database("articles").Collection("articles")
 
-	opts := options.Find()
-	opts.SetSort(bson.D{{"created_at", -1}})
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Println("Count articles error:", err)
+		return nil, 0, err
+	}
 
-	cursor, err := collection.Find(ctx, bson.D{}, opts)
+	opts.SetSkip(int64((reqDto.Page - 1) * reqDto.Limit))
+	opts.SetLimit(int64(reqDto.Limit))
+
+	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
 		log.Println("Get list of articles error:", err)
-		return nil, err
+		return nil, 0, err
 	}
 	defer cursor.Close(ctx)
 
+	articles := []*model.Article{}
 	for cursor.Next(ctx) {
 		var article model.Article
 
@@ -112,16 +308,5 @@ func (query *articleQueryMongo) GetList(ctx context.Context, reqDto dto.RequestL
 		}
 	}
 
-	// Store to cache
-	articlesJson, err := json.Marshal(articles)
-	if err != nil {
-		return nil, err
-	}
-
-	err = query.rds.Set(ctx, cacheKey, string(articlesJson), 10*time.Minute).Err()
-	if err != nil {
-		return nil, err
-	}
-
-	return articles, nil
+	return articles, total, nil
 }
@@ -0,0 +1,158 @@
+package query
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Adhiana46/query-service/cache"
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	articleCacheTTL = 10 * time.Minute
+
+	// the comment count is cached apart from the article, the snapshots
+	// the article events cache know nothing of comments
+	commentCountKeyBase = "article-comments-"
+)
+
+type articleListCache struct {
+	Articles []*model.Article `json:"articles"`
+	Total    int64            `json:"total"`
+}
+
+// articleQueryCache answers from the cache and falls back to the primary
+// query, the projection keeps the cached articles and lists up to date.
+type articleQueryCache struct {
+	cache   cache.Cache
+	primary ArticleQuery
+}
+
+func NewArticleQueryCache(c cache.Cache, primary ArticleQuery) ArticleQuery {
+	return &articleQueryCache{
+		cache:   c,
+		primary: primary,
+	}
+}
+
+func (query *articleQueryCache) GetSingle(ctx context.Context, reqDto dto.RequestSingleArticle) (*model.Article, error) {
+	cacheKey := fmt.Sprintf("article-%s", reqDto.Uuid)
+
+	// get from cache, ignore error
+	var article model.Article
+	cacheResult, err := query.cache.Get(ctx, cacheKey)
+	if err == nil && len(cacheResult) > 0 {
+		err = json.Unmarshal(cacheResult, &article)
+		if err == nil {
+			// the cache holds unpublished articles too
+			if !reqDto.Viewer.CanSee(&article) {
+				return nil, mongo.ErrNoDocuments
+			}
+
+			// without a cached count the article is read again
+			count, err := query.commentCount(ctx, reqDto.Uuid)
+			if err == nil {
+				article.CommentCount = count
+				return &article, nil
+			}
+		}
+	}
+
+	result, err := query.primary.GetSingle(ctx, reqDto)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store to cache
+	articleJson, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.cache.Set(ctx, cacheKey, articleJson, articleCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = SetCommentCount(ctx, query.cache, result.Uuid, result.CommentCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (query *articleQueryCache) commentCount(ctx context.Context, uuid string) (int64, error) {
+	value, err := query.cache.Get(ctx, commentCountKeyBase+uuid)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// SetCommentCount caches the comment count of an article, GetSingle adds it
+// to the cached article.
+func SetCommentCount(ctx context.Context, c cache.Cache, uuid string, count int64) error {
+	return c.Set(ctx, commentCountKeyBase+uuid, []byte(strconv.FormatInt(count, 10)), articleCacheTTL)
+}
+
+// GetBySlug is not cached, the cached articles are keyed by uuid and a slug
+// can move to another article once its holder is purged.
+func (query *articleQueryCache) GetBySlug(ctx context.Context, reqDto dto.RequestArticleBySlug) (*model.Article, error) {
+	return query.primary.GetBySlug(ctx, reqDto)
+}
+
+func (query *articleQueryCache) GetList(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	// cache-key based on reqDto json -> md5, under the generation of its namespace
+	reqDtoJson, _ := json.Marshal(reqDto)
+	hash := md5.Sum(reqDtoJson)
+	cacheKey, err := listCacheKey(ctx, query.cache, reqDto, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// get from cache, ignore error
+	var result articleListCache
+	cacheResult, err := query.cache.Get(ctx, cacheKey)
+	if err == nil && len(cacheResult) > 0 {
+		err = json.Unmarshal(cacheResult, &result)
+		if err == nil {
+			return result.Articles, result.Total, nil
+		}
+	}
+
+	articles, total, err := query.primary.GetList(ctx, reqDto)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Store to cache
+	result = articleListCache{
+		Articles: articles,
+		Total:    total,
+	}
+	resultJson, err := json.Marshal(result)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.cache.Set(ctx, cacheKey, resultJson, articleCacheTTL)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// GetTrash is not cached, the trash is only browsed now and then.
+func (query *articleQueryCache) GetTrash(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	return query.primary.GetTrash(ctx, reqDto)
+}
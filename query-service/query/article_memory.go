@@ -0,0 +1,192 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/projection"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// articleQueryMemory reads the in-memory read model. The search matches
+// every word of the query anywhere in the title or body, unknown articles
+// are reported as mongo.ErrNoDocuments like the Mongo query does.
+type articleQueryMemory struct {
+	articles *projection.ArticleProjectionMemory
+}
+
+func NewArticleQueryMemory(articles *projection.ArticleProjectionMemory) ArticleQuery {
+	return &articleQueryMemory{
+		articles: articles,
+	}
+}
+
+func (query *articleQueryMemory) GetSingle(ctx context.Context, reqDto dto.RequestSingleArticle) (*model.Article, error) {
+	article, ok := query.articles.Find(reqDto.Uuid)
+	if !ok || article.DeletedAt != nil || !reqDto.Viewer.CanSee(article) {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return article, nil
+}
+
+func (query *articleQueryMemory) GetBySlug(ctx context.Context, reqDto dto.RequestArticleBySlug) (*model.Article, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	for _, article := range query.articles.Articles() {
+		if article.DeletedAt != nil || !hasSlug(article, reqDto.Slug) {
+			continue
+		}
+		if !reqDto.Viewer.CanSee(article) {
+			break
+		}
+
+		return article, nil
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+func (query *articleQueryMemory) GetList(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	matches := query.match(reqDto, false)
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].CreatedAt.After(matches[j].CreatedAt)
+	})
+
+	articles, total := page(matches, reqDto)
+	return articles, total, nil
+}
+
+func (query *articleQueryMemory) GetTrash(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	matches := query.match(reqDto, true)
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].DeletedAt.After(*matches[j].DeletedAt)
+	})
+
+	articles, total := page(matches, reqDto)
+	return articles, total, nil
+}
+
+// match returns the articles of the request that are either live or in the
+// trash.
+func (query *articleQueryMemory) match(reqDto dto.RequestListArticle, trashed bool) []*model.Article {
+	words := strings.Fields(strings.ToLower(reqDto.Query))
+
+	matches := []*model.Article{}
+	for _, article := range query.articles.Articles() {
+		if (article.DeletedAt != nil) != trashed {
+			continue
+		}
+		if !reqDto.Viewer.CanSee(article) || !hasStatus(article, reqDto.Status) {
+			continue
+		}
+		if matchesArticle(article, reqDto, words) {
+			matches = append(matches, article)
+		}
+	}
+
+	return matches
+}
+
+func page(matches []*model.Article, reqDto dto.RequestListArticle) ([]*model.Article, int64) {
+	total := int64(len(matches))
+
+	start := (reqDto.Page - 1) * reqDto.Limit
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + reqDto.Limit
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	return matches[start:end], total
+}
+
+func matchesArticle(article *model.Article, reqDto dto.RequestListArticle, words []string) bool {
+	if reqDto.Author != "" {
+		if isAuthorPrefix(reqDto.Author) {
+			if !strings.HasPrefix(article.Author, strings.TrimSuffix(reqDto.Author, "*")) {
+				return false
+			}
+		} else if article.Author != reqDto.Author {
+			return false
+		}
+	}
+
+	for _, tag := range requiredTags(reqDto) {
+		if !hasTag(article, tag) {
+			return false
+		}
+	}
+	if anyOf := normalizeTags(reqDto.TagsAny); len(anyOf) > 0 {
+		found := false
+		for _, tag := range anyOf {
+			found = found || hasTag(article, tag)
+		}
+		if !found {
+			return false
+		}
+	}
+	if category := normalizeTag(reqDto.Category); category != "" && article.Category != category {
+		return false
+	}
+
+	if !reqDto.CreatedFrom.IsZero() && article.CreatedAt.Before(reqDto.CreatedFrom) {
+		return false
+	}
+	if !reqDto.CreatedTo.IsZero() && article.CreatedAt.After(reqDto.CreatedTo) {
+		return false
+	}
+
+	text := strings.ToLower(article.Title + " " + article.Body)
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasSlug(article *model.Article, slug string) bool {
+	for _, held := range article.Slugs {
+		if held == slug {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasTag(article *model.Article, tag string) bool {
+	for _, articleTag := range article.Tags {
+		if articleTag == tag {
+			return true
+		}
+	}
+
+	return false
+}
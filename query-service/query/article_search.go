@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/search"
+	"github.com/go-playground/validator/v10"
+)
+
+// articleQuerySearch serves lists from a search index, ranked by relevance
+// and highlighted. Single articles and lists that include unpublished
+// articles still come from the primary query.
+type articleQuerySearch struct {
+	index   search.Index
+	primary ArticleQuery
+}
+
+func NewArticleQuerySearch(index search.Index, primary ArticleQuery) ArticleQuery {
+	return &articleQuerySearch{
+		index:   index,
+		primary: primary,
+	}
+}
+
+func (query *articleQuerySearch) GetSingle(ctx context.Context, reqDto dto.RequestSingleArticle) (*model.Article, error) {
+	return query.primary.GetSingle(ctx, reqDto)
+}
+
+func (query *articleQuerySearch) GetBySlug(ctx context.Context, reqDto dto.RequestArticleBySlug) (*model.Article, error) {
+	return query.primary.GetBySlug(ctx, reqDto)
+}
+
+func (query *articleQuerySearch) GetList(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	// the index only holds published articles
+	if reqDto.Viewer != (dto.Viewer{}) || (reqDto.Status != "" && reqDto.Status != model.StatusPublished) {
+		return query.primary.GetList(ctx, reqDto)
+	}
+
+	return query.index.Search(ctx, reqDto)
+}
+
+// GetTrash comes from the primary query, the index drops deleted articles.
+func (query *articleQuerySearch) GetTrash(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	return query.primary.GetTrash(ctx, reqDto)
+}
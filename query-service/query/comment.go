@@ -0,0 +1,91 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CommentQuery reads the comments of an article. Whether the viewer may read
+// the article is left to the caller.
+type CommentQuery interface {
+	// GetList returns a page of the top-level comments of an article, oldest
+	// first, followed by every reply in their threads, oldest first. The
+	// total counts the top-level comments.
+	GetList(ctx context.Context, reqDto dto.RequestListComment) ([]*model.Comment, int64, error)
+}
+
+type commentQueryMongo struct {
+	collection *mongo.Collection
+}
+
+func NewCommentQueryMongo(collection *mongo.Collection) CommentQuery {
+	return &commentQueryMongo{
+		collection: collection,
+	}
+}
+
+func (query *commentQueryMongo) GetList(ctx context.Context, reqDto dto.RequestListComment) ([]*model.Comment, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	filter := bson.M{"article_id": reqDto.ArticleID, "depth": 0}
+
+	total, err := query.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "uuid", Value: 1}}).
+		SetSkip(int64((reqDto.Page - 1) * reqDto.Limit)).
+		SetLimit(int64(reqDto.Limit))
+
+	comments, err := query.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(comments) == 0 {
+		return comments, total, nil
+	}
+
+	threads := bson.A{}
+	for _, comment := range comments {
+		threads = append(threads, comment.Uuid)
+	}
+
+	replies, err := query.find(
+		ctx,
+		bson.M{"thread_id": bson.M{"$in": threads}, "depth": bson.M{"$gt": 0}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "uuid", Value: 1}}),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append(comments, replies...), total, nil
+}
+
+func (query *commentQueryMongo) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*model.Comment, error) {
+	cursor, err := query.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []*model.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
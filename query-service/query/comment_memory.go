@@ -0,0 +1,61 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/projection"
+	"github.com/go-playground/validator/v10"
+)
+
+type commentQueryMemory struct {
+	comments *projection.CommentProjectionMemory
+}
+
+func NewCommentQueryMemory(comments *projection.CommentProjectionMemory) CommentQuery {
+	return &commentQueryMemory{
+		comments: comments,
+	}
+}
+
+func (query *commentQueryMemory) GetList(ctx context.Context, reqDto dto.RequestListComment) ([]*model.Comment, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	all := query.comments.Comments(reqDto.ArticleID)
+
+	topLevel := []*model.Comment{}
+	for _, comment := range all {
+		if comment.Depth == 0 {
+			topLevel = append(topLevel, comment)
+		}
+	}
+	total := int64(len(topLevel))
+
+	start := (reqDto.Page - 1) * reqDto.Limit
+	if start > len(topLevel) {
+		start = len(topLevel)
+	}
+	end := start + reqDto.Limit
+	if end > len(topLevel) {
+		end = len(topLevel)
+	}
+
+	comments := append([]*model.Comment{}, topLevel[start:end]...)
+	threads := map[string]bool{}
+	for _, comment := range comments {
+		threads[comment.Uuid] = true
+	}
+
+	for _, comment := range all {
+		if comment.Depth > 0 && threads[comment.ThreadID] {
+			comments = append(comments, comment)
+		}
+	}
+
+	return comments, total, nil
+}
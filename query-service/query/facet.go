@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FacetQuery lists the tags and categories with the number of published
+// articles that have them, most used first.
+type FacetQuery interface {
+	Tags(ctx context.Context, reqDto dto.RequestListFacet) ([]*model.Facet, error)
+	Categories(ctx context.Context, reqDto dto.RequestListFacet) ([]*model.Facet, error)
+}
+
+type facetQueryMongo struct {
+	tags       *mongo.Collection
+	categories *mongo.Collection
+}
+
+func NewFacetQueryMongo(tags, categories *mongo.Collection) FacetQuery {
+	return &facetQueryMongo{
+		tags:       tags,
+		categories: categories,
+	}
+}
+
+func (query *facetQueryMongo) Tags(ctx context.Context, reqDto dto.RequestListFacet) ([]*model.Facet, error) {
+	return query.find(ctx, query.tags, reqDto)
+}
+
+func (query *facetQueryMongo) Categories(ctx context.Context, reqDto dto.RequestListFacet) ([]*model.Facet, error) {
+	return query.find(ctx, query.categories, reqDto)
+}
+
+func (query *facetQueryMongo) find(ctx context.Context, collection *mongo.Collection, reqDto dto.RequestListFacet) ([]*model.Facet, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "count", Value: -1}, {Key: "name", Value: 1}}).
+		SetLimit(int64(reqDto.Limit))
+
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	facets := []*model.Facet{}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
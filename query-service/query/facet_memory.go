@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/projection"
+	"github.com/go-playground/validator/v10"
+)
+
+type facetQueryMemory struct {
+	facets *projection.FacetProjectionMemory
+}
+
+func NewFacetQueryMemory(facets *projection.FacetProjectionMemory) FacetQuery {
+	return &facetQueryMemory{
+		facets: facets,
+	}
+}
+
+func (query *facetQueryMemory) Tags(ctx context.Context, reqDto dto.RequestListFacet) ([]*model.Facet, error) {
+	return limitFacets(query.facets.Tags(), reqDto)
+}
+
+func (query *facetQueryMemory) Categories(ctx context.Context, reqDto dto.RequestListFacet) ([]*model.Facet, error) {
+	return limitFacets(query.facets.Categories(), reqDto)
+}
+
+func limitFacets(facets []*model.Facet, reqDto dto.RequestListFacet) ([]*model.Facet, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	if len(facets) > reqDto.Limit {
+		facets = facets[:reqDto.Limit]
+	}
+
+	return facets, nil
+}
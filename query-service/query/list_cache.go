@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Adhiana46/query-service/cache"
+	"github.com/Adhiana46/query-service/dto"
+)
+
+// The list cache is split in namespaces, each one with a generation counter
+// that is part of the cache key. Bumping a generation makes every list cached
+// under it unreachable, the old entries simply expire.
+const (
+	listGenerationKey       = "article-list-gen"
+	authorGenerationKeyBase = "article-list-gen-author-"
+)
+
+// InvalidateArticleLists drops the cached lists a write to the articles of
+// the given authors can change: every list that is not limited to one author,
+// and the lists of those authors.
+func InvalidateArticleLists(ctx context.Context, c cache.Cache, authors ...string) error {
+	keys := []string{listGenerationKey}
+	for _, author := range authors {
+		if author == "" {
+			continue
+		}
+		keys = append(keys, authorGenerationKeyBase+author)
+	}
+
+	return c.Incr(ctx, keys...)
+}
+
+// listNamespace returns the generation key the list request is cached under.
+// Only an exact author filter narrows the list to a single author.
+func listNamespace(reqDto dto.RequestListArticle) string {
+	if reqDto.Author != "" && !isAuthorPrefix(reqDto.Author) {
+		return authorGenerationKeyBase + reqDto.Author
+	}
+
+	return listGenerationKey
+}
+
+func listCacheKey(ctx context.Context, c cache.Cache, reqDto dto.RequestListArticle, hash string) (string, error) {
+	namespace := listNamespace(reqDto)
+
+	var generation int64
+	value, err := c.Get(ctx, namespace)
+	switch {
+	case errors.Is(err, cache.ErrMiss):
+	case err != nil:
+		return "", err
+	default:
+		generation, err = strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%s-%d-%s", namespace, generation, hash), nil
+}
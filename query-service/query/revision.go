@@ -0,0 +1,114 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Adhiana46/query-service/diff"
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RevisionQuery reads the revision history of an article. Unknown revisions
+// are reported as mongo.ErrNoDocuments.
+type RevisionQuery interface {
+	// GetList returns the revisions of an article, newest first.
+	GetList(ctx context.Context, reqDto dto.RequestListRevision) ([]*model.Revision, int64, error)
+	GetSingle(ctx context.Context, reqDto dto.RequestSingleRevision) (*model.Revision, error)
+	// GetLatest returns the newest revision of an article.
+	GetLatest(ctx context.Context, uuid string) (*model.Revision, error)
+}
+
+// DiffRevisions returns the unified diff of the title and body of two
+// revisions, a nil from diffs against an empty article.
+func DiffRevisions(from, to *model.Revision) string {
+	fromName := fmt.Sprintf("%s@0", to.Uuid)
+	fromText := ""
+	if from != nil {
+		fromName = fmt.Sprintf("%s@%d", from.Uuid, from.Revision)
+		fromText = revisionText(from)
+	}
+
+	return diff.Unified(fromName, fmt.Sprintf("%s@%d", to.Uuid, to.Revision), fromText, revisionText(to))
+}
+
+func revisionText(revision *model.Revision) string {
+	return revision.Title + "\n\n" + revision.Body + "\n"
+}
+
+type revisionQueryMongo struct {
+	collection *mongo.Collection
+}
+
+func NewRevisionQueryMongo(collection *mongo.Collection) RevisionQuery {
+	return &revisionQueryMongo{
+		collection: collection,
+	}
+}
+
+func (query *revisionQueryMongo) GetList(ctx context.Context, reqDto dto.RequestListRevision) ([]*model.Revision, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	filter := bson.M{"uuid": reqDto.Uuid}
+
+	total, err := query.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revision", Value: -1}}).
+		SetSkip(int64((reqDto.Page - 1) * reqDto.Limit)).
+		SetLimit(int64(reqDto.Limit))
+
+	cursor, err := query.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []*model.Revision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+func (query *revisionQueryMongo) GetSingle(ctx context.Context, reqDto dto.RequestSingleRevision) (*model.Revision, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	var revision model.Revision
+
+	err := query.collection.FindOne(ctx, bson.M{"uuid": reqDto.Uuid, "revision": reqDto.Revision}).Decode(&revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+func (query *revisionQueryMongo) GetLatest(ctx context.Context, uuid string) (*model.Revision, error) {
+	var revision model.Revision
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}})
+
+	err := query.collection.FindOne(ctx, bson.M{"uuid": uuid}, opts).Decode(&revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
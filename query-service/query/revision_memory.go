@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/projection"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type revisionQueryMemory struct {
+	revisions *projection.RevisionProjectionMemory
+}
+
+func NewRevisionQueryMemory(revisions *projection.RevisionProjectionMemory) RevisionQuery {
+	return &revisionQueryMemory{
+		revisions: revisions,
+	}
+}
+
+func (query *revisionQueryMemory) GetList(ctx context.Context, reqDto dto.RequestListRevision) ([]*model.Revision, int64, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, 0, err
+	}
+
+	revisions := query.revisions.Revisions(reqDto.Uuid)
+	total := int64(len(revisions))
+
+	start := (reqDto.Page - 1) * reqDto.Limit
+	if start > len(revisions) {
+		start = len(revisions)
+	}
+	end := start + reqDto.Limit
+	if end > len(revisions) {
+		end = len(revisions)
+	}
+
+	return revisions[start:end], total, nil
+}
+
+func (query *revisionQueryMemory) GetSingle(ctx context.Context, reqDto dto.RequestSingleRevision) (*model.Revision, error) {
+	validate := validator.New()
+
+	if err := validate.Struct(reqDto); err != nil {
+		return nil, err
+	}
+
+	for _, revision := range query.revisions.Revisions(reqDto.Uuid) {
+		if revision.Revision == reqDto.Revision {
+			return revision, nil
+		}
+	}
+
+	return nil, mongo.ErrNoDocuments
+}
+
+func (query *revisionQueryMemory) GetLatest(ctx context.Context, uuid string) (*model.Revision, error) {
+	revisions := query.revisions.Revisions(uuid)
+	if len(revisions) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return revisions[0], nil
+}
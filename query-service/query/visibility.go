@@ -0,0 +1,67 @@
+package query
+
+import (
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// publishedFilter matches the published articles, documents projected before
+// the editorial workflow have no status and are published.
+var publishedFilter = bson.M{"status": bson.M{"$in": bson.A{model.StatusPublished, nil}}}
+
+// visibilityFilter limits a query to the articles the viewer may read, it
+// returns nil when the viewer sees every article.
+func visibilityFilter(viewer dto.Viewer) bson.M {
+	switch {
+	case viewer.Editor:
+		return nil
+	case viewer.Subject != "":
+		return bson.M{"$or": bson.A{publishedFilter, bson.M{"author": viewer.Subject}}}
+	default:
+		return publishedFilter
+	}
+}
+
+// statusFilter matches the articles in the requested status, nil when the
+// request is not limited to one.
+func statusFilter(status string) bson.M {
+	switch status {
+	case "":
+		return nil
+	case model.StatusPublished:
+		return publishedFilter
+	default:
+		return bson.M{"status": status}
+	}
+}
+
+// withVisibility adds the visibility and status conditions of the viewer to
+// the filter.
+func withVisibility(filter bson.M, viewer dto.Viewer, status string) bson.M {
+	conditions := bson.A{}
+	for _, condition := range []bson.M{visibilityFilter(viewer), statusFilter(status)} {
+		if condition != nil {
+			conditions = append(conditions, condition)
+		}
+	}
+
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+
+	return filter
+}
+
+// hasStatus reports whether the article is in the requested status, an empty
+// status matches every article.
+func hasStatus(article *model.Article, status string) bool {
+	switch status {
+	case "":
+		return true
+	case model.StatusPublished:
+		return article.IsPublished()
+	default:
+		return article.Status == status
+	}
+}
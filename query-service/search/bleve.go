@@ -0,0 +1,310 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// bleveIndex is an embedded search engine kept on the local disk, it needs no
+// cluster and suits development and tests. The latest version of every
+// article, deleted ones included, is kept in the internal storage of the
+// index to refuse out-of-order events.
+type bleveIndex struct {
+	mu    sync.Mutex
+	index bleve.Index
+}
+
+type bleveDocument struct {
+	Uuid     string   `json:"uuid"`
+	Slug     string   `json:"slug"`
+	Author   string   `json:"author"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Tags     []string `json:"tags"`
+	Category string   `json:"category"`
+	Status   string   `json:"status"`
+	Version  int      `json:"version"`
+	// CommentCount changes without a new version
+	CommentCount int64     `json:"comment_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NewBleveIndex opens the index at path, or creates it with the article
+// mapping. An empty path keeps the index in memory.
+func NewBleveIndex(path string) (Index, error) {
+	if path == "" {
+		index, err := bleve.NewMemOnly(bleveMapping())
+		if err != nil {
+			return nil, err
+		}
+		return &bleveIndex{index: index}, nil
+	}
+
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, bleveMapping())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &bleveIndex{index: index}, nil
+}
+
+func bleveMapping() *mapping.IndexMappingImpl {
+	keyword := bleve.NewKeywordFieldMapping()
+	text := bleve.NewTextFieldMapping()
+	numeric := bleve.NewNumericFieldMapping()
+	datetime := bleve.NewDateTimeFieldMapping()
+
+	article := bleve.NewDocumentMapping()
+	article.AddFieldMappingsAt("uuid", keyword)
+	article.AddFieldMappingsAt("slug", keyword)
+	article.AddFieldMappingsAt("author", keyword)
+	article.AddFieldMappingsAt("title", text)
+	article.AddFieldMappingsAt("body", text)
+	article.AddFieldMappingsAt("tags", keyword)
+	article.AddFieldMappingsAt("category", keyword)
+	article.AddFieldMappingsAt("status", keyword)
+	article.AddFieldMappingsAt("version", numeric)
+	article.AddFieldMappingsAt("comment_count", numeric)
+	article.AddFieldMappingsAt("created_at", datetime)
+	article.AddFieldMappingsAt("updated_at", datetime)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = article
+
+	return indexMapping
+}
+
+func (idx *bleveIndex) Index(ctx context.Context, article *model.Article) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	// the version held is rewritten, only a newer one is kept
+	stale, err := idx.isStale(article.Uuid, article.Version+1)
+	if err != nil || stale {
+		return err
+	}
+
+	err = idx.index.Index(article.Uuid, bleveDocument{
+		Uuid:      article.Uuid,
+		Slug:      article.Slug,
+		Author:    article.Author,
+		Title:     article.Title,
+		Body:      article.Body,
+		Tags:      indexedTags(article.Tags),
+		Category:  article.Category,
+		Status:    article.Status,
+		Version:   article.Version,
+		CreatedAt: article.CreatedAt,
+		UpdatedAt: article.UpdatedAt,
+
+		CommentCount: article.CommentCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	return idx.setVersion(article.Uuid, article.Version)
+}
+
+func (idx *bleveIndex) Delete(ctx context.Context, uuid string, version int) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	stale, err := idx.isStale(uuid, version)
+	if err != nil || stale {
+		return err
+	}
+
+	if err := idx.index.Delete(uuid); err != nil {
+		return err
+	}
+
+	return idx.setVersion(uuid, version)
+}
+
+func (idx *bleveIndex) Search(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	var match query.Query = bleve.NewMatchAllQuery()
+	if reqDto.Query != "" {
+		title := bleve.NewMatchQuery(reqDto.Query)
+		title.SetField("title")
+		title.SetBoost(5)
+
+		body := bleve.NewMatchQuery(reqDto.Query)
+		body.SetField("body")
+
+		match = bleve.NewDisjunctionQuery(title, body)
+	}
+
+	conjuncts := []query.Query{match}
+	if reqDto.Author != "" {
+		if strings.HasSuffix(reqDto.Author, "*") {
+			author := bleve.NewPrefixQuery(strings.TrimSuffix(reqDto.Author, "*"))
+			author.SetField("author")
+			conjuncts = append(conjuncts, author)
+		} else {
+			author := bleve.NewTermQuery(reqDto.Author)
+			author.SetField("author")
+			conjuncts = append(conjuncts, author)
+		}
+	}
+	required, anyOf, category := taxonomyFilter(reqDto)
+	for _, tag := range required {
+		conjuncts = append(conjuncts, termQuery("tags", tag))
+	}
+	if len(anyOf) > 0 {
+		tags := []query.Query{}
+		for _, tag := range anyOf {
+			tags = append(tags, termQuery("tags", tag))
+		}
+		conjuncts = append(conjuncts, bleve.NewDisjunctionQuery(tags...))
+	}
+	if category != "" {
+		conjuncts = append(conjuncts, termQuery("category", category))
+	}
+	if !reqDto.CreatedFrom.IsZero() || !reqDto.CreatedTo.IsZero() {
+		inclusive := true
+		createdAt := bleve.NewDateRangeInclusiveQuery(reqDto.CreatedFrom, reqDto.CreatedTo, &inclusive, &inclusive)
+		createdAt.SetField("created_at")
+		conjuncts = append(conjuncts, createdAt)
+	}
+
+	request := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(conjuncts...), reqDto.Limit, (reqDto.Page-1)*reqDto.Limit, false)
+	request.Fields = []string{"*"}
+	if reqDto.Query != "" {
+		// most relevant first when searching
+		request.SortBy([]string{"-_score", "-created_at"})
+	} else {
+		request.SortBy([]string{"-created_at"})
+	}
+
+	request.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	for _, field := range highlightFields {
+		request.Highlight.AddField(field)
+	}
+
+	result, err := idx.index.SearchInContext(ctx, request)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	articles := []*model.Article{}
+	for _, hit := range result.Hits {
+		article := &model.Article{
+			Uuid:       stringField(hit.Fields, "uuid"),
+			Slug:       stringField(hit.Fields, "slug"),
+			Author:     stringField(hit.Fields, "author"),
+			Title:      stringField(hit.Fields, "title"),
+			Body:       stringField(hit.Fields, "body"),
+			Tags:       stringsField(hit.Fields, "tags"),
+			Category:   stringField(hit.Fields, "category"),
+			Status:     indexedStatus(stringField(hit.Fields, "status")),
+			CreatedAt:  timeField(hit.Fields, "created_at"),
+			UpdatedAt:  timeField(hit.Fields, "updated_at"),
+			Highlights: matchedFragments(hit.Fragments),
+		}
+		if version, ok := hit.Fields["version"].(float64); ok {
+			article.Version = int(version)
+		}
+		if count, ok := hit.Fields["comment_count"].(float64); ok {
+			article.CommentCount = int64(count)
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles, int64(result.Total), nil
+}
+
+func (idx *bleveIndex) Close() error {
+	return idx.index.Close()
+}
+
+func (idx *bleveIndex) isStale(uuid string, version int) (bool, error) {
+	stored, err := idx.index.GetInternal(versionKey(uuid))
+	if err != nil || stored == nil {
+		return false, err
+	}
+
+	storedVersion, err := strconv.Atoi(string(stored))
+	if err != nil {
+		return false, nil
+	}
+
+	return storedVersion >= version, nil
+}
+
+func (idx *bleveIndex) setVersion(uuid string, version int) error {
+	return idx.index.SetInternal(versionKey(uuid), []byte(strconv.Itoa(version)))
+}
+
+func versionKey(uuid string) []byte {
+	return []byte("version/" + uuid)
+}
+
+// matchedFragments drops the fragments bleve returns for fields without a
+// match, they hold the start of the field text.
+func matchedFragments(fragments map[string][]string) map[string][]string {
+	result := map[string][]string{}
+	for field, values := range fragments {
+		for _, value := range values {
+			if strings.Contains(value, "<mark>") {
+				result[field] = append(result[field], value)
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func stringField(fields map[string]interface{}, name string) string {
+	value, _ := fields[name].(string)
+	return value
+}
+
+// stringsField reads a list, bleve returns a list of one value as the value.
+func stringsField(fields map[string]interface{}, name string) []string {
+	switch value := fields[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := []string{}
+		for _, v := range value {
+			if str, ok := v.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	}
+
+	return []string{}
+}
+
+func termQuery(field string, term string) query.Query {
+	q := bleve.NewTermQuery(term)
+	q.SetField(field)
+	return q
+}
+
+func timeField(fields map[string]interface{}, name string) time.Time {
+	value, _ := fields[name].(string)
+	parsed, _ := time.Parse(time.RFC3339, value)
+	return parsed
+}
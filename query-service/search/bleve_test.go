@@ -0,0 +1,283 @@
+package search
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+)
+
+func newTestBleveIndex(t *testing.T) Index {
+	t.Helper()
+
+	index, err := NewBleveIndex(filepath.Join(t.TempDir(), "articles.bleve"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { index.Close() })
+
+	return index
+}
+
+func testArticle(uuid string, version int, title string, body string) *model.Article {
+	createdAt := time.Date(2022, 11, 5, 10, 0, 0, 0, time.UTC)
+
+	return &model.Article{
+		Uuid:      uuid,
+		Slug:      uuid,
+		Author:    "author-1",
+		Title:     title,
+		Body:      body,
+		Status:    model.StatusPublished,
+		Version:   version,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func searchUuids(t *testing.T, index Index, reqDto dto.RequestListArticle) []string {
+	t.Helper()
+
+	if reqDto.Page == 0 {
+		reqDto.Page, reqDto.Limit = 1, 10
+	}
+
+	articles, total, err := index.Search(context.Background(), reqDto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != int64(len(articles)) {
+		t.Errorf("total = %d, want %d", total, len(articles))
+	}
+
+	uuids := []string{}
+	for _, article := range articles {
+		uuids = append(uuids, article.Uuid)
+	}
+	return uuids
+}
+
+func TestBleveIndexKeepsNewestVersion(t *testing.T) {
+	ctx := context.Background()
+	index := newTestBleveIndex(t)
+
+	if err := index.Index(ctx, testArticle("article-1", 2, "Second title", "Body")); err != nil {
+		t.Fatal(err)
+	}
+	// delivered late, must not overwrite version 2
+	if err := index.Index(ctx, testArticle("article-1", 1, "First title", "Body")); err != nil {
+		t.Fatal(err)
+	}
+
+	articles, _, err := index.Search(ctx, dto.RequestListArticle{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].Version != 2 || articles[0].Title != "Second title" {
+		t.Fatalf("articles = %+v, want version 2 only", articles)
+	}
+	if articles[0].Status != model.StatusPublished {
+		t.Errorf("status = %q, want the indexed status", articles[0].Status)
+	}
+}
+
+func TestBleveIndexRewritesCommentCount(t *testing.T) {
+	ctx := context.Background()
+	index := newTestBleveIndex(t)
+
+	article := testArticle("article-1", 2, "Title", "Body")
+	if err := index.Index(ctx, article); err != nil {
+		t.Fatal(err)
+	}
+
+	// a comment changes the count, not the version
+	article.CommentCount = 3
+	if err := index.Index(ctx, article); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := testArticle("article-1", 1, "Old title", "Body")
+	if err := index.Index(ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	articles, _, err := index.Search(ctx, dto.RequestListArticle{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].CommentCount != 3 || articles[0].Title != "Title" {
+		t.Fatalf("articles = %+v, want version 2 with 3 comments", articles)
+	}
+}
+
+func TestBleveIndexDeleteIsVersionGuarded(t *testing.T) {
+	ctx := context.Background()
+	index := newTestBleveIndex(t)
+
+	if err := index.Index(ctx, testArticle("article-1", 3, "Title", "Body")); err != nil {
+		t.Fatal(err)
+	}
+
+	// a delete older than the indexed article is ignored
+	if err := index.Delete(ctx, "article-1", 2); err != nil {
+		t.Fatal(err)
+	}
+	if uuids := searchUuids(t, index, dto.RequestListArticle{}); len(uuids) != 1 {
+		t.Fatalf("found %v after a stale delete, want article-1", uuids)
+	}
+
+	if err := index.Delete(ctx, "article-1", 4); err != nil {
+		t.Fatal(err)
+	}
+	if uuids := searchUuids(t, index, dto.RequestListArticle{}); len(uuids) != 0 {
+		t.Fatalf("found %v after the delete, want nothing", uuids)
+	}
+
+	// an update delivered after the delete doesn't bring the article back
+	if err := index.Index(ctx, testArticle("article-1", 3, "Title", "Body")); err != nil {
+		t.Fatal(err)
+	}
+	if uuids := searchUuids(t, index, dto.RequestListArticle{}); len(uuids) != 0 {
+		t.Fatalf("found %v after a stale update, want nothing", uuids)
+	}
+}
+
+func TestBleveIndexRanksTitleMatchesFirst(t *testing.T) {
+	ctx := context.Background()
+	index := newTestBleveIndex(t)
+
+	articles := []*model.Article{
+		testArticle("in-body", 1, "Weekly notes", "A few words about golang channels"),
+		testArticle("in-title", 1, "Golang channels", "A few words about concurrency"),
+		testArticle("unrelated", 1, "Gardening", "Tomatoes need sun"),
+	}
+	for _, article := range articles {
+		if err := index.Index(ctx, article); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, _, err := index.Search(ctx, dto.RequestListArticle{Page: 1, Limit: 10, Query: "golang"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].Uuid != "in-title" || found[1].Uuid != "in-body" {
+		t.Fatalf("found %v, want in-title before in-body", found)
+	}
+	if len(found[0].Highlights["title"]) == 0 {
+		t.Errorf("highlights = %v, want the title match", found[0].Highlights)
+	}
+}
+
+func TestBleveIndexFiltersByAuthor(t *testing.T) {
+	ctx := context.Background()
+	index := newTestBleveIndex(t)
+
+	for _, author := range []string{"alice", "alex", "bob"} {
+		article := testArticle(author, 1, "Title", "Body")
+		article.Author = author
+		if err := index.Index(ctx, article); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if uuids := searchUuids(t, index, dto.RequestListArticle{Author: "bob"}); len(uuids) != 1 || uuids[0] != "bob" {
+		t.Errorf("author bob found %v", uuids)
+	}
+	if uuids := searchUuids(t, index, dto.RequestListArticle{Author: "al*"}); len(uuids) != 2 {
+		t.Errorf("author al* found %v, want alice and alex", uuids)
+	}
+}
+
+func TestBleveIndexFiltersByTagsAndCategory(t *testing.T) {
+	ctx := context.Background()
+	index := newTestBleveIndex(t)
+
+	articles := []struct {
+		uuid     string
+		tags     []string
+		category string
+	}{
+		{"go-web", []string{"go", "web"}, "backend"},
+		{"go", []string{"go"}, "backend"},
+		{"rust", []string{"rust"}, "systems"},
+		{"untagged", nil, ""},
+	}
+	for _, a := range articles {
+		article := testArticle(a.uuid, 1, "Title", "Body")
+		article.Tags = a.tags
+		article.Category = a.category
+		if err := index.Index(ctx, article); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		reqDto dto.RequestListArticle
+		want   []string
+	}{
+		{"tag", dto.RequestListArticle{Tag: "Go"}, []string{"go", "go-web"}},
+		{"tags_all", dto.RequestListArticle{TagsAll: []string{"go", "web"}}, []string{"go-web"}},
+		{"tags_any", dto.RequestListArticle{TagsAny: []string{"web", "rust"}}, []string{"go-web", "rust"}},
+		{"category", dto.RequestListArticle{Category: "systems"}, []string{"rust"}},
+		{"tag and category", dto.RequestListArticle{Tag: "go", Category: "systems"}, []string{}},
+	}
+	for _, tt := range tests {
+		uuids := searchUuids(t, index, tt.reqDto)
+		sort.Strings(uuids)
+		if strings.Join(uuids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s found %v, want %v", tt.name, uuids, tt.want)
+		}
+	}
+
+	// the hits carry the taxonomy like the primary query
+	found, _, err := index.Search(ctx, dto.RequestListArticle{Page: 1, Limit: 10, TagsAll: []string{"web"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || strings.Join(found[0].Tags, ",") != "go,web" || found[0].Category != "backend" {
+		t.Errorf("found %+v, want the tags and category of go-web", found)
+	}
+	found, _, err = index.Search(ctx, dto.RequestListArticle{Page: 1, Limit: 10, Tag: "rust"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || strings.Join(found[0].Tags, ",") != "rust" {
+		t.Errorf("found %+v, want the single tag of rust", found)
+	}
+}
+
+func TestBleveIndexKeepsVersionsWhenReopened(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.bleve")
+
+	index, err := NewBleveIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Delete(ctx, "article-1", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err = NewBleveIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	if err := index.Index(ctx, testArticle("article-1", 1, "Title", "Body")); err != nil {
+		t.Fatal(err)
+	}
+	if uuids := searchUuids(t, index, dto.RequestListArticle{}); len(uuids) != 0 {
+		t.Fatalf("found %v, want the delete remembered after reopening", uuids)
+	}
+}
@@ -0,0 +1,305 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+)
+
+// elasticsearchIndex talks to the REST API shared by Elasticsearch 7+ and
+// OpenSearch. Documents are written with external versioning, the cluster
+// itself refuses versions older than the stored one.
+type elasticsearchIndex struct {
+	baseURL string
+	name    string
+	client  *http.Client
+}
+
+type elasticsearchDocument struct {
+	Uuid     string   `json:"uuid"`
+	Slug     string   `json:"slug"`
+	Author   string   `json:"author"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Tags     []string `json:"tags"`
+	Category string   `json:"category"`
+	Status   string   `json:"status"`
+	Version  int      `json:"version"`
+	// CommentCount changes without a new version
+	CommentCount int64     `json:"comment_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type elasticsearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source    elasticsearchDocument `json:"_source"`
+			Highlight map[string][]string   `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+var elasticsearchMapping = map[string]any{
+	"mappings": map[string]any{
+		"properties": map[string]any{
+			"uuid":          map[string]any{"type": "keyword"},
+			"slug":          map[string]any{"type": "keyword"},
+			"author":        map[string]any{"type": "keyword"},
+			"title":         map[string]any{"type": "text"},
+			"body":          map[string]any{"type": "text"},
+			"tags":          map[string]any{"type": "keyword"},
+			"category":      map[string]any{"type": "keyword"},
+			"status":        map[string]any{"type": "keyword"},
+			"version":       map[string]any{"type": "integer"},
+			"comment_count": map[string]any{"type": "long"},
+			"created_at":    map[string]any{"type": "date"},
+			"updated_at":    map[string]any{"type": "date"},
+		},
+	},
+}
+
+// NewElasticsearchIndex uses the index of the cluster at baseURL and creates
+// it with the article mapping when it does not exist yet.
+func NewElasticsearchIndex(ctx context.Context, baseURL string, name string) (Index, error) {
+	idx := &elasticsearchIndex{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		name:    name,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	status, _, err := idx.do(ctx, http.MethodHead, "/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == http.StatusNotFound {
+		status, body, err := idx.do(ctx, http.MethodPut, "/"+url.PathEscape(name), elasticsearchMapping)
+		if err != nil {
+			return nil, err
+		}
+		// another replica may have created it meanwhile
+		if status >= 300 && !strings.Contains(string(body), "resource_already_exists_exception") {
+			return nil, fmt.Errorf("create index %s: %d %s", name, status, body)
+		}
+		return idx, nil
+	}
+
+	// an index created by an older release gets the fields added since
+	status, body, err := idx.do(ctx, http.MethodPut, "/"+url.PathEscape(name)+"/_mapping", elasticsearchMapping["mappings"])
+	if err != nil {
+		return nil, err
+	}
+	if status >= 300 {
+		return nil, fmt.Errorf("update mapping of index %s: %d %s", name, status, body)
+	}
+
+	return idx, nil
+}
+
+func (idx *elasticsearchIndex) Index(ctx context.Context, article *model.Article) error {
+	doc := elasticsearchDocument{
+		Uuid:      article.Uuid,
+		Slug:      article.Slug,
+		Author:    article.Author,
+		Title:     article.Title,
+		Body:      article.Body,
+		Tags:      indexedTags(article.Tags),
+		Category:  article.Category,
+		Status:    article.Status,
+		Version:   article.Version,
+		CreatedAt: article.CreatedAt,
+		UpdatedAt: article.UpdatedAt,
+
+		CommentCount: article.CommentCount,
+	}
+
+	// external_gte rewrites the version the index holds
+	status, body, err := idx.do(ctx, http.MethodPut, idx.docPath(article.Uuid, article.Version, "external_gte"), doc)
+	if err != nil {
+		return err
+	}
+
+	// 409 means the index already holds a newer version
+	if status >= 300 && status != http.StatusConflict {
+		return fmt.Errorf("index article %s: %d %s", article.Uuid, status, body)
+	}
+
+	return nil
+}
+
+func (idx *elasticsearchIndex) Delete(ctx context.Context, uuid string, version int) error {
+	status, body, err := idx.do(ctx, http.MethodDelete, idx.docPath(uuid, version, "external"), nil)
+	if err != nil {
+		return err
+	}
+
+	if status >= 300 && status != http.StatusConflict && status != http.StatusNotFound {
+		return fmt.Errorf("delete article %s: %d %s", uuid, status, body)
+	}
+
+	return nil
+}
+
+func (idx *elasticsearchIndex) Search(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error) {
+	must := []any{}
+	if reqDto.Query != "" {
+		must = append(must, map[string]any{
+			"multi_match": map[string]any{
+				"query":  reqDto.Query,
+				"fields": []string{"title^5", "body"},
+			},
+		})
+	} else {
+		must = append(must, map[string]any{"match_all": map[string]any{}})
+	}
+
+	filter := []any{}
+	if reqDto.Author != "" {
+		if strings.HasSuffix(reqDto.Author, "*") {
+			filter = append(filter, map[string]any{"prefix": map[string]any{"author": strings.TrimSuffix(reqDto.Author, "*")}})
+		} else {
+			filter = append(filter, map[string]any{"term": map[string]any{"author": reqDto.Author}})
+		}
+	}
+	required, anyOf, category := taxonomyFilter(reqDto)
+	for _, tag := range required {
+		filter = append(filter, map[string]any{"term": map[string]any{"tags": tag}})
+	}
+	if len(anyOf) > 0 {
+		filter = append(filter, map[string]any{"terms": map[string]any{"tags": anyOf}})
+	}
+	if category != "" {
+		filter = append(filter, map[string]any{"term": map[string]any{"category": category}})
+	}
+	if createdAt := createdAtRange(reqDto); len(createdAt) > 0 {
+		filter = append(filter, map[string]any{"range": map[string]any{"created_at": createdAt}})
+	}
+
+	sort := []any{map[string]any{"created_at": "desc"}}
+	if reqDto.Query != "" {
+		// most relevant first when searching
+		sort = append([]any{"_score"}, sort...)
+	}
+
+	highlight := map[string]any{}
+	for _, field := range highlightFields {
+		highlight[field] = map[string]any{}
+	}
+
+	request := map[string]any{
+		"from":             (reqDto.Page - 1) * reqDto.Limit,
+		"size":             reqDto.Limit,
+		"track_total_hits": true,
+		"query": map[string]any{
+			"bool": map[string]any{
+				"must":   must,
+				"filter": filter,
+			},
+		},
+		"sort": sort,
+		"highlight": map[string]any{
+			"pre_tags":  []string{"<mark>"},
+			"post_tags": []string{"</mark>"},
+			"fields":    highlight,
+		},
+	}
+
+	status, body, err := idx.do(ctx, http.MethodPost, "/"+url.PathEscape(idx.name)+"/_search", request)
+	if err != nil {
+		return nil, 0, err
+	}
+	if status >= 300 {
+		return nil, 0, fmt.Errorf("search articles: %d %s", status, body)
+	}
+
+	var response elasticsearchResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, 0, err
+	}
+
+	articles := []*model.Article{}
+	for _, hit := range response.Hits.Hits {
+		articles = append(articles, &model.Article{
+			Uuid:         hit.Source.Uuid,
+			Slug:         hit.Source.Slug,
+			Author:       hit.Source.Author,
+			Title:        hit.Source.Title,
+			Body:         hit.Source.Body,
+			Tags:         indexedTags(hit.Source.Tags),
+			Category:     hit.Source.Category,
+			Status:       indexedStatus(hit.Source.Status),
+			Version:      hit.Source.Version,
+			CommentCount: hit.Source.CommentCount,
+			CreatedAt:    hit.Source.CreatedAt,
+			UpdatedAt:    hit.Source.UpdatedAt,
+			Highlights:   hit.Highlight,
+		})
+	}
+
+	return articles, response.Hits.Total.Value, nil
+}
+
+func (idx *elasticsearchIndex) Close() error {
+	idx.client.CloseIdleConnections()
+	return nil
+}
+
+func (idx *elasticsearchIndex) docPath(uuid string, version int, versionType string) string {
+	return fmt.Sprintf("/%s/_doc/%s?version=%d&version_type=%s", url.PathEscape(idx.name), url.PathEscape(uuid), version, versionType)
+}
+
+func (idx *elasticsearchIndex) do(ctx context.Context, method string, path string, payload any) (int, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, idx.baseURL+path, reqBody)
+	if err != nil {
+		return 0, nil, err
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := idx.client.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return response.StatusCode, body, nil
+}
+
+func createdAtRange(reqDto dto.RequestListArticle) map[string]any {
+	result := map[string]any{}
+	if !reqDto.CreatedFrom.IsZero() {
+		result["gte"] = reqDto.CreatedFrom.Format(time.RFC3339)
+	}
+	if !reqDto.CreatedTo.IsZero() {
+		result["lte"] = reqDto.CreatedTo.Format(time.RFC3339)
+	}
+	return result
+}
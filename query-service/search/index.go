@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+)
+
+// Index is a full-text search engine the articles are projected into, next to
+// the MongoDB collection. Index and Delete are version guarded like the
+// projection, so an out-of-order event never overwrites a newer article.
+// Index rewrites an article at the version it holds, the comment count
+// changes without a new version of the article.
+type Index interface {
+	Index(ctx context.Context, article *model.Article) error
+	Delete(ctx context.Context, uuid string, version int) error
+	Search(ctx context.Context, reqDto dto.RequestListArticle) ([]*model.Article, int64, error)
+	Close() error
+}
+
+// highlightFields are the fields search hits are highlighted in.
+var highlightFields = []string{"title", "body"}
+
+// taxonomyFilter returns the tags an article must all have, the tags it must
+// have one of and its category, normalized like the tags the primary query
+// filters on.
+func taxonomyFilter(reqDto dto.RequestListArticle) ([]string, []string, string) {
+	return normalizedTags(append([]string{reqDto.Tag}, reqDto.TagsAll...)), normalizedTags(reqDto.TagsAny), normalizedTag(reqDto.Category)
+}
+
+func normalizedTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func normalizedTags(tags []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		if tag = normalizedTag(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// indexedTags stores an article without tags with an empty list rather than
+// null.
+func indexedTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
+
+// indexedStatus is the status of a search hit. Only published articles are
+// indexed, documents indexed before the status was stored have none.
+func indexedStatus(status string) string {
+	if status == "" {
+		return model.StatusPublished
+	}
+
+	return status
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/event"
+	"github.com/go-chi/chi/v5"
+)
+
+func (app *Config) GetDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 25
+	}
+
+	deadLetters, err := app.deadLetters.List(limit)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Successfully Get List of Dead Letters",
+		Data:    dto.DeadLettersToResponseDtos(deadLetters),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) GetDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	deadLetter, err := app.deadLetters.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		app.deadLetterError(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Successfully Get Dead Letter",
+		Data:    dto.DeadLetterToResponseDTO(deadLetter),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) RequeueDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	deadLetter, err := app.deadLetters.Requeue(chi.URLParam(r, "id"))
+	if err != nil {
+		app.deadLetterError(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Dead Letter Successfully Requeued",
+		Data:    dto.DeadLetterToResponseDTO(deadLetter),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) DeleteDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	err := app.deadLetters.Delete(chi.URLParam(r, "id"))
+	if err != nil {
+		app.deadLetterError(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Dead Letter Successfully Deleted",
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) PurgeDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	purged, err := app.deadLetters.Purge()
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: fmt.Sprintf("%d Dead Letters Successfully Purged", purged),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+// requireDeadLetters refuses the admin endpoints on transports without a
+// browsable dead-letter queue.
+func (app *Config) requireDeadLetters(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if app.deadLetters == nil {
+			app.errorJSON(w, fmt.Errorf("dead letters can't be browsed on the %s transport", app.eventTransport), http.StatusNotImplemented)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *Config) deadLetterError(w http.ResponseWriter, err error) {
+	if err == event.ErrDeadLetterNotFound {
+		app.errorJSON(w, err, http.StatusNotFound)
+		return
+	}
+
+	app.errorJSON(w, err)
+}
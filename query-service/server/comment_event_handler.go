@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Adhiana46/query-service/event"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/query"
+)
+
+func (app *Config) handleCommentCreated(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, comment, err := decodeCommentEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if comment.Uuid != "" {
+		if err := app.commentProjection.Created(ctx, comment); err != nil {
+			return err
+		}
+
+		if err := app.recountComments(ctx, comment.ArticleID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleCommentChanged applies an edit, a delete or a moderation, each event
+// carries the whole comment.
+func (app *Config) handleCommentChanged(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	envelope, comment, err := decodeCommentEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if comment.Uuid != "" {
+		applied, err := app.commentProjection.Updated(ctx, comment)
+		if err != nil {
+			return err
+		}
+
+		// the count is recounted even for a stale event, a retry repairs a
+		// count the first attempt left behind
+		if err := app.recountComments(ctx, comment.ArticleID); err != nil {
+			return err
+		}
+
+		if !applied {
+			log.Printf("Ignoring out-of-order %s %s for comment %s version %d", msg.EventType, envelope.EventID, comment.Uuid, comment.Version)
+		}
+	}
+
+	return nil
+}
+
+// decodeCommentEvent unwraps the comment snapshot carried by an event. The
+// aggregate version of the envelope orders the events of a comment.
+func decodeCommentEvent(msg *event.Message) (*event.Envelope, *model.Comment, error) {
+	envelope, err := event.DecodeEnvelope(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comment := model.Comment{}
+	if err := json.Unmarshal(envelope.Data, &comment); err != nil {
+		return nil, nil, err
+	}
+
+	if envelope.AggregateVersion != 0 {
+		comment.Version = envelope.AggregateVersion
+	}
+
+	return envelope, &comment, nil
+}
+
+// recountComments stores the comment count of the article, indexes it and
+// drops the cached lists that show it.
+func (app *Config) recountComments(ctx context.Context, articleID string) error {
+	article, err := app.commentProjection.Recount(ctx, articleID)
+	if err != nil {
+		return err
+	}
+
+	// the article is purged, its comments go with it
+	if article == nil {
+		return nil
+	}
+
+	if err := query.SetCommentCount(ctx, app.cache, article.Uuid, article.CommentCount); err != nil {
+		log.Println("Can't cache comment count:", err)
+	}
+
+	// a trashed article is kept out of the index
+	if article.DeletedAt == nil {
+		if err := app.indexArticle(ctx, article); err != nil {
+			return err
+		}
+	}
+
+	app.invalidateArticleLists(ctx, article.Author)
+
+	return nil
+}
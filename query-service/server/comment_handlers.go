@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetCommentsHandler pages through the threads of an article, the comments
+// of an article the caller can't read are as private as the article.
+func (app *Config) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page == 0 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit == 0 {
+		limit = 25
+	}
+
+	if !app.canSeeArticle(w, r) {
+		return
+	}
+
+	requestDto := dto.RequestListComment{
+		ArticleID: chi.URLParam(r, "uuid"),
+		Page:      page,
+		Limit:     limit,
+		Viewer:    viewerFromRequest(r),
+	}
+
+	comments, total, err := app.queryComment.GetList(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Succesfully Get List of Comments",
+		Data:    dto.CommentsToResponseListDTO(comments, total, requestDto),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,541 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/query-service/cache"
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/event"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/query"
+	"github.com/Adhiana46/query-service/search"
+)
+
+// testApp is the service wired to the in-memory transport, read model and
+// cache. Events are published in the envelopes command-service writes.
+type testApp struct {
+	app    *Config
+	server *httptest.Server
+	broker *event.MemoryBroker
+	events int
+}
+
+func newTestApp(t *testing.T, options ...func(*Config)) *testApp {
+	t.Helper()
+
+	broker := event.NewMemoryBroker()
+	app := NewMemory(broker, cache.NewCacheMemory())
+	for _, option := range options {
+		option(app)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		app.ListenEvents()
+		close(done)
+	}()
+
+	for deadline := time.Now().Add(time.Second); broker.Subscribed() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the event consumer did not subscribe")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	server := httptest.NewServer(app.Routes())
+	t.Cleanup(func() {
+		server.Close()
+		broker.Close()
+		<-done
+	})
+
+	return &testApp{
+		app:    app,
+		server: server,
+		broker: broker,
+	}
+}
+
+// publish sends the article event with the snapshot of the article after it
+// and waits until it is projected.
+func (a *testApp) publish(t *testing.T, eventType string, article model.Article) string {
+	t.Helper()
+
+	a.events++
+	eventID := fmt.Sprintf("event-%d", a.events)
+
+	a.redeliver(t, eventID, eventType, article)
+
+	return eventID
+}
+
+// redeliver sends an event again under the id it was first sent with.
+func (a *testApp) redeliver(t *testing.T, eventID string, eventType string, article model.Article) {
+	t.Helper()
+
+	a.deliver(t, eventID, eventType, "article", article.Uuid, article.Version, article)
+}
+
+// publishComment sends the comment event with the snapshot of the comment
+// after it and waits until it is projected.
+func (a *testApp) publishComment(t *testing.T, eventType string, comment model.Comment) {
+	t.Helper()
+
+	a.events++
+	a.deliver(t, fmt.Sprintf("event-%d", a.events), eventType, "comment", comment.Uuid, comment.Version, comment)
+}
+
+// deliver sends the envelope of an aggregate snapshot and waits until it is
+// handled.
+func (a *testApp) deliver(t *testing.T, eventID string, eventType string, aggregateType string, aggregateID string, version int, snapshot any) {
+	t.Helper()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	occurredAt := time.Now().UTC()
+	body, err := json.Marshal(event.Envelope{
+		EventID:          eventID,
+		EventType:        eventType,
+		AggregateID:      aggregateID,
+		AggregateType:    aggregateType,
+		AggregateVersion: version,
+		OccurredAt:       occurredAt,
+		Producer:         "command-service",
+		SchemaVersion:    1,
+		Data:             data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadLetters := len(a.broker.DeadLetters())
+
+	err = a.broker.Publish(&event.Message{
+		ID:          eventID,
+		EventType:   eventType,
+		Key:         aggregateID,
+		ContentType: "application/json",
+		Timestamp:   occurredAt,
+		Body:        body,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// handled once it is in the inbox
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		seen, err := a.app.inbox.Seen(context.Background(), eventID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen {
+			return
+		}
+		if len(a.broker.DeadLetters()) > deadLetters {
+			t.Fatalf("%s %s was dead-lettered", eventType, eventID)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s %s was not projected", eventType, eventID)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// get reads path as subject, anonymously when subject is empty, and decodes
+// the data of the response into data.
+func (a *testApp) get(t *testing.T, path string, subject string, data any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, a.server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "" {
+		req.Header.Set("X-Auth-Subject", subject)
+		req.Header.Set("X-Auth-Roles", "author")
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	payload := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode == http.StatusOK && data != nil {
+		if err := json.Unmarshal(payload.Data, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return res.StatusCode
+}
+
+func (a *testApp) getArticle(t *testing.T, uuid string, subject string) (*dto.ResponseArticle, int) {
+	t.Helper()
+
+	article := &dto.ResponseArticle{}
+	status := a.get(t, "/articles/"+uuid, subject, article)
+
+	return article, status
+}
+
+func (a *testApp) listArticles(t *testing.T, path string, subject string) []string {
+	t.Helper()
+
+	list := dto.ResponseListArticle{}
+	if status := a.get(t, path, subject, &list); status != http.StatusOK {
+		t.Fatalf("%s answered %d", path, status)
+	}
+
+	titles := []string{}
+	for _, article := range list.Articles {
+		titles = append(titles, article.Title)
+	}
+	return titles
+}
+
+// cached reports whether the single article cache holds the article.
+func (a *testApp) cached(t *testing.T, uuid string) *model.Article {
+	t.Helper()
+
+	value, err := a.app.cache.Get(context.Background(), "article-"+uuid)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	article := model.Article{}
+	if err := json.Unmarshal(value, &article); err != nil {
+		t.Fatal(err)
+	}
+	return &article
+}
+
+func testArticle(uuid string, title string) model.Article {
+	createdAt := time.Date(2022, 11, 5, 10, 0, 0, 0, time.UTC)
+
+	return model.Article{
+		Uuid:      uuid,
+		Slug:      uuid,
+		Author:    "alice",
+		Title:     title,
+		Body:      "Body of " + title,
+		Tags:      []string{"go"},
+		Status:    model.StatusPublished,
+		Version:   1,
+		Revision:  1,
+		UpdatedBy: "alice",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func TestCreatedArticleIsReadable(t *testing.T) {
+	app := newTestApp(t)
+
+	app.publish(t, articleCreatedEvent, testArticle("article-1", "First title"))
+
+	article, status := app.getArticle(t, "article-1", "")
+	if status != http.StatusOK || article.Title != "First title" || article.Version != 1 {
+		t.Fatalf("article answered %d with %+v, want version 1", status, article)
+	}
+
+	if titles := app.listArticles(t, "/articles", ""); len(titles) != 1 || titles[0] != "First title" {
+		t.Errorf("list = %v, want the created article", titles)
+	}
+
+	bySlug := &dto.ResponseArticle{}
+	if status := app.get(t, "/articles/by-slug/article-1", "", bySlug); status != http.StatusOK || bySlug.Uuid != "article-1" {
+		t.Errorf("by slug answered %d with %+v", status, bySlug)
+	}
+}
+
+func TestDraftIsOnlyReadableByItsAuthor(t *testing.T) {
+	app := newTestApp(t)
+
+	draft := testArticle("article-1", "Draft")
+	draft.Status = model.StatusDraft
+	app.publish(t, articleCreatedEvent, draft)
+
+	if _, status := app.getArticle(t, "article-1", ""); status == http.StatusOK {
+		t.Errorf("anonymous read of a draft answered %d", status)
+	}
+	if _, status := app.getArticle(t, "article-1", "alice"); status != http.StatusOK {
+		t.Errorf("author read of a draft answered %d, want 200", status)
+	}
+	if titles := app.listArticles(t, "/articles", ""); len(titles) != 0 {
+		t.Errorf("anonymous list = %v, want no drafts", titles)
+	}
+}
+
+func TestIdentityNeedsGatewaySecret(t *testing.T) {
+	app := newTestApp(t, func(app *Config) {
+		app.gatewaySecret = "gateway-secret"
+	})
+
+	draft := testArticle("article-1", "Draft")
+	draft.Status = model.StatusDraft
+	app.publish(t, articleCreatedEvent, draft)
+
+	// the identity headers of a caller bypassing the gateway are ignored
+	if _, status := app.getArticle(t, "article-1", "alice"); status == http.StatusOK {
+		t.Errorf("author read of a draft without the gateway secret answered %d", status)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, app.server.URL+"/articles/article-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Auth-Subject", "alice")
+	req.Header.Set("X-Gateway-Secret", "gateway-secret")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("author read of a draft with the gateway secret answered %d, want 200", res.StatusCode)
+	}
+}
+
+func TestUpdatesAreVersionGuarded(t *testing.T) {
+	app := newTestApp(t)
+
+	created := testArticle("article-1", "First title")
+	createdID := app.publish(t, articleCreatedEvent, created)
+
+	updated := created
+	updated.Title = "Third title"
+	updated.Version = 3
+	updated.Revision = 3
+	app.publish(t, articleUpdatedEvent, updated)
+
+	// delivered after version 3
+	stale := created
+	stale.Title = "Second title"
+	stale.Version = 2
+	stale.Revision = 2
+	app.publish(t, articleUpdatedEvent, stale)
+
+	// redelivered by the broker, and replayed once the inbox forgot it
+	app.redeliver(t, createdID, articleCreatedEvent, created)
+	app.publish(t, articleCreatedEvent, created)
+
+	// checked before a read, a read caches the article again
+	if cached := app.cached(t, "article-1"); cached == nil || cached.Version != 3 {
+		t.Errorf("cached %+v, want version 3", cached)
+	}
+
+	article, status := app.getArticle(t, "article-1", "")
+	if status != http.StatusOK || article.Title != "Third title" || article.Version != 3 {
+		t.Fatalf("article answered %d with %+v, want version 3", status, article)
+	}
+}
+
+func TestDeletedArticleMovesToTrash(t *testing.T) {
+	app := newTestApp(t)
+
+	article := testArticle("article-1", "First title")
+	app.publish(t, articleCreatedEvent, article)
+
+	// read once so it is cached
+	if _, status := app.getArticle(t, "article-1", ""); status != http.StatusOK {
+		t.Fatalf("article answered %d", status)
+	}
+	app.listArticles(t, "/articles", "")
+
+	deletedAt := time.Date(2022, 11, 6, 10, 0, 0, 0, time.UTC)
+	deleted := article
+	deleted.Version = 2
+	deleted.DeletedAt = &deletedAt
+	app.publish(t, articleDeletedEvent, deleted)
+
+	if cached := app.cached(t, "article-1"); cached != nil {
+		t.Errorf("cached %+v after delete, want nothing", cached)
+	}
+	if _, status := app.getArticle(t, "article-1", ""); status == http.StatusOK {
+		t.Errorf("deleted article answered %d", status)
+	}
+	if titles := app.listArticles(t, "/articles", ""); len(titles) != 0 {
+		t.Errorf("list = %v after delete, want nothing", titles)
+	}
+
+	if status := app.get(t, "/articles/trash", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("anonymous trash answered %d, want 401", status)
+	}
+	if titles := app.listArticles(t, "/articles/trash", "alice"); len(titles) != 1 || titles[0] != "First title" {
+		t.Errorf("trash = %v, want the deleted article", titles)
+	}
+	if titles := app.listArticles(t, "/articles/trash", "bob"); len(titles) != 0 {
+		t.Errorf("trash of another author = %v, want nothing", titles)
+	}
+
+	restored := article
+	restored.Version = 3
+	app.publish(t, articleRestoredEvent, restored)
+
+	if restored, status := app.getArticle(t, "article-1", ""); status != http.StatusOK || restored.Version != 3 {
+		t.Errorf("restored article answered %d with %+v, want version 3", status, restored)
+	}
+	if titles := app.listArticles(t, "/articles/trash", "alice"); len(titles) != 0 {
+		t.Errorf("trash = %v after restore, want nothing", titles)
+	}
+}
+
+func TestDeleteAheadOfCreateIsKept(t *testing.T) {
+	app := newTestApp(t)
+
+	// article.created failed once and is retried behind the delete
+	article := testArticle("article-1", "First title")
+	deletedAt := time.Date(2022, 11, 6, 10, 0, 0, 0, time.UTC)
+	deleted := article
+	deleted.Version = 2
+	deleted.DeletedAt = &deletedAt
+	app.publish(t, articleDeletedEvent, deleted)
+
+	app.publish(t, articleCreatedEvent, article)
+
+	if _, status := app.getArticle(t, "article-1", ""); status == http.StatusOK {
+		t.Errorf("deleted article answered %d", status)
+	}
+	if titles := app.listArticles(t, "/articles", ""); len(titles) != 0 {
+		t.Errorf("list = %v, want the delete kept", titles)
+	}
+	if titles := app.listArticles(t, "/articles/trash", "alice"); len(titles) != 1 || titles[0] != "First title" {
+		t.Errorf("trash = %v, want the deleted article", titles)
+	}
+}
+
+func TestSlugChangeAheadOfCreateIsKept(t *testing.T) {
+	app := newTestApp(t)
+
+	// article.created failed once and is retried behind the slug change
+	article := testArticle("article-1", "First title")
+	renamed := article
+	renamed.Version = 2
+	renamed.Slug = "second-title"
+	app.publish(t, articleSlugChangedEvent, renamed)
+
+	app.publish(t, articleCreatedEvent, article)
+
+	for _, slug := range []string{"second-title", "article-1"} {
+		bySlug := &dto.ResponseArticle{}
+		if status := app.get(t, "/articles/by-slug/"+slug, "", bySlug); status != http.StatusOK || bySlug.Uuid != "article-1" || bySlug.Slug != "second-title" {
+			t.Errorf("by slug %s answered %d with %+v, want the article at its new slug", slug, status, bySlug)
+		}
+	}
+}
+
+func TestCacheFollowsEvents(t *testing.T) {
+	app := newTestApp(t)
+
+	first := testArticle("article-1", "First title")
+	app.publish(t, articleCreatedEvent, first)
+
+	// the projection caches the article it applied
+	if cached := app.cached(t, "article-1"); cached == nil || cached.Title != "First title" {
+		t.Fatalf("cached %+v after create, want the created article", cached)
+	}
+
+	// fill the list caches
+	if titles := app.listArticles(t, "/articles", ""); len(titles) != 1 {
+		t.Fatalf("list = %v, want one article", titles)
+	}
+	if titles := app.listArticles(t, "/articles?author=alice", ""); len(titles) != 1 {
+		t.Fatalf("list of alice = %v, want one article", titles)
+	}
+	if titles := app.listArticles(t, "/articles?author=bob", ""); len(titles) != 0 {
+		t.Fatalf("list of bob = %v, want nothing", titles)
+	}
+
+	updated := first
+	updated.Title = "Second title"
+	updated.Version = 2
+	updated.Revision = 2
+	app.publish(t, articleUpdatedEvent, updated)
+
+	if article, _ := app.getArticle(t, "article-1", ""); article.Title != "Second title" {
+		t.Errorf("article = %+v after update, want the second title", article)
+	}
+	if titles := app.listArticles(t, "/articles", ""); len(titles) != 1 || titles[0] != "Second title" {
+		t.Errorf("list = %v after update, want the second title", titles)
+	}
+	if titles := app.listArticles(t, "/articles?author=alice", ""); len(titles) != 1 || titles[0] != "Second title" {
+		t.Errorf("list of alice = %v after update, want the second title", titles)
+	}
+
+	// the article moves to bob, both authors' lists change
+	moved := updated
+	moved.Author = "bob"
+	moved.Version = 3
+	moved.Revision = 3
+	app.publish(t, articleUpdatedEvent, moved)
+
+	if titles := app.listArticles(t, "/articles?author=alice", ""); len(titles) != 0 {
+		t.Errorf("list of alice = %v after the move, want nothing", titles)
+	}
+	if titles := app.listArticles(t, "/articles?author=bob", ""); len(titles) != 1 {
+		t.Errorf("list of bob = %v after the move, want the article", titles)
+	}
+
+	app.publish(t, articleCreatedEvent, testArticle("article-2", "Other title"))
+
+	if titles := app.listArticles(t, "/articles", ""); len(titles) != 2 {
+		t.Errorf("list = %v after a create, want both articles", titles)
+	}
+}
+
+func TestCommentsReindexTheArticle(t *testing.T) {
+	index, err := search.NewBleveIndex("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(t, func(app *Config) {
+		app.searchIndex = index
+		app.queryArticle = query.NewArticleQuerySearch(index, app.queryArticle)
+	})
+
+	app.publish(t, articleCreatedEvent, testArticle("article-1", "First title"))
+
+	createdAt := time.Date(2022, 11, 6, 10, 0, 0, 0, time.UTC)
+	app.publishComment(t, commentCreatedEvent, model.Comment{
+		Uuid:      "comment-1",
+		ArticleID: "article-1",
+		ThreadID:  "comment-1",
+		Author:    "bob",
+		Body:      "First comment",
+		Status:    model.CommentVisible,
+		Version:   1,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	})
+
+	// the list is served by the index
+	list := dto.ResponseListArticle{}
+	if status := app.get(t, "/articles", "", &list); status != http.StatusOK {
+		t.Fatalf("list answered %d", status)
+	}
+	if len(list.Articles) != 1 || list.Articles[0].CommentCount != 1 {
+		t.Errorf("list = %+v, want the article with its comment", list.Articles)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Adhiana46/query-service/event"
+	"github.com/Adhiana46/query-service/model"
+)
+
+const (
+	articleCreatedEvent  = "article.created"
+	articleUpdatedEvent  = "article.updated"
+	articleDeletedEvent  = "article.deleted"
+	articleRestoredEvent = "article.restored"
+	articlePurgedEvent   = "article.purged"
+
+	articleSubmittedEvent = "article.submitted"
+	articlePublishedEvent = "article.published"
+	articleArchivedEvent  = "article.archived"
+
+	articlePublishScheduledEvent = "article.publish_scheduled"
+	articlePublishCancelledEvent = "article.publish_cancelled"
+
+	articleSlugChangedEvent = "article.slug_changed"
+
+	commentCreatedEvent   = "comment.created"
+	commentEditedEvent    = "comment.edited"
+	commentDeletedEvent   = "comment.deleted"
+	commentModeratedEvent = "comment.moderated"
+)
+
+// consumedEvents are the events of the articles topic the read models are
+// projected from.
+var consumedEvents = []string{
+	articleCreatedEvent,
+	articleUpdatedEvent,
+	articleDeletedEvent,
+	articleRestoredEvent,
+	articlePurgedEvent,
+	articleSubmittedEvent,
+	articlePublishedEvent,
+	articleArchivedEvent,
+	articlePublishScheduledEvent,
+	articlePublishCancelledEvent,
+	articleSlugChangedEvent,
+	commentCreatedEvent,
+	commentEditedEvent,
+	commentDeletedEvent,
+	commentModeratedEvent,
+}
+
+// articleStatuses is the status each editorial workflow event moves to.
+var articleStatuses = map[string]string{
+	articleSubmittedEvent: model.StatusInReview,
+	articlePublishedEvent: model.StatusPublished,
+	articleArchivedEvent:  model.StatusArchived,
+}
+
+// ListenEvents projects the consumed events of the articles topic until the
+// transport is closed.
+func (app *Config) ListenEvents() {
+	app.listenEvents("articles", consumedEvents)
+}
+
+func (app *Config) listenEvents(topic string, events []string) {
+	// create consumer
+	var consumer event.Consumer
+	var err error
+
+	switch app.eventTransport {
+	case event.TransportKafka:
+		consumer, err = event.NewConsumerKafka(app.kafka, topic, event.DefaultRetryPolicy, app.handleEvent)
+	case event.TransportMemory:
+		consumer, err = event.NewConsumerMemory(app.memoryBroker, event.DefaultRetryPolicy, app.handleEvent)
+	default:
+		consumer, err = event.NewConsumerRabbitmq(app.rabbitConn, topic, event.DefaultRetryPolicy, app.handleEvent)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	// watch the topic and consume events
+	err = consumer.Listen(events)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (app *Config) handleEvent(msg *event.Message) error {
+	envelope, err := event.DecodeEnvelope(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// redeliveries of an already projected event are acknowledged as is
+	if envelope.EventID != "" {
+		seen, err := app.inbox.Seen(ctx, envelope.EventID)
+		if err != nil {
+			return err
+		}
+		if seen {
+			log.Printf("Skipping already processed %s %s", msg.EventType, envelope.EventID)
+			return nil
+		}
+	}
+
+	switch msg.EventType {
+	case articleCreatedEvent:
+		err = app.handleArticleCreated(msg)
+	case articleUpdatedEvent:
+		err = app.handleArticleUpdated(msg)
+	case articleDeletedEvent:
+		err = app.handleArticleDeleted(msg)
+	case articleRestoredEvent:
+		err = app.handleArticleRestored(msg)
+	case articlePurgedEvent:
+		err = app.handleArticlePurged(msg)
+	case articleSubmittedEvent, articlePublishedEvent, articleArchivedEvent:
+		err = app.handleArticleStatusChanged(msg)
+	case articlePublishScheduledEvent, articlePublishCancelledEvent:
+		err = app.handleArticleScheduleChanged(msg)
+	case articleSlugChangedEvent:
+		err = app.handleArticleSlugChanged(msg)
+	case commentCreatedEvent:
+		err = app.handleCommentCreated(msg)
+	case commentEditedEvent, commentDeletedEvent, commentModeratedEvent:
+		err = app.handleCommentChanged(msg)
+	}
+	if err != nil {
+		return err
+	}
+
+	if envelope.EventID == "" {
+		return nil
+	}
+
+	return app.inbox.MarkProcessed(ctx, &model.ProcessedEvent{
+		EventID:          envelope.EventID,
+		EventType:        envelope.EventType,
+		AggregateID:      envelope.AggregateID,
+		AggregateVersion: envelope.AggregateVersion,
+		ProcessedAt:      time.Now(),
+	})
+}
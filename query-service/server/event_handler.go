@@ -0,0 +1,432 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Adhiana46/query-service/event"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/query"
+)
+
+func (app *Config) handleArticleCreated(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	// articles created before the editorial workflow were published right away
+	if article.Status == "" {
+		article.Status = model.StatusPublished
+	}
+
+	if article.Uuid != "" {
+		// insert into collection
+		created, err := app.articleProjection.Created(ctx, article)
+		if err != nil {
+			return err
+		}
+
+		// a redelivered event must not overwrite the cache with its snapshot
+		if created {
+			cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+			app.setArticleCache(ctx, cacheKey, article)
+		}
+
+		if err := app.refreshFacets(ctx, article); err != nil {
+			return err
+		}
+
+		if err := app.recordRevision(ctx, article); err != nil {
+			return err
+		}
+
+		if err := app.indexArticle(ctx, article); err != nil {
+			return err
+		}
+
+		app.invalidateArticleLists(ctx, article.Author)
+	}
+
+	return nil
+}
+
+func (app *Config) handleArticleUpdated(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	envelope, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if article.Uuid != "" {
+		// update into collection, older versions are refused
+		previous, applied, err := app.articleProjection.Updated(ctx, article)
+		if err != nil {
+			return err
+		}
+
+		// the tags the update dropped are only known while it is applied
+		if err := app.refreshFacets(ctx, article, previous); err != nil {
+			return err
+		}
+
+		// the revision is history, it is kept even when the update is stale
+		if err := app.recordRevision(ctx, article); err != nil {
+			return err
+		}
+
+		// the index is guarded on its own, it may lag behind a retried event
+		if err := app.indexArticle(ctx, article); err != nil {
+			return err
+		}
+
+		if !applied {
+			log.Printf("Ignoring out-of-order %s %s for article %s version %d", msg.EventType, envelope.EventID, article.Uuid, article.Version)
+			return nil
+		}
+
+		// Set Cache
+		cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+		app.setArticleCache(ctx, cacheKey, article)
+
+		// the article may have moved to another author
+		authors := []string{article.Author}
+		if previous != nil && previous.Author != article.Author {
+			authors = append(authors, previous.Author)
+		}
+		app.invalidateArticleLists(ctx, authors...)
+	}
+
+	return nil
+}
+
+func (app *Config) handleArticleStatusChanged(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	envelope, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if article.Uuid != "" {
+		article.Status = articleStatuses[msg.EventType]
+
+		changed, err := app.articleProjection.StatusChanged(ctx, article, envelope.OccurredAt)
+		if err != nil {
+			return err
+		}
+
+		if err := app.refreshFacets(ctx, article); err != nil {
+			return err
+		}
+
+		// the index is guarded on its own, it may lag behind a retried event
+		if err := app.indexArticle(ctx, article); err != nil {
+			return err
+		}
+
+		if !changed {
+			log.Printf("Ignoring out-of-order %s %s for article %s version %d", msg.EventType, envelope.EventID, article.Uuid, article.Version)
+			return nil
+		}
+
+		// Set Cache
+		cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+		app.setArticleCache(ctx, cacheKey, article)
+
+		app.invalidateArticleLists(ctx, article.Author)
+	}
+
+	return nil
+}
+
+// handleArticleScheduleChanged keeps the scheduled publication of an
+// unpublished article, the article stays hidden until article.published.
+func (app *Config) handleArticleScheduleChanged(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	envelope, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if article.Uuid != "" {
+		if msg.EventType == articlePublishCancelledEvent {
+			article.PublishAt = nil
+		}
+
+		changed, err := app.articleProjection.ScheduleChanged(ctx, article, envelope.OccurredAt)
+		if err != nil {
+			return err
+		}
+
+		if !changed {
+			log.Printf("Ignoring out-of-order %s %s for article %s version %d", msg.EventType, envelope.EventID, article.Uuid, article.Version)
+			return nil
+		}
+
+		// Set Cache
+		cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+		app.setArticleCache(ctx, cacheKey, article)
+
+		app.invalidateArticleLists(ctx, article.Author)
+	}
+
+	return nil
+}
+
+// handleArticleSlugChanged moves the article to its new slug, the previous
+// ones keep resolving to it.
+func (app *Config) handleArticleSlugChanged(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	envelope, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if article.Uuid != "" {
+		changed, err := app.articleProjection.SlugChanged(ctx, article, envelope.OccurredAt)
+		if err != nil {
+			return err
+		}
+
+		// the index is guarded on its own, it may lag behind a retried event
+		if err := app.indexArticle(ctx, article); err != nil {
+			return err
+		}
+
+		if !changed {
+			log.Printf("Ignoring out-of-order %s %s for article %s version %d", msg.EventType, envelope.EventID, article.Uuid, article.Version)
+			return nil
+		}
+
+		// Set Cache
+		cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+		app.setArticleCache(ctx, cacheKey, article)
+
+		app.invalidateArticleLists(ctx, article.Author)
+	}
+
+	return nil
+}
+
+func (app *Config) handleArticleDeleted(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	envelope, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if article.Uuid != "" {
+		// Delete Cache
+		cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+		app.cache.Del(ctx, cacheKey)
+
+		// move to the trash, the document stays as a tombstone
+		deletedAt := envelope.OccurredAt
+		if article.DeletedAt != nil {
+			deletedAt = *article.DeletedAt
+		}
+		deleted, err := app.articleProjection.Deleted(ctx, article, deletedAt)
+		if err != nil {
+			return err
+		}
+
+		if err := app.refreshFacets(ctx, article); err != nil {
+			return err
+		}
+
+		if app.searchIndex != nil {
+			if err := app.searchIndex.Delete(ctx, article.Uuid, article.Version); err != nil {
+				return err
+			}
+		}
+
+		if deleted {
+			app.invalidateArticleLists(ctx, article.Author)
+		}
+	}
+
+	return nil
+}
+
+func (app *Config) handleArticleRestored(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	envelope, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if article.Uuid != "" {
+		restored, err := app.articleProjection.Restored(ctx, article, envelope.OccurredAt)
+		if err != nil {
+			return err
+		}
+
+		if err := app.refreshFacets(ctx, article); err != nil {
+			return err
+		}
+
+		// the index is guarded on its own, it may lag behind a retried event
+		if err := app.indexArticle(ctx, article); err != nil {
+			return err
+		}
+
+		if !restored {
+			log.Printf("Ignoring out-of-order %s %s for article %s version %d", msg.EventType, envelope.EventID, article.Uuid, article.Version)
+			return nil
+		}
+
+		// Set Cache
+		cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+		app.setArticleCache(ctx, cacheKey, article)
+
+		app.invalidateArticleLists(ctx, article.Author)
+	}
+
+	return nil
+}
+
+func (app *Config) handleArticlePurged(msg *event.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, article, err := decodeArticleEvent(msg)
+	if err != nil {
+		return event.Permanent(err)
+	}
+
+	if article.Uuid != "" {
+		// Delete Cache
+		cacheKey := fmt.Sprintf("article-%s", article.Uuid)
+		app.cache.Del(ctx, cacheKey)
+
+		// only trashed articles are purged, the cached lists don't hold them.
+		// A purge ahead of article.created fails until the article is there.
+		_, err := app.articleProjection.Purged(ctx, article.Uuid, article.Version)
+		if err != nil {
+			return err
+		}
+
+		// purging is final, a retry removes whatever history is left
+		if err := app.revisionProjection.Purged(ctx, article.Uuid); err != nil {
+			return err
+		}
+
+		if err := app.commentProjection.ArticlePurged(ctx, article.Uuid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeArticleEvent unwraps the article snapshot carried by an event. The
+// aggregate version of the envelope orders the events of an article.
+func decodeArticleEvent(msg *event.Message) (*event.Envelope, *model.Article, error) {
+	envelope, err := event.DecodeEnvelope(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	article := model.Article{}
+	if err := json.Unmarshal(envelope.Data, &article); err != nil {
+		return nil, nil, err
+	}
+
+	if envelope.AggregateVersion != 0 {
+		article.Version = envelope.AggregateVersion
+	}
+
+	return envelope, &article, nil
+}
+
+// recordRevision adds the snapshot to the revision history. Events published
+// before revisions were numbered carry none and are left out.
+func (app *Config) recordRevision(ctx context.Context, article *model.Article) error {
+	if article.Revision == 0 {
+		return nil
+	}
+
+	return app.revisionProjection.Recorded(ctx, model.RevisionOf(article))
+}
+
+func (app *Config) setArticleCache(ctx context.Context, cacheKey string, article *model.Article) {
+	articleJson, err := json.Marshal(article)
+	if err != nil {
+		return
+	}
+
+	app.cache.Set(ctx, cacheKey, articleJson, 10*time.Minute)
+}
+
+// indexArticle keeps the search index in step with the collection, it only
+// holds published articles. The index refuses versions older than the one it
+// holds.
+func (app *Config) indexArticle(ctx context.Context, article *model.Article) error {
+	if app.searchIndex == nil {
+		return nil
+	}
+
+	if !article.IsPublished() {
+		return app.searchIndex.Delete(ctx, article.Uuid, article.Version)
+	}
+
+	// the snapshot of an article event knows nothing of its comments
+	count, err := app.commentProjection.Count(ctx, article.Uuid)
+	if err != nil {
+		return err
+	}
+	article.CommentCount = count
+
+	return app.searchIndex.Index(ctx, article)
+}
+
+// refreshFacets recounts the tags and the category of the articles. The
+// counts are recounted even for a stale event, a retry repairs a count the
+// first attempt left behind.
+func (app *Config) refreshFacets(ctx context.Context, articles ...*model.Article) error {
+	tags := []string{}
+	categories := []string{}
+	for _, article := range articles {
+		if article == nil {
+			continue
+		}
+
+		tags = append(tags, article.Tags...)
+		if article.Category != "" {
+			categories = append(categories, article.Category)
+		}
+	}
+
+	if len(tags) == 0 && len(categories) == 0 {
+		return nil
+	}
+
+	return app.facetProjection.Refresh(ctx, tags, categories)
+}
+
+// invalidateArticleLists drops the cached lists the write can change. A
+// failure is only logged, the lists expire on their own.
+func (app *Config) invalidateArticleLists(ctx context.Context, authors ...string) {
+	err := query.InvalidateArticleLists(ctx, app.cache, authors...)
+	if err != nil {
+		log.Println("Can't invalidate article lists:", err)
+	}
+}
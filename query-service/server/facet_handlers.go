@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+)
+
+func (app *Config) GetTagsHandler(w http.ResponseWriter, r *http.Request) {
+	app.listFacets(w, r, app.queryFacet.Tags, "Succesfully Get List of Tags")
+}
+
+func (app *Config) GetCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	app.listFacets(w, r, app.queryFacet.Categories, "Succesfully Get List of Categories")
+}
+
+// listFacets serves the tag and category lists, they only differ in the
+// query they run.
+func (app *Config) listFacets(
+	w http.ResponseWriter,
+	r *http.Request,
+	list func(context.Context, dto.RequestListFacet) ([]*model.Facet, error),
+	message string,
+) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit == 0 {
+		limit = 100
+	}
+
+	facets, err := list(r.Context(), dto.RequestListFacet{Limit: limit})
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: message,
+		Data:    dto.FacetsToResponseDtos(facets),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
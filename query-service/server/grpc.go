@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/pb"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (app *Config) serveGRPC() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Starting %s gRPC server on port %s\n", appName, grpcPort)
+
+	return app.GRPCServer().Serve(listener)
+}
+
+// GRPCServer is the gRPC API of the service, the counterpart of Routes.
+func (app *Config) GRPCServer() *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(app.grpcTrustGateway))
+	pb.RegisterArticleQueryServer(server, &articleQueryServer{app: app})
+
+	return server
+}
+
+// grpcError is the gRPC counterpart of errorJSON. Validation errors carry
+// the failed fields as BadRequest details.
+func grpcError(err error) error {
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.As(err, &validationErrors):
+		st := status.New(codes.InvalidArgument, err.Error())
+
+		badRequest := &errdetails.BadRequest{}
+		for _, fieldErr := range validationErrors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldErr.Field(),
+				Description: validationDescription(fieldErr),
+			})
+		}
+
+		if detailed, detailErr := st.WithDetails(badRequest); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, "article not found")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// viewerFromMetadata is the gRPC counterpart of viewerFromRequest, the
+// gateway sends the verified caller as call metadata.
+func viewerFromMetadata(ctx context.Context) dto.Viewer {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	subject := firstMetadata(md, "x-auth-subject")
+	if subject == "" {
+		return dto.Viewer{}
+	}
+
+	editor := false
+	for _, role := range strings.Split(firstMetadata(md, "x-auth-roles"), ",") {
+		switch strings.TrimSpace(role) {
+		case "editor", "admin":
+			editor = true
+		}
+	}
+
+	return dto.Viewer{
+		Subject: subject,
+		Editor:  editor,
+	}
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func validationDescription(fieldErr validator.FieldError) string {
+	if fieldErr.Param() != "" {
+		return fmt.Sprintf("failed on the '%s=%s' rule", fieldErr.Tag(), fieldErr.Param())
+	}
+
+	return fmt.Sprintf("failed on the '%s' rule", fieldErr.Tag())
+}
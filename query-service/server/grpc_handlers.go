@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type articleQueryServer struct {
+	pb.UnimplementedArticleQueryServer
+
+	app *Config
+}
+
+func (s *articleQueryServer) GetSingle(ctx context.Context, req *pb.GetSingleArticleRequest) (*pb.GetSingleArticleResponse, error) {
+	requestDto := dto.RequestSingleArticle{
+		Uuid:   req.GetUuid(),
+		Viewer: viewerFromMetadata(ctx),
+	}
+
+	article, err := s.app.queryArticle.GetSingle(ctx, requestDto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.GetSingleArticleResponse{
+		Message: "Sucessfully Get Article",
+		Article: articleToProto(article),
+	}, nil
+}
+
+func (s *articleQueryServer) GetBySlug(ctx context.Context, req *pb.GetArticleBySlugRequest) (*pb.GetSingleArticleResponse, error) {
+	requestDto := dto.RequestArticleBySlug{
+		Slug:   req.GetSlug(),
+		Viewer: viewerFromMetadata(ctx),
+	}
+
+	article, err := s.app.queryArticle.GetBySlug(ctx, requestDto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.GetSingleArticleResponse{
+		Message: "Sucessfully Get Article",
+		Article: articleToProto(article),
+	}, nil
+}
+
+func (s *articleQueryServer) GetList(ctx context.Context, req *pb.GetListArticleRequest) (*pb.GetListArticleResponse, error) {
+	requestDto := dto.RequestListArticle{
+		Page:   int(req.GetPage()),
+		Limit:  int(req.GetLimit()),
+		Query:  req.GetQ(),
+		Author: req.GetAuthor(),
+		Status: req.GetStatus(),
+		Viewer: viewerFromMetadata(ctx),
+
+		Tag:      req.GetTag(),
+		TagsAll:  req.GetTagsAll(),
+		TagsAny:  req.GetTagsAny(),
+		Category: req.GetCategory(),
+	}
+	if req.GetCreatedFrom() != nil {
+		requestDto.CreatedFrom = req.GetCreatedFrom().AsTime()
+	}
+	if req.GetCreatedTo() != nil {
+		requestDto.CreatedTo = req.GetCreatedTo().AsTime()
+	}
+	if requestDto.Page == 0 {
+		requestDto.Page = 1
+	}
+	if requestDto.Limit == 0 {
+		requestDto.Limit = 25
+	}
+
+	articles, total, err := s.app.queryArticle.GetList(ctx, requestDto)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	list := dto.ArticlesToResponseListDTO(articles, total, requestDto)
+
+	resp := &pb.GetListArticleResponse{
+		Message:    "Succesfully Get List of Articles",
+		Articles:   make([]*pb.Article, 0, len(articles)),
+		Total:      list.Total,
+		Page:       int32(list.Page),
+		Limit:      int32(list.Limit),
+		TotalPages: int32(list.TotalPages),
+	}
+	for _, article := range articles {
+		resp.Articles = append(resp.Articles, articleToProto(article))
+	}
+
+	return resp, nil
+}
+
+func articleToProto(article *model.Article) *pb.Article {
+	result := &pb.Article{
+		Uuid:      article.Uuid,
+		Slug:      article.Slug,
+		Author:    article.Author,
+		Title:     article.Title,
+		Body:      article.Body,
+		Tags:      article.Tags,
+		Category:  article.Category,
+		Status:    article.Status,
+		Version:   int32(article.Version),
+		CreatedAt: timestamppb.New(article.CreatedAt),
+		UpdatedAt: timestamppb.New(article.UpdatedAt),
+
+		CommentCount: article.CommentCount,
+	}
+
+	if article.PublishAt != nil {
+		result.PublishAt = timestamppb.New(*article.PublishAt)
+	}
+
+	if len(article.Highlights) > 0 {
+		result.Highlights = map[string]*pb.Highlight{}
+		for field, fragments := range article.Highlights {
+			result.Highlights[field] = &pb.Highlight{Fragments: fragments}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/event"
+	"github.com/go-chi/chi/v5"
+)
+
+func (app *Config) GetArticlesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page == 0 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit == 0 {
+		limit = 25
+	}
+
+	q := r.URL.Query().Get("q")
+	author := r.URL.Query().Get("author")
+
+	createdFrom, err := readTimeParam(r, "created_from", false)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	createdTo, err := readTimeParam(r, "created_to", true)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	requestDto := dto.RequestListArticle{
+		Page:        page,
+		Limit:       limit,
+		Query:       q,
+		Author:      author,
+		Status:      r.URL.Query().Get("status"),
+		Viewer:      viewerFromRequest(r),
+		Tag:         r.URL.Query().Get("tag"),
+		TagsAll:     splitList(r.URL.Query().Get("tags_all")),
+		TagsAny:     splitList(r.URL.Query().Get("tags_any")),
+		Category:    r.URL.Query().Get("category"),
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
+
+	articles, total, err := app.queryArticle.GetList(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Succesfully Get List of Articles",
+		Data:    dto.ArticlesToResponseListDTO(articles, total, requestDto),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+// GetTrashHandler lists the deleted articles. Editors see the whole trash,
+// everybody else only their own articles.
+func (app *Config) GetTrashHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subject, editor := callerFromRequest(r)
+	if subject == "" {
+		app.errorJSON(w, errors.New("authentication required"), http.StatusUnauthorized)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page == 0 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit == 0 {
+		limit = 25
+	}
+
+	author := subject
+	if editor {
+		author = r.URL.Query().Get("author")
+	}
+
+	requestDto := dto.RequestListArticle{
+		Page:   page,
+		Limit:  limit,
+		Query:  r.URL.Query().Get("q"),
+		Author: author,
+		Status: r.URL.Query().Get("status"),
+		Viewer: viewerFromRequest(r),
+	}
+
+	articles, total, err := app.queryArticle.GetTrash(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Succesfully Get List of Deleted Articles",
+		Data:    dto.ArticlesToResponseListDTO(articles, total, requestDto),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) GetSingleArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	requestDto := dto.RequestSingleArticle{
+		Uuid:   uuid,
+		Viewer: viewerFromRequest(r),
+	}
+
+	article, err := app.queryArticle.GetSingle(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Sucessfully Get Article",
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {fmt.Sprintf("\"%d\"", article.Version)}})
+}
+
+// GetArticleBySlugHandler answers for the current and the retired slugs, the
+// article carries its current slug.
+func (app *Config) GetArticleBySlugHandler(w http.ResponseWriter, r *http.Request) {
+	requestDto := dto.RequestArticleBySlug{
+		Slug:   chi.URLParam(r, "slug"),
+		Viewer: viewerFromRequest(r),
+	}
+
+	article, err := app.queryArticle.GetBySlug(r.Context(), requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Sucessfully Get Article",
+		Data:    dto.ArticleToResponseDTO(article),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp, http.Header{"ETag": {fmt.Sprintf("\"%d\"", article.Version)}})
+}
+
+func (app *Config) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	checks := map[string]string{
+		"mongodb": "up",
+	}
+
+	switch app.eventTransport {
+	case event.TransportKafka:
+		checks["kafka"] = "up"
+		if err := event.PingKafka(r.Context(), app.kafka.Brokers); err != nil {
+			checks["kafka"] = "down"
+			status = http.StatusServiceUnavailable
+		}
+	case event.TransportMemory:
+		checks["events"] = "memory"
+	default:
+		checks["rabbitmq"] = string(app.rabbitConn.State())
+		if !app.rabbitConn.IsConnected() {
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	if err := app.mongoDb.Ping(r.Context(), nil); err != nil {
+		checks["mongodb"] = "down"
+		status = http.StatusServiceUnavailable
+	}
+
+	resp := jsonResponse{
+		Error:   status != http.StatusOK,
+		Message: http.StatusText(status),
+		Data:    checks,
+	}
+
+	app.writeJSON(w, status, resp)
+}
@@ -1,12 +1,16 @@
-package main
+package server
 
 import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/Adhiana46/query-service/dto"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -81,3 +85,51 @@ func (app *Config) errorJSON(w http.ResponseWriter, err error, status ...int) er
 
 	return app.writeJSON(w, statusCode, payload)
 }
+
+// readTimeParam parses an RFC 3339 time or a plain date from the query
+// string. A plain date ends the day when it closes a range.
+func readTimeParam(r *http.Request, name string, endOfDay bool) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date or an RFC 3339 time", name)
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return parsed, nil
+}
+
+// callerFromRequest reads the caller rest-gateway forwards on authenticated
+// routes. editor reports whether it holds the editor or admin role.
+func callerFromRequest(r *http.Request) (string, bool) {
+	editor := false
+	for _, role := range strings.Split(r.Header.Get("X-Auth-Roles"), ",") {
+		switch strings.TrimSpace(role) {
+		case "editor", "admin":
+			editor = true
+		}
+	}
+
+	return r.Header.Get("X-Auth-Subject"), editor
+}
+
+// viewerFromRequest is the caller a read is made for, anonymous when the
+// gateway forwarded nobody.
+func viewerFromRequest(r *http.Request) dto.Viewer {
+	subject, editor := callerFromRequest(r)
+
+	return dto.Viewer{
+		Subject: subject,
+		Editor:  subject != "" && editor,
+	}
+}
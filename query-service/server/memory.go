@@ -0,0 +1,35 @@
+package server
+
+import (
+	"github.com/Adhiana46/query-service/cache"
+	"github.com/Adhiana46/query-service/event"
+	"github.com/Adhiana46/query-service/projection"
+	"github.com/Adhiana46/query-service/query"
+)
+
+// NewMemory wires the service to the in-memory read models and inbox, the
+// given broker and cache, the way Run wires the Mongo, broker and Redis ones.
+// It runs the service in-process without any infrastructure.
+func NewMemory(broker *event.MemoryBroker, c cache.Cache) *Config {
+	articles := projection.NewArticleProjectionMemory()
+	revisions := projection.NewRevisionProjectionMemory()
+	facets := projection.NewFacetProjectionMemory(articles)
+	comments := projection.NewCommentProjectionMemory(articles)
+
+	return &Config{
+		AppName:            appName,
+		AppVersion:         appVersion,
+		eventTransport:     event.TransportMemory,
+		memoryBroker:       broker,
+		cache:              c,
+		queryArticle:       query.NewArticleQueryCache(c, query.NewArticleQueryMemory(articles)),
+		queryRevision:      query.NewRevisionQueryMemory(revisions),
+		queryFacet:         query.NewFacetQueryMemory(facets),
+		queryComment:       query.NewCommentQueryMemory(comments),
+		articleProjection:  articles,
+		revisionProjection: revisions,
+		facetProjection:    facets,
+		commentProjection:  comments,
+		inbox:              projection.NewInboxMemory(),
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// trustsGateway reports whether sent matches the secret shared with
+// rest-gateway. Without a secret every caller is trusted and the service
+// relies on only being reachable through the gateway.
+func (app *Config) trustsGateway(sent string) bool {
+	if app.gatewaySecret == "" {
+		return true
+	}
+
+	return subtle.ConstantTimeCompare([]byte(app.gatewaySecret), []byte(sent)) == 1
+}
+
+// trustGateway drops the identity headers of a request without the gateway
+// secret, the read is then made for an anonymous caller.
+func (app *Config) trustGateway(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !app.trustsGateway(r.Header.Get("X-Gateway-Secret")) {
+			r.Header.Del("X-Auth-Subject")
+			r.Header.Del("X-Auth-Roles")
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// grpcTrustGateway is the gRPC counterpart of trustGateway.
+func (app *Config) grpcTrustGateway(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if !app.trustsGateway(firstMetadata(md, "x-gateway-secret")) {
+		md = md.Copy()
+		md.Delete("x-auth-subject")
+		md.Delete("x-auth-roles")
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+
+	return handler(ctx, req)
+}
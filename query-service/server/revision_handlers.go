@@ -0,0 +1,188 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Adhiana46/query-service/dto"
+	"github.com/Adhiana46/query-service/model"
+	"github.com/Adhiana46/query-service/query"
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (app *Config) GetRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page == 0 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit == 0 {
+		limit = 25
+	}
+
+	if !app.canSeeArticle(w, r) {
+		return
+	}
+
+	requestDto := dto.RequestListRevision{
+		Uuid:  chi.URLParam(r, "uuid"),
+		Page:  page,
+		Limit: limit,
+	}
+
+	revisions, total, err := app.queryRevision.GetList(ctx, requestDto)
+	if err != nil {
+		app.errorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Succesfully Get List of Revisions",
+		Data:    dto.RevisionsToResponseListDTO(revisions, total, requestDto),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+func (app *Config) GetRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !app.canSeeArticle(w, r) {
+		return
+	}
+
+	revisionNumber, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil {
+		app.errorJSON(w, errors.New("revision must be a number"), http.StatusBadRequest)
+		return
+	}
+
+	revision, err := app.queryRevision.GetSingle(ctx, dto.RequestSingleRevision{
+		Uuid:     chi.URLParam(r, "uuid"),
+		Revision: revisionNumber,
+	})
+	if err != nil {
+		app.revisionErrorJSON(w, err)
+		return
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Sucessfully Get Revision",
+		Data:    dto.RevisionToResponseDTO(revision),
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+// GetRevisionDiffHandler diffs two revisions of an article. to defaults to
+// the latest revision and from to the one before it, from=0 diffs against
+// the empty article.
+func (app *Config) GetRevisionDiffHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	uuid := chi.URLParam(r, "uuid")
+
+	if !app.canSeeArticle(w, r) {
+		return
+	}
+
+	from, err := readRevisionParam(r, "from")
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	to, err := readRevisionParam(r, "to")
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var toRevision *model.Revision
+	if to < 0 {
+		toRevision, err = app.queryRevision.GetLatest(ctx, uuid)
+	} else {
+		toRevision, err = app.queryRevision.GetSingle(ctx, dto.RequestSingleRevision{Uuid: uuid, Revision: to})
+	}
+	if err != nil {
+		app.revisionErrorJSON(w, err)
+		return
+	}
+
+	if from < 0 {
+		from = toRevision.Revision - 1
+	}
+
+	var fromRevision *model.Revision
+	if from > 0 {
+		fromRevision, err = app.queryRevision.GetSingle(ctx, dto.RequestSingleRevision{Uuid: uuid, Revision: from})
+		if err != nil {
+			app.revisionErrorJSON(w, err)
+			return
+		}
+	}
+
+	resp := jsonResponse{
+		Error:   false,
+		Message: "Sucessfully Get Revision Diff",
+		Data: dto.ResponseDiff{
+			Uuid: uuid,
+			From: from,
+			To:   toRevision.Revision,
+			Diff: query.DiffRevisions(fromRevision, toRevision),
+		},
+	}
+
+	app.writeJSON(w, http.StatusOK, resp)
+}
+
+// canSeeArticle writes a 404 unless the caller may read the article, the
+// history of an unpublished article is as private as the article itself.
+func (app *Config) canSeeArticle(w http.ResponseWriter, r *http.Request) bool {
+	_, err := app.queryArticle.GetSingle(r.Context(), dto.RequestSingleArticle{
+		Uuid:   chi.URLParam(r, "uuid"),
+		Viewer: viewerFromRequest(r),
+	})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			app.errorJSON(w, errors.New("article not found"), http.StatusNotFound)
+		} else {
+			app.errorJSON(w, err)
+		}
+		return false
+	}
+
+	return true
+}
+
+// readRevisionParam parses a revision number from the query string, it
+// returns -1 when the parameter is missing.
+func readRevisionParam(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return -1, nil
+	}
+
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision < 0 {
+		return 0, fmt.Errorf("%s must be a revision number", name)
+	}
+
+	return revision, nil
+}
+
+func (app *Config) revisionErrorJSON(w http.ResponseWriter, err error) {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		app.errorJSON(w, errors.New("revision not found"), http.StatusNotFound)
+		return
+	}
+
+	app.errorJSON(w, err)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+)
+
+func (app *Config) Routes() http.Handler {
+	mux := chi.NewRouter()
+
+	mux.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   []string{"https://*", "http://*"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"Link", "ETag"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}))
+
+	mux.Use(middleware.Heartbeat("/ping"))
+	mux.Use(app.trustGateway)
+
+	mux.Get("/health", app.HealthHandler)
+
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		payload := jsonResponse{
+			Error:   false,
+			Message: fmt.Sprintf("Welcome to %s version %s", app.AppName, app.AppVersion),
+		}
+
+		_ = app.writeJSON(w, http.StatusOK, payload)
+	})
+
+	mux.Route("/articles", func(r chi.Router) {
+		r.Get("/", app.GetArticlesHandler)
+		r.Get("/trash", app.GetTrashHandler)
+		r.Get("/by-slug/{slug}", app.GetArticleBySlugHandler)
+		r.Get("/{uuid}", app.GetSingleArticleHandler)
+		r.Get("/{uuid}/revisions", app.GetRevisionsHandler)
+		r.Get("/{uuid}/revisions/{revision}", app.GetRevisionHandler)
+		r.Get("/{uuid}/diff", app.GetRevisionDiffHandler)
+		r.Get("/{uuid}/comments", app.GetCommentsHandler)
+	})
+
+	// Tag and category facets
+	mux.Get("/tags", app.GetTagsHandler)
+	mux.Get("/categories", app.GetCategoriesHandler)
+
+	// Dead-lettered events
+	mux.Route("/admin/dead-letters", func(r chi.Router) {
+		r.Use(app.requireDeadLetters)
+		r.Get("/", app.GetDeadLettersHandler)
+		r.Delete("/", app.PurgeDeadLettersHandler)
+		r.Get("/{id}", app.GetDeadLetterHandler)
+		r.Delete("/{id}", app.DeleteDeadLetterHandler)
+		r.Post("/{id}/requeue", app.RequeueDeadLetterHandler)
+	})
+
+	return mux
+}
@@ -0,0 +1,361 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/query-service/cache"
+	"github.com/Adhiana46/query-service/event"
+	"github.com/Adhiana46/query-service/projection"
+	"github.com/Adhiana46/query-service/query"
+	"github.com/Adhiana46/query-service/search"
+	"github.com/go-redis/redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	appName    = "Query Service"
+	appVersion = "1.0"
+	port       = "80"
+	grpcPort   = "50051"
+
+	inboxRetention = 30 * 24 * time.Hour
+)
+
+type Config struct {
+	AppName    string
+	AppVersion string
+
+	mongoDb        *mongo.Client
+	eventTransport string
+	rabbitConn     *event.Connection
+	kafka          event.KafkaConfig
+	memoryBroker   *event.MemoryBroker
+	rds            *redis.Client
+	cache          cache.Cache
+
+	queryArticle       query.ArticleQuery
+	queryRevision      query.RevisionQuery
+	queryFacet         query.FacetQuery
+	queryComment       query.CommentQuery
+	articleProjection  projection.ArticleProjection
+	revisionProjection projection.RevisionProjection
+	facetProjection    projection.FacetProjection
+	commentProjection  projection.CommentProjection
+	inbox              projection.Inbox
+	searchIndex        search.Index
+	deadLetters        *event.DeadLetters
+	gatewaySecret      string
+}
+
+// Run connects the service to MongoDB, the event transport, Redis and the
+// search index, projects the consumed events and serves the HTTP and gRPC
+// APIs until the HTTP server fails.
+func Run() {
+	app := Config{
+		AppName:       appName,
+		AppVersion:    appVersion,
+		gatewaySecret: os.Getenv("GATEWAY_SECRET"),
+	}
+
+	// open mongodb
+	err := app.openMongodb()
+	if err != nil {
+		log.Panicf("Can't open MongoDB connection: %s", err)
+	}
+	defer app.closeMongodb()
+
+	err = query.EnsureArticleIndexes(context.Background(), app.mongoDb.Database("articles").Collection("articles"))
+	if err != nil {
+		log.Panicf("Can't create MongoDB indexes: %s", err)
+	}
+
+	err = projection.EnsureRevisionIndexes(context.Background(), app.mongoDb.Database("articles").Collection("revisions"))
+	if err != nil {
+		log.Panicf("Can't create MongoDB indexes: %s", err)
+	}
+
+	err = projection.EnsureCommentIndexes(context.Background(), app.mongoDb.Database("articles").Collection("comments"))
+	if err != nil {
+		log.Panicf("Can't create MongoDB indexes: %s", err)
+	}
+
+	for _, name := range []string{"tags", "categories"} {
+		err = projection.EnsureFacetIndexes(context.Background(), app.mongoDb.Database("articles").Collection(name))
+		if err != nil {
+			log.Panicf("Can't create MongoDB indexes: %s", err)
+		}
+	}
+
+	// open the event transport
+	err = app.openEvents()
+	if err != nil {
+		log.Panicf("Can't open %s event transport: %s", app.eventTransport, err)
+	}
+	defer app.closeEvents()
+
+	// open redis
+	err = app.openRedis()
+	if err != nil {
+		log.Panicf("Can't open Redis connection: %s", err)
+	}
+	defer app.closeRedis()
+
+	// open the search index, when one is configured
+	err = app.openSearch()
+	if err != nil {
+		log.Panicf("Can't open search index: %s", err)
+	}
+	defer app.closeSearch()
+
+	app.registerQuery()
+
+	err = projection.EnsureInboxIndexes(context.Background(), app.mongoDb.Database("articles").Collection("processed_events"), inboxRetention)
+	if err != nil {
+		log.Panicf("Can't create MongoDB indexes: %s", err)
+	}
+
+	log.Printf("Starting %s service on port %s\n", appName, port)
+
+	s := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: app.Routes(),
+	}
+
+	// listening for events
+	go app.ListenEvents()
+
+	go func() {
+		if err := app.serveGRPC(); err != nil {
+			log.Panic(err)
+		}
+	}()
+
+	// starting the server
+	if err := s.ListenAndServe(); err != nil {
+		log.Panic(err)
+	}
+}
+
+func (app *Config) registerQuery() {
+	app.cache = cache.NewCacheRedis(app.rds)
+	app.queryArticle = query.NewArticleQueryCache(app.cache, query.NewArticleQueryMongo(app.mongoDb))
+	if app.searchIndex != nil {
+		app.queryArticle = query.NewArticleQuerySearch(app.searchIndex, app.queryArticle)
+	}
+	app.queryRevision = query.NewRevisionQueryMongo(app.mongoDb.Database("articles").Collection("revisions"))
+	app.articleProjection = projection.NewArticleProjectionMongo(app.mongoDb.Database("articles").Collection("articles"))
+	app.revisionProjection = projection.NewRevisionProjectionMongo(app.mongoDb.Database("articles").Collection("revisions"))
+	app.queryFacet = query.NewFacetQueryMongo(
+		app.mongoDb.Database("articles").Collection("tags"),
+		app.mongoDb.Database("articles").Collection("categories"),
+	)
+	app.facetProjection = projection.NewFacetProjectionMongo(
+		app.mongoDb.Database("articles").Collection("articles"),
+		app.mongoDb.Database("articles").Collection("tags"),
+		app.mongoDb.Database("articles").Collection("categories"),
+	)
+	app.queryComment = query.NewCommentQueryMongo(app.mongoDb.Database("articles").Collection("comments"))
+	app.commentProjection = projection.NewCommentProjectionMongo(
+		app.mongoDb.Database("articles").Collection("comments"),
+		app.mongoDb.Database("articles").Collection("articles"),
+	)
+	app.inbox = projection.NewInboxMongo(app.mongoDb.Database("articles").Collection("processed_events"))
+
+	// the dead-letter queue can only be browsed on RabbitMQ
+	if app.rabbitConn != nil {
+		deadLetters := event.NewDeadLetters(app.rabbitConn, "articles")
+		app.deadLetters = &deadLetters
+	}
+}
+
+// Mongodb
+func (app *Config) openMongodb() error {
+	mongoURL := os.Getenv("MONGO_URL")
+	username := os.Getenv("MONGO_USERNAME")
+	password := os.Getenv("MONGO_PASSWORD")
+
+	var count int64
+	var retryTime = 1 * time.Second
+
+	for {
+		clientOptions := options.Client().ApplyURI(mongoURL)
+		clientOptions.SetAuth(options.Credential{
+			Username: username,
+			Password: password,
+		})
+
+		c, err := mongo.Connect(context.TODO(), clientOptions)
+
+		if err != nil {
+			log.Println("MongoDB not yet ready...", err)
+			count++
+		} else {
+			log.Println("Connected to MongoDB...")
+			app.mongoDb = c
+			break
+		}
+
+		if count > 5 {
+			log.Println("Could not connect to MongoDB", err)
+			return err
+		}
+
+		retryTime = time.Duration(math.Pow(float64(count), 2)) * time.Second
+		log.Println("Retrying in", retryTime)
+		time.Sleep(retryTime)
+		continue
+	}
+
+	return nil
+}
+
+func (app *Config) closeMongodb() {
+	//
+}
+
+// Search, SEARCH_BACKEND is one of mongo (default), elasticsearch or bleve
+func (app *Config) openSearch() error {
+	switch backend := os.Getenv("SEARCH_BACKEND"); backend {
+	case "", "mongo":
+		return nil
+	case "bleve":
+		index, err := search.NewBleveIndex(os.Getenv("BLEVE_PATH"))
+		if err != nil {
+			return err
+		}
+
+		log.Println("Opened bleve index")
+		app.searchIndex = index
+		return nil
+	case "elasticsearch", "opensearch":
+		var count int64
+		var retryTime = 1 * time.Second
+
+		for {
+			index, err := search.NewElasticsearchIndex(context.Background(), os.Getenv("ELASTICSEARCH_URL"), os.Getenv("ELASTICSEARCH_INDEX"))
+			if err == nil {
+				log.Println("Connected to Elasticsearch...")
+				app.searchIndex = index
+				return nil
+			}
+
+			log.Println("Elasticsearch not yet ready...", err)
+			count++
+
+			if count > 5 {
+				log.Println("Could not connect to Elasticsearch", err)
+				return err
+			}
+
+			retryTime = time.Duration(math.Pow(float64(count), 2)) * time.Second
+			log.Println("Retrying in", retryTime)
+			time.Sleep(retryTime)
+		}
+	default:
+		return fmt.Errorf("unknown SEARCH_BACKEND %q", backend)
+	}
+}
+
+func (app *Config) closeSearch() {
+	if app.searchIndex != nil {
+		app.searchIndex.Close()
+	}
+}
+
+// Events, EVENT_TRANSPORT is one of rabbitmq (default), kafka or memory
+func (app *Config) openEvents() error {
+	app.eventTransport = os.Getenv("EVENT_TRANSPORT")
+	if app.eventTransport == "" {
+		app.eventTransport = event.TransportRabbitmq
+	}
+
+	switch app.eventTransport {
+	case event.TransportRabbitmq:
+		return app.openRabbitmq()
+	case event.TransportKafka:
+		app.kafka = event.KafkaConfig{
+			Brokers:     splitList(os.Getenv("KAFKA_BROKERS")),
+			TopicPrefix: os.Getenv("KAFKA_TOPIC_PREFIX"),
+			GroupID:     os.Getenv("KAFKA_GROUP_ID"),
+		}
+		if app.kafka.GroupID == "" {
+			app.kafka.GroupID = "query-service"
+		}
+		return nil
+	case event.TransportMemory:
+		app.memoryBroker = event.NewMemoryBroker()
+		return nil
+	default:
+		return fmt.Errorf("unknown EVENT_TRANSPORT %q", app.eventTransport)
+	}
+}
+
+func (app *Config) closeEvents() {
+	if app.rabbitConn != nil {
+		app.closeRabbitmq()
+	}
+	if app.memoryBroker != nil {
+		app.memoryBroker.Close()
+	}
+}
+
+// Rabbitmq
+func (app *Config) openRabbitmq() error {
+	dsn := fmt.Sprintf(
+		"amqp://%s:%s@%s:%s/",
+		os.Getenv("AMQP_USER"),
+		os.Getenv("AMQP_PASSWORD"),
+		os.Getenv("AMQP_HOST"),
+		os.Getenv("AMQP_PORT"),
+	)
+
+	// Don't continue until rabbit is ready, reconnects are handled by event.Connection
+	connection, err := event.Dial(dsn)
+	if err != nil {
+		return err
+	}
+
+	app.rabbitConn = connection
+
+	return nil
+}
+
+func (app *Config) closeRabbitmq() {
+	app.rabbitConn.Close()
+}
+
+// Redis
+func (app *Config) openRedis() error {
+	app.rds = redis.NewClient(&redis.Options{
+		Addr:        fmt.Sprintf("%v:%v", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+		Password:    os.Getenv("REDIS_PASSWORD"),
+		DB:          0, // use default DB
+		ReadTimeout: -1,
+	})
+
+	log.Println("Connected to Redis")
+
+	return nil
+}
+
+func (app *Config) closeRedis() {
+	app.rds.Close()
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var testSecret = []byte("test-secret")
+
+func testClaims(expiresIn time.Duration) Claims {
+	now := time.Now()
+
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "alice",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		},
+		Roles: []string{"author"},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// writeJWKS writes the public keys by key id into a JWKS file.
+func writeJWKS(t *testing.T, keys map[string]*rsa.PublicKey) string {
+	t.Helper()
+
+	set := jsonWebKeySet{}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier, err := NewVerifier(Options{HMACSecret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, "", testSecret, testClaims(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "alice" || !claims.HasRole("author") {
+		t.Errorf("claims = %+v, want alice as author", claims)
+	}
+
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, "", []byte("other-secret"), testClaims(time.Hour))); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token signed with another secret: err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyRS256PicksKeyByKid(t *testing.T) {
+	first := generateKey(t)
+	second := generateKey(t)
+
+	verifier, err := NewVerifier(Options{
+		JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{
+			"key-1": &first.PublicKey,
+			"key-2": &second.PublicKey,
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, "key-2", second, testClaims(time.Hour))); err != nil {
+		t.Errorf("token of key-2: %v", err)
+	}
+
+	// the kid picks the key, a token signed with another key doesn't verify
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, "key-1", second, testClaims(time.Hour))); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token of key-2 sent as key-1: err = %v, want %v", err, ErrInvalidToken)
+	}
+
+	// with several keys the kid can't be left out
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, "", first, testClaims(time.Hour))); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token without kid: err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyRS256SingleKeyWithoutKid(t *testing.T) {
+	key := generateKey(t)
+
+	verifier, err := NewVerifier(Options{
+		JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{"key-1": &key.PublicKey}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, "", key, testClaims(time.Hour))); err != nil {
+		t.Errorf("token without kid: %v", err)
+	}
+}
+
+func TestVerifyRejectsUnknownKid(t *testing.T) {
+	key := generateKey(t)
+
+	verifier, err := NewVerifier(Options{
+		JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{"key-1": &key.PublicKey}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, "key-2", key, testClaims(time.Hour))); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token of an unknown kid: err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyRejectsExpiredToken(t *testing.T) {
+	verifier, err := NewVerifier(Options{HMACSecret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, "", testSecret, testClaims(-time.Minute))); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expired token: err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyRejectsUnexpectedAlg(t *testing.T) {
+	key := generateKey(t)
+
+	verifier, err := NewVerifier(Options{
+		HMACSecret: testSecret,
+		JWKSFile:   writeJWKS(t, map[string]*rsa.PublicKey{"key-1": &key.PublicKey}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := map[string]string{
+		"HS384": sign(t, jwt.SigningMethodHS384, "", testSecret, testClaims(time.Hour)),
+		"RS512": sign(t, jwt.SigningMethodRS512, "key-1", key, testClaims(time.Hour)),
+		"none":  sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, testClaims(time.Hour)),
+	}
+	for alg, token := range tokens {
+		if _, err := verifier.Verify(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s token: err = %v, want %v", alg, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestVerifyRejectsHS256WithoutSecret(t *testing.T) {
+	key := generateKey(t)
+
+	verifier, err := NewVerifier(Options{
+		JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{"key-1": &key.PublicKey}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an HS256 token keyed with the public key must not pass as RS256
+	publicKey := key.PublicKey.N.Bytes()
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, "key-1", publicKey, testClaims(time.Hour))); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("HS256 token without a secret: err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyChecksIssuerAndAudience(t *testing.T) {
+	verifier, err := NewVerifier(Options{
+		HMACSecret: testSecret,
+		Issuer:     "https://issuer.example",
+		Audience:   "articles",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := testClaims(time.Hour)
+	claims.Issuer = "https://issuer.example"
+	claims.Audience = jwt.ClaimStrings{"articles"}
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, "", testSecret, claims)); err != nil {
+		t.Errorf("token of the expected issuer and audience: %v", err)
+	}
+
+	claims.Audience = jwt.ClaimStrings{"other"}
+	if _, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, "", testSecret, claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token of another audience: err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestNewVerifierNeedsAKey(t *testing.T) {
+	if _, err := NewVerifier(Options{}); !errors.Is(err, ErrNoKeys) {
+		t.Errorf("err = %v, want %v", err, ErrNoKeys)
+	}
+}
@@ -0,0 +1,97 @@
+// Command token mints JWTs accepted by the gateway, for local testing.
+//
+//	go run ./cmd/token -sub alice -roles editor
+package main
+
+import (
+	"crypto/rsa"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/rest-gateway/auth"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func main() {
+	var (
+		subject  string
+		roles    string
+		ttl      time.Duration
+		secret   string
+		keyFile  string
+		keyID    string
+		issuer   string
+		audience string
+	)
+
+	flag.StringVar(&subject, "sub", "", "subject of the token, the article author")
+	flag.StringVar(&roles, "roles", "", "comma separated roles, e.g. editor,admin")
+	flag.DurationVar(&ttl, "ttl", time.Hour, "lifetime of the token")
+	flag.StringVar(&secret, "secret", os.Getenv("JWT_HS256_SECRET"), "HS256 secret")
+	flag.StringVar(&keyFile, "key", "", "PEM encoded RSA private key, signs with RS256 instead of HS256")
+	flag.StringVar(&keyID, "kid", "", "key id of the RSA key in the JWKS file")
+	flag.StringVar(&issuer, "iss", os.Getenv("JWT_ISSUER"), "issuer")
+	flag.StringVar(&audience, "aud", os.Getenv("JWT_AUDIENCE"), "audience")
+	flag.Parse()
+
+	if subject == "" {
+		log.Fatal("-sub is required")
+	}
+
+	now := time.Now()
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	if audience != "" {
+		claims.Audience = jwt.ClaimStrings{audience}
+	}
+	if roles != "" {
+		claims.Roles = strings.Split(roles, ",")
+	}
+
+	var (
+		signed string
+		err    error
+	)
+	if keyFile != "" {
+		var key *rsa.PrivateKey
+		key, err = readPrivateKey(keyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		if keyID != "" {
+			token.Header["kid"] = keyID
+		}
+		signed, err = token.SignedString(key)
+	} else {
+		if secret == "" {
+			log.Fatal("-secret or JWT_HS256_SECRET is required for HS256")
+		}
+		signed, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(signed)
+}
+
+func readPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.ParseRSAPrivateKeyFromPEM(data)
+}
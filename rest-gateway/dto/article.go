@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Adhiana46/rest-gateway/pb"
+)
+
+type ResponseArticle struct {
+	Uuid      string    `json:"uuid"`
+	Slug      string    `json:"slug"`
+	Author    string    `json:"author"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Tags      []string  `json:"tags"`
+	Category  string    `json:"category"`
+	Status    string    `json:"status"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	CommentCount int64 `json:"comment_count"`
+
+	PublishAt  *time.Time          `json:"publish_at,omitempty"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+type ResponseListArticle struct {
+	Articles   []*ResponseArticle `json:"articles"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
+
+type RequestStoreArticle struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Tags      []string   `json:"tags"`
+	Category  string     `json:"category"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+type RequestUpdateArticle struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Tags      []string   `json:"tags"`
+	Category  string     `json:"category"`
+	PublishAt *time.Time `json:"publish_at"`
+	Version   int        `json:"version"`
+}
+
+type RequestDeleteArticle struct {
+	Version int `json:"version"`
+}
+
+func ArticleFromProto(article *pb.Article) *ResponseArticle {
+	result := &ResponseArticle{
+		Uuid:      article.GetUuid(),
+		Slug:      article.GetSlug(),
+		Author:    article.GetAuthor(),
+		Title:     article.GetTitle(),
+		Body:      article.GetBody(),
+		Tags:      article.GetTags(),
+		Category:  article.GetCategory(),
+		Status:    article.GetStatus(),
+		Version:   int(article.GetVersion()),
+		CreatedAt: article.GetCreatedAt().AsTime(),
+		UpdatedAt: article.GetUpdatedAt().AsTime(),
+
+		CommentCount: article.GetCommentCount(),
+	}
+
+	if article.GetPublishAt() != nil {
+		publishAt := article.GetPublishAt().AsTime()
+		result.PublishAt = &publishAt
+	}
+
+	if len(article.GetHighlights()) > 0 {
+		result.Highlights = map[string][]string{}
+		for field, highlight := range article.GetHighlights() {
+			result.Highlights[field] = highlight.GetFragments()
+		}
+	}
+
+	return result
+}
+
+func ArticleListFromProto(list *pb.GetListArticleResponse) *ResponseListArticle {
+	articles := make([]*ResponseArticle, 0, len(list.GetArticles()))
+	for _, article := range list.GetArticles() {
+		articles = append(articles, ArticleFromProto(article))
+	}
+
+	return &ResponseListArticle{
+		Articles:   articles,
+		Total:      list.GetTotal(),
+		Page:       int(list.GetPage()),
+		Limit:      int(list.GetLimit()),
+		TotalPages: int(list.GetTotalPages()),
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// dialGRPC connects lazily, the backends may start after the gateway.
+func dialGRPC(target string) (*grpc.ClientConn, error) {
+	return grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// outgoingContext bounds the backend call with a deadline and carries the
+// request ids and the verified caller as call metadata.
+func (app *Config) outgoingContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.Context(), grpcTimeout)
+
+	md := metadata.Pairs("x-request-id", middleware.GetReqID(r.Context()))
+	if correlationID := r.Header.Get("X-Correlation-ID"); correlationID != "" {
+		md.Set("x-correlation-id", correlationID)
+	}
+	if claims, ok := claimsFromContext(r.Context()); ok {
+		md.Set("x-auth-subject", claims.Subject)
+		md.Set("x-auth-roles", strings.Join(claims.Roles, ","))
+	}
+	if app.gatewaySecret != "" {
+		md.Set("x-gateway-secret", app.gatewaySecret)
+	}
+
+	return metadata.NewOutgoingContext(ctx, md), cancel
+}
+
+// grpcErrorJSON writes a failed backend call with the HTTP status matching
+// its gRPC code. Field violations of invalid arguments are returned as data.
+func (app *Config) grpcErrorJSON(w http.ResponseWriter, err error) error {
+	st := status.Convert(err)
+
+	var violations []fieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				violations = append(violations, fieldViolation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	payload := jsonResponse{
+		Error:   true,
+		Message: st.Message(),
+	}
+	if len(violations) > 0 {
+		payload.Data = violations
+	}
+
+	return app.writeJSON(w, httpStatusFromCode(st.Code()), payload)
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusUnprocessableEntity
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499 // client closed request
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusInternalServerError
+}
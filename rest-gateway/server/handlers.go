@@ -0,0 +1,242 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Adhiana46/rest-gateway/dto"
+	"github.com/Adhiana46/rest-gateway/pb"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (app *Config) GetArticlesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := app.outgoingContext(r)
+	defer cancel()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	request := &pb.GetListArticleRequest{
+		Page:   int32(page),
+		Limit:  int32(limit),
+		Q:      r.URL.Query().Get("q"),
+		Author: r.URL.Query().Get("author"),
+		Status: r.URL.Query().Get("status"),
+
+		Tag:      r.URL.Query().Get("tag"),
+		TagsAll:  splitList(r.URL.Query().Get("tags_all")),
+		TagsAny:  splitList(r.URL.Query().Get("tags_any")),
+		Category: r.URL.Query().Get("category"),
+	}
+
+	createdFrom, err := readTimeParam(r, "created_from", false)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if !createdFrom.IsZero() {
+		request.CreatedFrom = timestamppb.New(createdFrom)
+	}
+
+	createdTo, err := readTimeParam(r, "created_to", true)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if !createdTo.IsZero() {
+		request.CreatedTo = timestamppb.New(createdTo)
+	}
+
+	response, err := app.queryClient.GetList(ctx, request)
+	if err != nil {
+		app.grpcErrorJSON(w, err)
+		return
+	}
+
+	payload := jsonResponse{
+		Error:   false,
+		Message: response.GetMessage(),
+		Data:    dto.ArticleListFromProto(response),
+	}
+
+	app.writeJSON(w, http.StatusOK, payload)
+}
+
+func (app *Config) GetSingleArticleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := app.outgoingContext(r)
+	defer cancel()
+
+	response, err := app.queryClient.GetSingle(ctx, &pb.GetSingleArticleRequest{
+		Uuid: chi.URLParam(r, "uuid"),
+	})
+	if err != nil {
+		app.grpcErrorJSON(w, err)
+		return
+	}
+
+	payload := jsonResponse{
+		Error:   false,
+		Message: response.GetMessage(),
+		Data:    dto.ArticleFromProto(response.GetArticle()),
+	}
+
+	app.writeJSON(w, http.StatusOK, payload, http.Header{"ETag": {etag(response.GetArticle().GetVersion())}})
+}
+
+// GetArticleBySlugHandler returns the article of a slug. A retired slug is
+// answered with a permanent redirect to the current one.
+func (app *Config) GetArticleBySlugHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := app.outgoingContext(r)
+	defer cancel()
+
+	slug := chi.URLParam(r, "slug")
+
+	response, err := app.queryClient.GetBySlug(ctx, &pb.GetArticleBySlugRequest{
+		Slug: slug,
+	})
+	if err != nil {
+		app.grpcErrorJSON(w, err)
+		return
+	}
+
+	if current := response.GetArticle().GetSlug(); current != slug {
+		location := apiPrefix + "/articles/by-slug/" + url.PathEscape(current)
+		http.Redirect(w, r, location, http.StatusMovedPermanently)
+		return
+	}
+
+	payload := jsonResponse{
+		Error:   false,
+		Message: response.GetMessage(),
+		Data:    dto.ArticleFromProto(response.GetArticle()),
+	}
+
+	app.writeJSON(w, http.StatusOK, payload, http.Header{"ETag": {etag(response.GetArticle().GetVersion())}})
+}
+
+func (app *Config) StoreArticleHandler(w http.ResponseWriter, r *http.Request) {
+	var requestDto dto.RequestStoreArticle
+	if err := app.readJSON(w, r, &requestDto); err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := app.outgoingContext(r)
+	defer cancel()
+
+	if idempotencyKey := r.Header.Get("Idempotency-Key"); idempotencyKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "idempotency-key", idempotencyKey)
+	}
+
+	var header metadata.MD
+	response, err := app.commandClient.Store(ctx, &pb.StoreArticleRequest{
+		Title:     requestDto.Title,
+		Body:      requestDto.Body,
+		Tags:      requestDto.Tags,
+		Category:  requestDto.Category,
+		PublishAt: timestampOrNil(requestDto.PublishAt),
+	}, grpc.Header(&header))
+	if err != nil {
+		app.grpcErrorJSON(w, err)
+		return
+	}
+
+	payload := jsonResponse{
+		Error:   false,
+		Message: response.GetMessage(),
+		Data:    dto.ArticleFromProto(response.GetArticle()),
+	}
+
+	headers := http.Header{"ETag": {etag(response.GetArticle().GetVersion())}}
+	if replayed := header.Get("idempotent-replayed"); len(replayed) > 0 {
+		headers.Set("Idempotent-Replayed", replayed[0])
+	}
+
+	app.writeJSON(w, http.StatusOK, payload, headers)
+}
+
+func (app *Config) UpdateArticleHandler(w http.ResponseWriter, r *http.Request) {
+	var requestDto dto.RequestUpdateArticle
+	if err := app.readJSON(w, r, &requestDto); err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	ctx, cancel := app.outgoingContext(r)
+	defer cancel()
+
+	response, err := app.commandClient.Update(ctx, &pb.UpdateArticleRequest{
+		Uuid:      chi.URLParam(r, "uuid"),
+		Title:     requestDto.Title,
+		Body:      requestDto.Body,
+		Tags:      requestDto.Tags,
+		Category:  requestDto.Category,
+		PublishAt: timestampOrNil(requestDto.PublishAt),
+		Version:   int32(requestDto.Version),
+	})
+	if err != nil {
+		app.grpcErrorJSON(w, err)
+		return
+	}
+
+	payload := jsonResponse{
+		Error:   false,
+		Message: response.GetMessage(),
+		Data:    dto.ArticleFromProto(response.GetArticle()),
+	}
+
+	app.writeJSON(w, http.StatusOK, payload, http.Header{"ETag": {etag(response.GetArticle().GetVersion())}})
+}
+
+func (app *Config) DeleteArticleHandler(w http.ResponseWriter, r *http.Request) {
+	// the body is optional, it only carries the expected version
+	var requestDto dto.RequestDeleteArticle
+	if err := app.readJSON(w, r, &requestDto); err != nil && !errors.Is(err, io.EOF) {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	version, err := readIfMatch(r)
+	if err != nil {
+		app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		requestDto.Version = version
+	}
+
+	ctx, cancel := app.outgoingContext(r)
+	defer cancel()
+
+	response, err := app.commandClient.Delete(ctx, &pb.DeleteArticleRequest{
+		Uuid:    chi.URLParam(r, "uuid"),
+		Version: int32(requestDto.Version),
+	})
+	if err != nil {
+		app.grpcErrorJSON(w, err)
+		return
+	}
+
+	payload := jsonResponse{
+		Error:   false,
+		Message: response.GetMessage(),
+		Data:    dto.ArticleFromProto(response.GetArticle()),
+	}
+
+	app.writeJSON(w, http.StatusOK, payload)
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Adhiana46/rest-gateway/auth"
+)
+
+type claimsKey struct{}
+
+// authenticate rejects requests without a valid bearer token. The verified
+// claims are kept in the request context for the proxy to forward.
+func (app *Config) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+		if token == "" || token == header {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			app.errorJSON(w, auth.ErrMissingToken, http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := app.verifier.Verify(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			app.errorJSON(w, err, http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), claimsKey{}, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticateOptional verifies the bearer token when one is sent, so public
+// reads can show more to an authenticated caller. An invalid token is still
+// rejected.
+func (app *Config) authenticateOptional(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		app.authenticate(next).ServeHTTP(w, r)
+	})
+}
+
+func claimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*auth.Claims)
+	return claims, ok
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/rest-gateway/auth"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var testSecret = []byte("test-secret")
+
+// newTestApp proxies to upstream and verifies HS256 tokens of testSecret.
+func newTestApp(t *testing.T, upstream string) *Config {
+	t.Helper()
+
+	verifier, err := auth.NewVerifier(auth.Options{HMACSecret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Config{
+		AppName:       appName,
+		AppVersion:    appVersion,
+		queryURL:      upstream,
+		commandURL:    upstream,
+		client:        &http.Client{Timeout: upstreamTimeout},
+		verifier:      verifier,
+		gatewaySecret: "gateway-secret",
+	}
+}
+
+func testToken(t *testing.T, expiresIn time.Duration, subject string, roles ...string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+		Roles: roles,
+	})
+
+	signed, err := token.SignedString(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// recordUpstream is an upstream service keeping the headers of the last
+// request it got.
+func recordUpstream(t *testing.T) (*httptest.Server, *http.Header) {
+	t.Helper()
+
+	received := &http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"error":false}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, received
+}
+
+func TestAuthenticateRejectsMissingOrInvalidToken(t *testing.T) {
+	upstream, _ := recordUpstream(t)
+	routes := newTestApp(t, upstream.URL).Routes()
+
+	tests := []struct {
+		name          string
+		authorization string
+		challenge     string
+	}{
+		{"missing", "", "Bearer"},
+		{"other scheme", "Basic YWxpY2U6c2VjcmV0", "Bearer"},
+		{"invalid", "Bearer not-a-token", `Bearer error="invalid_token"`},
+		{"expired", "Bearer " + testToken(t, -time.Minute, "alice"), `Bearer error="invalid_token"`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, apiPrefix+"/articles/article-1/restore", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s token answered %d, want 401", tt.name, rec.Code)
+		}
+		if challenge := rec.Header().Get("WWW-Authenticate"); challenge != tt.challenge {
+			t.Errorf("%s token challenge = %q, want %q", tt.name, challenge, tt.challenge)
+		}
+	}
+}
+
+func TestAuthenticateOptionalRejectsInvalidToken(t *testing.T) {
+	upstream, _ := recordUpstream(t)
+	routes := newTestApp(t, upstream.URL).Routes()
+
+	req := httptest.NewRequest(http.MethodGet, apiPrefix+"/articles/article-1/comments", nil)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("public read with an invalid token answered %d, want 401", rec.Code)
+	}
+}
+
+func TestProxyReplacesClientIdentityHeaders(t *testing.T) {
+	upstream, received := recordUpstream(t)
+	routes := newTestApp(t, upstream.URL).Routes()
+
+	req := httptest.NewRequest(http.MethodPost, apiPrefix+"/articles/article-1/restore", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken(t, time.Hour, "alice", "author"))
+	req.Header.Set("X-Auth-Subject", "mallory")
+	req.Header.Set("X-Auth-Roles", "admin")
+	req.Header.Set("X-Gateway-Secret", "forged-secret")
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("restore answered %d", rec.Code)
+	}
+	if subject := received.Values("X-Auth-Subject"); len(subject) != 1 || subject[0] != "alice" {
+		t.Errorf("upstream subject = %v, want the token subject alice", subject)
+	}
+	if roles := received.Values("X-Auth-Roles"); len(roles) != 1 || roles[0] != "author" {
+		t.Errorf("upstream roles = %v, want the token roles", roles)
+	}
+	if secret := received.Values("X-Gateway-Secret"); len(secret) != 1 || secret[0] != "gateway-secret" {
+		t.Errorf("upstream gateway secret = %v, want the configured one", secret)
+	}
+}
+
+func TestProxyDropsIdentityHeadersOfAnonymousCaller(t *testing.T) {
+	upstream, received := recordUpstream(t)
+	routes := newTestApp(t, upstream.URL).Routes()
+
+	req := httptest.NewRequest(http.MethodGet, apiPrefix+"/articles/article-1/comments", nil)
+	req.Header.Set("X-Auth-Subject", "mallory")
+	req.Header.Set("X-Auth-Roles", "admin")
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("comments answered %d", rec.Code)
+	}
+	if subject, roles := received.Get("X-Auth-Subject"), received.Get("X-Auth-Roles"); subject != "" || roles != "" {
+		t.Errorf("upstream got subject %q with roles %q, want no identity", subject, roles)
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// forwardedRequestHeaders are the client headers the upstream services act on.
+var forwardedRequestHeaders = []string{
+	"Authorization",
+	"Content-Type",
+	"Accept",
+	"If-Match",
+	"Idempotency-Key",
+	"X-Correlation-ID",
+}
+
+// forwardedResponseHeaders are the upstream headers handed back to the client.
+var forwardedResponseHeaders = []string{
+	"Content-Type",
+	"ETag",
+	"Location",
+	"Idempotent-Replayed",
+	"X-Correlation-ID",
+}
+
+// proxy forwards the request to the upstream service with its path below the
+// api prefix and its query string. The upstream status code and body are
+// returned to the client unchanged.
+func (app *Config) proxy(upstream string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+
+		url := upstream + path
+		if r.URL.RawQuery != "" {
+			url += "?" + r.URL.RawQuery
+		}
+
+		request, err := http.NewRequestWithContext(r.Context(), r.Method, url, r.Body)
+		if err != nil {
+			app.errorJSON(w, err)
+			return
+		}
+
+		for _, name := range forwardedRequestHeaders {
+			if values := r.Header.Values(name); len(values) > 0 {
+				request.Header[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+		// the upstream services log and trace by the same request id
+		request.Header.Set("X-Request-Id", middleware.GetReqID(r.Context()))
+
+		// the identity headers are only ever set from a verified token, the
+		// services trust them along with the shared gateway secret
+		if claims, ok := claimsFromContext(r.Context()); ok {
+			request.Header.Set("X-Auth-Subject", claims.Subject)
+			request.Header.Set("X-Auth-Roles", strings.Join(claims.Roles, ","))
+		}
+		if app.gatewaySecret != "" {
+			request.Header.Set("X-Gateway-Secret", app.gatewaySecret)
+		}
+
+		response, err := app.client.Do(request)
+		if err != nil {
+			log.Printf("Error calling %s %s: %s", r.Method, url, err)
+			app.errorJSON(w, errors.New("upstream service is unavailable"), http.StatusBadGateway)
+			return
+		}
+		defer response.Body.Close()
+
+		for _, name := range forwardedResponseHeaders {
+			if values := response.Header.Values(name); len(values) > 0 {
+				w.Header()[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+		w.Header().Set("X-Request-Id", request.Header.Get("X-Request-Id"))
+
+		w.WriteHeader(response.StatusCode)
+		if _, err := io.Copy(w, response.Body); err != nil {
+			log.Printf("Error copying response of %s %s: %s", r.Method, url, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+)
+
+func (app *Config) Routes() http.Handler {
+	mux := chi.NewRouter()
+
+	mux.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   []string{"https://*", "http://*"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "Idempotency-Key", "X-Request-Id", "X-Correlation-ID"},
+		ExposedHeaders:   []string{"Link", "ETag", "Location", "Idempotent-Replayed", "X-Request-Id", "X-Correlation-ID"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}))
+
+	mux.Use(middleware.Heartbeat("/ping"))
+	mux.Use(middleware.RequestID)
+
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		payload := jsonResponse{
+			Error:   false,
+			Message: fmt.Sprintf("Welcome to %s version %s", app.AppName, app.AppVersion),
+		}
+
+		_ = app.writeJSON(w, http.StatusOK, payload)
+	})
+
+	// Articles
+	mux.Route(apiPrefix+"/articles", func(r chi.Router) {
+		// reads are public, unpublished articles are only shown to their
+		// author and editors
+		r.Group(func(r chi.Router) {
+			r.Use(app.authenticateOptional)
+
+			r.Get("/", app.GetArticlesHandler)
+			r.Get("/{uuid}", app.GetSingleArticleHandler)
+			r.Get("/by-slug/{slug}", app.GetArticleBySlugHandler)
+
+			// revision history
+			r.Get("/{uuid}/revisions", app.proxy(app.queryURL))
+			r.Get("/{uuid}/revisions/{revision}", app.proxy(app.queryURL))
+			r.Get("/{uuid}/diff", app.proxy(app.queryURL))
+
+			// comment threads
+			r.Get("/{uuid}/comments", app.proxy(app.queryURL))
+		})
+
+		// writes need an authenticated caller
+		r.Group(func(r chi.Router) {
+			r.Use(app.authenticate)
+
+			r.Post("/", app.StoreArticleHandler)
+			r.Put("/{uuid}", app.UpdateArticleHandler)
+			r.Delete("/{uuid}", app.DeleteArticleHandler)
+
+			r.Post("/bulk", app.proxy(app.commandURL))
+
+			// trash
+			r.Get("/trash", app.proxy(app.queryURL))
+			r.Post("/{uuid}/restore", app.proxy(app.commandURL))
+
+			r.Post("/{uuid}/revert/{revision}", app.proxy(app.commandURL))
+
+			// editorial workflow
+			r.Post("/{uuid}/submit", app.proxy(app.commandURL))
+			r.Post("/{uuid}/publish", app.proxy(app.commandURL))
+			r.Post("/{uuid}/archive", app.proxy(app.commandURL))
+
+			// scheduled publishing
+			r.Post("/{uuid}/schedule", app.proxy(app.commandURL))
+			r.Delete("/{uuid}/schedule", app.proxy(app.commandURL))
+
+			r.Post("/{uuid}/comments", app.proxy(app.commandURL))
+		})
+	})
+
+	// Comments, editing and deleting is left to their author and editors,
+	// moderating to editors
+	mux.Route(apiPrefix+"/comments", func(r chi.Router) {
+		r.Use(app.authenticate)
+
+		r.Put("/{uuid}", app.proxy(app.commandURL))
+		r.Delete("/{uuid}", app.proxy(app.commandURL))
+		r.Post("/{uuid}/moderate", app.proxy(app.commandURL))
+	})
+
+	// tag and category facets with their article counts
+	mux.Get(apiPrefix+"/tags", app.proxy(app.queryURL))
+	mux.Get(apiPrefix+"/categories", app.proxy(app.queryURL))
+
+	return mux
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Adhiana46/rest-gateway/auth"
+	"github.com/Adhiana46/rest-gateway/pb"
+	"google.golang.org/grpc"
+)
+
+const (
+	appName    = "REST-Gateway"
+	appVersion = "1.0"
+	port       = "80"
+
+	apiPrefix       = "/api/v1"
+	upstreamTimeout = 30 * time.Second
+	grpcTimeout     = 10 * time.Second
+)
+
+type Config struct {
+	AppName    string
+	AppVersion string
+
+	queryURL   string
+	commandURL string
+	client     *http.Client
+	verifier   *auth.Verifier
+
+	// shared with the services, they only trust the identity headers of
+	// requests carrying it
+	gatewaySecret string
+
+	commandConn   *grpc.ClientConn
+	queryConn     *grpc.ClientConn
+	commandClient pb.ArticleCommandClient
+	queryClient   pb.ArticleQueryClient
+}
+
+// Options are the upstream services of the gateway and the keys it checks
+// and forwards callers with.
+type Options struct {
+	QueryURL      string // query-service HTTP API
+	CommandURL    string // command-service HTTP API
+	GRPCQuery     string // query-service gRPC target
+	GRPCCommand   string // command-service gRPC target
+	Verifier      *auth.Verifier
+	GatewaySecret string // sent to the services along with the caller
+}
+
+// New connects the gateway to the gRPC APIs of the services. Close releases
+// the connections.
+func New(opts Options) (*Config, error) {
+	app := &Config{
+		AppName:       appName,
+		AppVersion:    appVersion,
+		queryURL:      opts.QueryURL,
+		commandURL:    opts.CommandURL,
+		client:        &http.Client{Timeout: upstreamTimeout},
+		verifier:      opts.Verifier,
+		gatewaySecret: opts.GatewaySecret,
+	}
+
+	commandConn, err := dialGRPC(opts.GRPCCommand)
+	if err != nil {
+		return nil, fmt.Errorf("connect to command-service: %w", err)
+	}
+	app.commandConn = commandConn
+	app.commandClient = pb.NewArticleCommandClient(commandConn)
+
+	queryConn, err := dialGRPC(opts.GRPCQuery)
+	if err != nil {
+		commandConn.Close()
+		return nil, fmt.Errorf("connect to query-service: %w", err)
+	}
+	app.queryConn = queryConn
+	app.queryClient = pb.NewArticleQueryClient(queryConn)
+
+	return app, nil
+}
+
+func (app *Config) Close() {
+	app.commandConn.Close()
+	app.queryConn.Close()
+}
+
+// Run serves the gateway configured from the environment until the HTTP
+// server fails.
+func Run() {
+	verifier, err := auth.NewVerifier(auth.Options{
+		HMACSecret: []byte(os.Getenv("JWT_HS256_SECRET")),
+		JWKSFile:   os.Getenv("JWT_JWKS_FILE"),
+		Issuer:     os.Getenv("JWT_ISSUER"),
+		Audience:   os.Getenv("JWT_AUDIENCE"),
+	})
+	if err != nil {
+		log.Panicf("Can't load JWT keys: %s", err)
+	}
+
+	// gRPC backends
+	app, err := New(Options{
+		QueryURL:      os.Getenv("URL_QUERY_SVC"),
+		CommandURL:    os.Getenv("URL_COMMAND_SVC"),
+		GRPCQuery:     os.Getenv("GRPC_QUERY_SVC"),
+		GRPCCommand:   os.Getenv("GRPC_COMMAND_SVC"),
+		Verifier:      verifier,
+		GatewaySecret: os.Getenv("GATEWAY_SECRET"),
+	})
+	if err != nil {
+		log.Panicf("Can't %s", err)
+	}
+	defer app.Close()
+
+	log.Printf("Starting %s service on port %s\n", appName, port)
+
+	s := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: app.Routes(),
+	}
+
+	// starting the server
+	if err := s.ListenAndServe(); err != nil {
+		log.Panic(err)
+	}
+}